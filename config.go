@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"path/filepath"
 	"strings"
 
 	"ted/internal/dblib"
@@ -22,10 +23,19 @@ type Config struct {
 	Password string
 	Command  string
 	Where    string
+	Goto     string
 	OrderBy  string
 	// DBTypeOverride allows explicitly selecting the database type via flags
 	DBTypeOverride *dblib.DatabaseType
 	VimMode        bool
+	// PinKeys pins the key columns at the left during horizontal scrolling
+	PinKeys bool
+	// DryRun collects writes into a SQL script instead of executing them
+	DryRun bool
+	// CascadeDepth limits how many levels of ON DELETE CASCADE the delete preview follows
+	CascadeDepth int
+	// Theme colors the editor, the default dark theme when nil
+	Theme *Theme
 }
 
 var databaseIcons = map[dblib.DatabaseType]string{
@@ -119,6 +129,41 @@ func (c *Config) buildConnectionString() (string, dblib.DatabaseType, error) {
 	}
 }
 
+// connectionLabel identifies the connection without its password, e.g. for the journal
+func (c *Config) connectionLabel() string {
+	switch c.detectDatabaseType() {
+	case dblib.SQLite:
+		if abs, err := filepath.Abs(c.Database); err == nil {
+			return "sqlite:" + abs
+		}
+		return "sqlite:" + c.Database
+	case dblib.DuckDB:
+		if abs, err := filepath.Abs(c.Database); err == nil {
+			return "duckdb:" + abs
+		}
+		return "duckdb:" + c.Database
+	}
+
+	scheme := "postgres"
+	if c.detectDatabaseType() == dblib.MySQL {
+		scheme = "mysql"
+	}
+	host := c.Host
+	if host == "" {
+		host = "localhost"
+	}
+	if c.Port != "" {
+		host += ":" + c.Port
+	}
+	userName := c.Username
+	if userName == "" {
+		if currentUser, err := user.Current(); err == nil {
+			userName = currentUser.Username
+		}
+	}
+	return fmt.Sprintf("%s://%s@%s/%s", scheme, userName, host, c.Database)
+}
+
 func (c *Config) connect() (*sql.DB, dblib.DatabaseType, error) {
 	connStr, dbType, err := c.buildConnectionString()
 	if err != nil {
@@ -128,7 +173,7 @@ func (c *Config) connect() (*sql.DB, dblib.DatabaseType, error) {
 	var driverName string
 	switch dbType {
 	case dblib.SQLite:
-		driverName = "sqlite3"
+		driverName = dblib.SQLiteDriverName
 	case dblib.PostgreSQL:
 		driverName = "postgres"
 	case dblib.MySQL:
@@ -69,8 +69,8 @@ func formatTableNameWithColor(table string, positions []int) string {
 	runes := []rune(table)
 	for i, r := range runes {
 		if highlightMap[i] {
-			// Bold and dark green for matches
-			result.WriteString("[darkgreen::b]")
+			// Bold in the theme's match color
+			result.WriteString(styleTag(tcell.StyleDefault.Foreground(theme.Match).Bold(true)))
 			result.WriteRune(r)
 			result.WriteString("[-::-]")
 		} else {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -81,6 +82,10 @@ func buildColumnsFromAnalysis(db *sql.DB, dbType DatabaseType, analysis *ViewAna
 					col.Nullable = baseCol.Nullable
 					col.EnumValues = baseCol.EnumValues
 					col.CustomTypeName = baseCol.CustomTypeName
+					col.Generated = baseCol.Generated
+					col.Default = baseCol.Default
+					col.HasDefault = baseCol.HasDefault
+					col.AutoIncrement = baseCol.AutoIncrement
 				}
 			}
 		}
@@ -601,7 +606,12 @@ func (r *Relation) IsColumnEditable(colIdx int) bool {
 		return true
 	}
 
-	// All table columns are editable
+	// Generated columns are computed by the database
+	if col.Generated {
+		return false
+	}
+
+	// All other table columns are editable
 	if !r.IsView {
 		return true
 	}
@@ -631,8 +641,9 @@ func (r *Relation) IsColumnEditable(colIdx int) bool {
 	return true
 }
 
-// updateViewColumn updates a column in a view by updating the base table
-func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName string, newValue string) ([]any, error) {
+// updateViewColumn updates a column in a view by updating the base table.
+// String values are converted using the base column type; other values are bound as-is.
+func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName string, newValue any) ([]any, error) {
 	// Find the column
 	colIdx, ok := rel.ColumnIndex[colName]
 	if !ok || colIdx >= len(rel.Columns) {
@@ -677,13 +688,16 @@ func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName strin
 	// For now, use a direct UPDATE query
 	whereParts := make([]string, 0, len(baseRel.Key))
 	whereParams := make([]any, 0, len(baseRel.Key))
+	keyCols := make([]Column, 0, len(baseRel.Key))
 	for i, keyIdx := range baseRel.Key {
 		if keyIdx < len(baseRel.Columns) {
 			keyCol := baseRel.Columns[keyIdx]
 			whereParts = append(whereParts, fmt.Sprintf("%s = ?", quoteIdent(rel.DBType, keyCol.Name)))
 			whereParams = append(whereParams, baseKeyValues[i])
+			keyCols = append(keyCols, keyCol)
 		}
 	}
+	before := rel.storedImage(baseRel.Name, keyCols, whereParams, baseRowImage(rel.Columns, col.Table, records[rowIdx]))
 
 	// Convert newValue to appropriate type
 	toDBValue := func(colName, raw string) any {
@@ -721,7 +735,10 @@ func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName strin
 		}
 	}
 
-	valueArg := toDBValue(baseColName, newValue)
+	valueArg := newValue
+	if raw, ok := newValue.(string); ok {
+		valueArg = toDBValue(baseColName, raw)
+	}
 
 	// Build UPDATE query
 	placeholder := func(i int) string {
@@ -780,6 +797,8 @@ func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName strin
 			return nil, fmt.Errorf("update failed: %w", err)
 		}
 
+		rel.notifyWrite("UPDATE", baseRel.Name, query, args, before, rowImage(baseRel.Columns, rowVals))
+
 		// Map base table columns back to view columns
 		viewRow := make([]any, len(rel.Columns))
 		for i, viewCol := range rel.Columns {
@@ -830,6 +849,8 @@ func (rel *Relation) updateViewColumn(records [][]any, rowIdx int, colName strin
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
 
+	rel.notifyWrite("UPDATE", baseRel.Name, query, args, before, rowImage(baseRel.Columns, rowVals))
+
 	// Map base table columns back to view columns
 	viewRow := make([]any, len(rel.Columns))
 	for i, viewCol := range rel.Columns {
@@ -891,8 +912,8 @@ func (rel *Relation) formatLiteral(val any, attrType string) string {
 			return "NULL"
 		}
 		// For non-numeric types, quote and escape
-		if strings.Contains(at, "int") || strings.Contains(at, "real") || strings.Contains(at, "double") ||
-			strings.Contains(at, "float") || strings.Contains(at, "numeric") || strings.Contains(at, "decimal") {
+		if v != "" && (strings.Contains(at, "int") || strings.Contains(at, "real") || strings.Contains(at, "double") ||
+			strings.Contains(at, "float") || strings.Contains(at, "numeric") || strings.Contains(at, "decimal")) {
 			return v
 		}
 		s := strings.ReplaceAll(v, "'", "''")
@@ -906,35 +927,21 @@ func (rel *Relation) formatLiteral(val any, attrType string) string {
 
 // BuildInsertPreview constructs a SQL INSERT statement as a string with literal
 // values inlined for preview purposes. Intended only for UI preview.
+// newRecordRow holds a value per relation column, of which columns are inserted.
 func (rel *Relation) BuildInsertPreview(newRecordRow []any, columns []DisplayColumn) string {
-
-	// Check if all values are nil/empty
-	hasNonNullValue := false
-	for _, val := range newRecordRow {
-		if val != nil {
-			hasNonNullValue = true
-			break
-		}
-	}
-
-	// Return empty string if all values are nil/empty
-	if !hasNonNullValue {
-		return ""
-	}
-
 	// Build column list and values list
 	var cols []string
 	var vals []string
-	for i, column := range columns {
+	for _, column := range columns {
 		colIdx, ok := rel.ColumnIndex[column.Name]
 		if !ok || colIdx >= len(rel.Columns) {
 			continue
 		}
 		col := rel.Columns[colIdx]
-		// nil means no update
-		if newRecordRow[i] != EmptyCellValue {
+		// Unset and generated columns are left to the database
+		if colIdx < len(newRecordRow) && newRecordRow[colIdx] != EmptyCellValue && !col.Generated {
 			cols = append(cols, quoteIdent(rel.DBType, column.Name))
-			vals = append(vals, rel.formatLiteral(newRecordRow[i], col.Type))
+			vals = append(vals, rel.formatLiteral(newRecordRow[colIdx], col.Type))
 		}
 	}
 
@@ -1063,57 +1070,51 @@ func (rel *Relation) BuildDeletePreview(records [][]any, rowIdx int) string {
 	if rowIdx < 0 || rowIdx >= len(records) || len(rel.Key) == 0 {
 		return ""
 	}
+	table, keyCols, keyVals, err := rel.deleteKey(records[rowIdx])
+	if err != nil {
+		return ""
+	}
 
 	// Where clause with literal values
-	whereParts := make([]string, 0, len(rel.Key))
-	for _, keyIdx := range rel.Key {
-		if keyIdx >= len(rel.Columns) {
-			return ""
-		}
-		keyCol := rel.Columns[keyIdx]
+	whereParts := make([]string, 0, len(keyCols))
+	for i, keyCol := range keyCols {
 		qKeyName := quoteIdent(rel.DBType, keyCol.Name)
-		if rowIdx >= len(records) {
-			return ""
-		}
-		row := records[rowIdx]
-		if keyIdx < 0 || keyIdx >= len(row) {
-			return ""
-		}
-		whereParts = append(whereParts, fmt.Sprintf("%s = %s", qKeyName, rel.formatLiteral(row[keyIdx], keyCol.Type)))
+		whereParts = append(whereParts, fmt.Sprintf("%s = %s", qKeyName, rel.formatLiteral(keyVals[i], keyCol.Type)))
 	}
 
-	quotedTable := quoteQualified(rel.DBType, rel.Name)
+	quotedTable := quoteQualified(rel.DBType, table)
 	return fmt.Sprintf("DELETE FROM %s WHERE %s", quotedTable, strings.Join(whereParts, " AND "))
 }
 
 // DeleteDBRecord deletes a record from the database based on its key values.
+// For views and custom SQL, the row is deleted from the single base table.
 func (rel *Relation) DeleteDBRecord(records [][]any, rowIdx int) error {
 	if rowIdx < 0 || rowIdx >= len(records) || len(rel.Key) == 0 {
 		return fmt.Errorf("invalid row index or no key columns")
 	}
+	table, keyCols, keyVals, err := rel.deleteKey(records[rowIdx])
+	if err != nil {
+		return err
+	}
 
 	// Build WHERE clause using key columns
-	whereParts := make([]string, 0, len(rel.Key))
-	whereParams := make([]any, 0, len(rel.Key))
-	for _, keyIdx := range rel.Key {
-		if keyIdx >= len(rel.Columns) {
-			return fmt.Errorf("key column index %d out of range", keyIdx)
-		}
-		keyCol := rel.Columns[keyIdx]
+	whereParts := make([]string, 0, len(keyCols))
+	for i, keyCol := range keyCols {
 		qKeyName := quoteIdent(rel.DBType, keyCol.Name)
-		row := records[rowIdx]
-		if keyIdx < 0 || keyIdx >= len(row) {
-			return fmt.Errorf("key column %s index out of range", keyCol.Name)
-		}
-		whereParts = append(whereParts, fmt.Sprintf("%s = ?", qKeyName))
-		whereParams = append(whereParams, row[keyIdx])
+		whereParts = append(whereParts, fmt.Sprintf("%s = %s", qKeyName, rel.placeholder(i+1)))
 	}
 
-	quotedTable := quoteQualified(rel.DBType, rel.Name)
+	quotedTable := quoteQualified(rel.DBType, table)
 	deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE %s", quotedTable, strings.Join(whereParts, " AND "))
 
+	loaded := rowImage(rel.Columns, records[rowIdx])
+	if rel.IsView || rel.IsCustomSQL {
+		loaded = baseRowImage(rel.Columns, table, records[rowIdx])
+	}
+	before := rel.storedImage(table, keyCols, keyVals, loaded)
+
 	// Execute the DELETE
-	result, err := rel.DB.Exec(deleteSQL, whereParams...)
+	result, err := rel.DB.Exec(deleteSQL, keyVals...)
 	if err != nil {
 		return err
 	}
@@ -1125,10 +1126,44 @@ func (rel *Relation) DeleteDBRecord(records [][]any, rowIdx int) error {
 	if rowsAffected == 0 {
 		return fmt.Errorf("no rows were deleted")
 	}
+	rel.notifyWrite("DELETE", table, deleteSQL, keyVals, before, nil)
 
 	return nil
 }
 
+// deleteKey returns the table a row is deleted from, along with the key columns
+// and values identifying the row there. Views and custom SQL resolve to their
+// single base table.
+func (rel *Relation) deleteKey(row []any) (string, []Column, []any, error) {
+	if !rel.IsView && !rel.IsCustomSQL {
+		keyCols := make([]Column, 0, len(rel.Key))
+		keyVals := make([]any, 0, len(rel.Key))
+		for _, keyIdx := range rel.Key {
+			if keyIdx < 0 || keyIdx >= len(rel.Columns) || keyIdx >= len(row) {
+				return "", nil, nil, fmt.Errorf("key column index %d out of range", keyIdx)
+			}
+			keyCols = append(keyCols, rel.Columns[keyIdx])
+			keyVals = append(keyVals, row[keyIdx])
+		}
+		return rel.Name, keyCols, keyVals, nil
+	}
+
+	baseRel, table, err := rel.writableBase()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	keyCols := make([]Column, 0, len(baseRel.Key))
+	keyVals := make([]any, 0, len(baseRel.Key))
+	for i, keyIdx := range baseRel.Key {
+		if table.Key[i] >= len(row) {
+			return "", nil, nil, fmt.Errorf("key column %s out of range", baseRel.Columns[keyIdx].Name)
+		}
+		keyCols = append(keyCols, baseRel.Columns[keyIdx])
+		keyVals = append(keyVals, row[table.Key[i]])
+	}
+	return baseRel.Name, keyCols, keyVals, nil
+}
+
 // selectInsertedRowByKeys selects a row from the database using key values from newRecordRow.
 // This is used when RETURNING is not supported or LastInsertId is unavailable.
 // Key values are type-converted before being used in the WHERE clause.
@@ -1146,7 +1181,7 @@ func (rel *Relation) selectInsertedRowByKeys(tx *sql.Tx, newRecordRow []any, ret
 		if colIdx < len(rel.Columns) {
 			attrType = strings.ToLower(rel.Columns[colIdx].Type)
 		}
-		if strVal == NullGlyph || strVal == "" {
+		if strVal == NullGlyph {
 			return nil
 		}
 		t := attrType
@@ -1214,12 +1249,19 @@ func (rel *Relation) selectInsertedRowByKeys(tx *sql.Tx, newRecordRow []any, ret
 
 // InsertDBRecord inserts a new record into the database. It returns the inserted
 // row values ordered by relation.Columns. The newRecordRow should contain
-// values for all columns (or nil/NullGlyph for NULL values).
+// values for all columns: EmptyCellValue leaves the column out of the INSERT so
+// the database applies its DEFAULT, nil/NullGlyph inserts an explicit NULL and
+// "" inserts an empty string. Generated columns are always left out.
 func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 	if len(newRecordRow) != len(rel.Columns) {
 		return nil, fmt.Errorf("newRecordRow length mismatch: expected %d, got %d", len(rel.Columns), len(newRecordRow))
 	}
 
+	// Views and custom SQL insert into their base table
+	if rel.IsView || rel.IsCustomSQL {
+		return rel.insertViewRecord(newRecordRow)
+	}
+
 	// For multi-column keys, validate that all key values are present
 	// (multi-column keys cannot use LastInsertId since only single auto-increment columns can be auto-generated)
 	if len(rel.Key) > 1 {
@@ -1240,7 +1282,7 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 		if colIdx < len(rel.Columns) {
 			attrType = strings.ToLower(rel.Columns[colIdx].Type)
 		}
-		if raw == NullGlyph || raw == "" {
+		if raw == NullGlyph {
 			return nil
 		}
 		t := attrType
@@ -1296,14 +1338,8 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 			dbVal = val
 		}
 
-		// Skip NULL/empty values ONLY if column is nullable AND has a default
-		// For now, we include all NOT NULL columns even if nil (let DB handle constraint violations)
-		// We skip nullable columns with nil values (let DB use defaults)
-		if (dbVal == nil || dbVal == "" || dbVal == NullGlyph) && col.Nullable {
-			continue
-		}
-
-		if dbVal == EmptyCellValue {
+		// Unset values fall back to the column DEFAULT; generated columns can't be written
+		if dbVal == EmptyCellValue || col.Generated {
 			continue
 		}
 		cols = append(cols, quoteIdent(rel.DBType, col.Name))
@@ -1339,6 +1375,7 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 			if err := rel.DB.QueryRow(query).Scan(scanArgs...); err != nil {
 				return nil, fmt.Errorf("insert failed: %w", err)
 			}
+			rel.notifyWrite("INSERT", rel.Name, query, nil, nil, rowImage(rel.Columns, rowVals))
 			return rowVals, nil
 		}
 
@@ -1399,6 +1436,7 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 		if err := tx.Commit(); err != nil {
 			return nil, fmt.Errorf("commit failed: %w", err)
 		}
+		rel.notifyWrite("INSERT", rel.Name, query, nil, nil, rowImage(rel.Columns, rowVals))
 		return rowVals, nil
 	}
 
@@ -1416,6 +1454,7 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 		if err := rel.DB.QueryRow(query, args...).Scan(scanArgs...); err != nil {
 			return nil, fmt.Errorf("insert failed: %w", err)
 		}
+		rel.notifyWrite("INSERT", rel.Name, query, args, nil, rowImage(rel.Columns, rowVals))
 		return rowVals, nil
 	}
 
@@ -1477,6 +1516,7 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
+	rel.notifyWrite("INSERT", rel.Name, query, args, nil, rowImage(rel.Columns, rowVals))
 	return rowVals, nil
 }
 
@@ -1486,6 +1526,19 @@ func (rel *Relation) InsertDBRecord(newRecordRow []any) ([]any, error) {
 // an error.
 // For views, this updates the base table column.
 func (rel *Relation) UpdateDBValue(records [][]any, rowIdx int, colName string, newValue string) ([]any, error) {
+	return rel.updateDBValue(records, rowIdx, colName, newValue)
+}
+
+// UpdateDBBytes updates a single cell with raw binary data bound as a parameter,
+// bypassing the text conversion done by UpdateDBValue. Used to load files into
+// blob/bytea columns.
+func (rel *Relation) UpdateDBBytes(records [][]any, rowIdx int, colName string, data []byte) ([]any, error) {
+	return rel.updateDBValue(records, rowIdx, colName, data)
+}
+
+// updateDBValue implements UpdateDBValue and UpdateDBBytes. String values are
+// converted according to the column type; other values are bound as-is.
+func (rel *Relation) updateDBValue(records [][]any, rowIdx int, colName string, newValue any) ([]any, error) {
 	if rowIdx < 0 || rowIdx >= len(records) {
 		return nil, fmt.Errorf("index out of range")
 	}
@@ -1545,8 +1598,12 @@ func (rel *Relation) UpdateDBValue(records [][]any, rowIdx int, colName string,
 	}
 
 	// Build SET and WHERE clauses and args
-	valueArg := toDBValue(colName, newValue)
+	valueArg := newValue
+	if raw, ok := newValue.(string); ok {
+		valueArg = toDBValue(colName, raw)
+	}
 	keyArgs := make([]any, 0, len(rel.Key))
+	keyCols := make([]Column, 0, len(rel.Key))
 	whereParts := make([]string, 0, len(rel.Key))
 	for i, keyIdx := range rel.Key {
 		if keyIdx >= len(rel.Columns) {
@@ -1571,7 +1628,9 @@ func (rel *Relation) UpdateDBValue(records [][]any, rowIdx int, colName string,
 			return nil, err
 		}
 		keyArgs = append(keyArgs, row[keyIdx])
+		keyCols = append(keyCols, keyCol)
 	}
+	before := rel.storedImage(rel.Name, keyCols, keyArgs, rowImage(rel.Columns, records[rowIdx]))
 
 	// SET clause placeholder
 	var setClause string
@@ -1622,6 +1681,7 @@ func (rel *Relation) UpdateDBValue(records [][]any, rowIdx int, colName string,
 		if err := rel.DB.QueryRow(query, args...).Scan(scanArgs...); err != nil {
 			return nil, fmt.Errorf("update failed: %w", err)
 		}
+		rel.notifyWrite("UPDATE", rel.Name, query, args, before, rowImage(rel.Columns, rowVals))
 		return rowVals, nil
 	}
 
@@ -1666,15 +1726,22 @@ func (rel *Relation) UpdateDBValue(records [][]any, rowIdx int, colName string,
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit failed: %w", err)
 	}
+	rel.notifyWrite("UPDATE", rel.Name, query, args, before, rowImage(rel.Columns, rowVals))
 	return rowVals, nil
 }
 
 // QueryRows executes a SELECT for the given columns and clauses, returning the
 // resulting row cursor. Callers are responsible for closing the returned rows.
-func (rel *Relation) QueryRows(columns []string, sortCol *SortColumn, params []any, inclusive, scrollDown bool) (*sql.Rows, error) {
+// params position the query at a row: its key, or when sorting, optionally its
+// sort values followed by its key. Only rows matching the relation's Filter and
+// QuickFilters are returned.
+func (rel *Relation) QueryRows(columns []string, sortCols []SortColumn, params []any, inclusive, scrollDown bool) (*sql.Rows, error) {
+	if len(sortCols) > 0 || (rel.IsCustomSQL && rel.HasFilter()) {
+		return rel.querySortedRows(columns, sortCols, params, inclusive, scrollDown)
+	}
 	// Custom SQL uses different query building
 	if rel.IsCustomSQL {
-		return rel.queryRowsCustomSQL(columns, sortCol, params, inclusive, scrollDown)
+		return rel.queryRowsCustomSQL(columns, params, inclusive, scrollDown)
 	}
 
 	// Convert key indices to column names
@@ -1683,12 +1750,15 @@ func (rel *Relation) QueryRows(columns []string, sortCol *SortColumn, params []a
 		keyColNames[i] = rel.Columns[keyIdx].Name
 	}
 
-	query, err := selectQuery(rel.DBType, rel.Name, columns, sortCol, keyColNames, len(params) > 0, inclusive, scrollDown)
+	// Filter values are bound after the key params
+	args := &queryArgs{dbType: rel.DBType, values: append([]any{}, params...)}
+	filter := rel.filterPredicate(args)
+	query, err := selectQuery(rel.DBType, rel.Name, columns, nil, keyColNames, filter, len(params) > 0, inclusive, scrollDown)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := rel.DB.Query(query, params...)
+	rows, err := rel.DB.Query(query, args.values...)
 	if err != nil {
 		return nil, err
 	}
@@ -1696,7 +1766,7 @@ func (rel *Relation) QueryRows(columns []string, sortCol *SortColumn, params []a
 }
 
 // queryRowsCustomSQL builds and executes a paginated query for custom SQL
-func (rel *Relation) queryRowsCustomSQL(columns []string, sortCol *SortColumn, params []any, inclusive, scrollDown bool) (*sql.Rows, error) {
+func (rel *Relation) queryRowsCustomSQL(columns []string, params []any, inclusive, scrollDown bool) (*sql.Rows, error) {
 	// Convert key indices to column names
 	keyColNames := make([]string, len(rel.Key))
 	for i, keyIdx := range rel.Key {
@@ -1750,12 +1820,6 @@ func (rel *Relation) queryRowsCustomSQL(columns []string, sortCol *SortColumn, p
 
 	// ORDER BY clause
 	builder.WriteString(" ORDER BY ")
-	if sortCol != nil {
-		quotedSortCol := SortColumn{Name: quoteIdent(rel.DBType, sortCol.Name), Asc: sortCol.Asc}
-		sortColString := quotedSortCol.String(scrollDown)
-		builder.WriteString(sortColString)
-		builder.WriteString(", ")
-	}
 	for i, colName := range keyColNames {
 		quotedCol := quoteIdent(rel.DBType, colName)
 		sc := SortColumn{Name: quotedCol, Asc: scrollDown}
@@ -1780,160 +1844,224 @@ func (rel *Relation) placeholder(pos int) string {
 	return "?"
 }
 
-// FindNextRow searches for the next row matching findColVal in the column at index findCol.
-// It searches below the current selection first, then wraps around to search from the top.
+// FindNextRow searches for the next row whose column at index findCol equals findColVal,
+// in the order given by sortCols and then the key. sortVals are the current row's values
+// for sortCols. It searches below the current selection first, then wraps around to
+// search above it. Rows outside the relation's filters are skipped.
 // Returns: (keys of found row, true if found below/false if wrapped, error)
-func (rel *Relation) FindNextRow(findCol int, findColVal any, sortCol *SortColumn, sortColVal any, currentKeys []any) ([]any, bool, error) {
-	if findCol < 0 || findCol >= len(rel.Columns) {
-		return nil, false, fmt.Errorf("findCol index out of range")
+func (rel *Relation) FindNextRow(findCol int, findColVal any, sortCols []SortColumn, sortVals []any, currentKeys []any) ([]any, bool, error) {
+	return rel.FindRow(findCol, findColVal, FindOptions{}, sortCols, sortVals, currentKeys)
+}
+
+// baseTableName returns the single base table behind the relation.
+// Views and custom SQL only qualify when they draw from exactly one table.
+func (rel *Relation) baseTableName() (string, error) {
+	if !rel.IsView && !rel.IsCustomSQL {
+		return rel.Name, nil
 	}
-	if len(currentKeys) != len(rel.Key) {
-		return nil, false, fmt.Errorf("currentKeys length mismatch: expected %d, got %d", len(rel.Key), len(currentKeys))
+	if len(rel.Tables) == 1 {
+		for name := range rel.Tables {
+			return name, nil
+		}
 	}
+	return "", fmt.Errorf("relation does not have a single base table")
+}
 
-	searchColName := rel.Columns[findCol].Name
-	quotedSearchCol := quoteIdent(rel.DBType, searchColName)
-	quotedTable := quoteQualified(rel.DBType, rel.Name)
-
-	// Build key column list for SELECT
-	keyCols := make([]string, len(rel.Key))
-	for i, keyIdx := range rel.Key {
-		if keyIdx < len(rel.Columns) {
-			keyCols[i] = quoteIdent(rel.DBType, rel.Columns[keyIdx].Name)
+// BaseColumnIndex returns the index of the relation column that passes through
+// baseColumn of the base table, or -1 if the column is not selected.
+func (rel *Relation) BaseColumnIndex(table, baseColumn string) int {
+	for i, col := range rel.Columns {
+		if col.Table == table && col.BaseColumn == baseColumn {
+			return i
 		}
 	}
-	selectClause := strings.Join(keyCols, ", ")
+	return -1
+}
 
-	// Helper to build WHERE clause for multi-column key progression using row value syntax
-	buildKeyWhere := func(op string, startPos int) (string, []any) {
-		// Get key column names
-		keyColNames := make([]string, len(rel.Key))
-		for i, keyIdx := range rel.Key {
-			if keyIdx >= len(rel.Columns) {
-				continue
-			}
-			keyColNames[i] = rel.Columns[keyIdx].Name
+// IncomingReferences returns the foreign keys in the database that reference this
+// relation's base table, along with the name of that base table.
+func (rel *Relation) IncomingReferences() (string, []IncomingReference, error) {
+	table, err := rel.baseTableName()
+	if err != nil {
+		return "", nil, err
+	}
+	handler := rel.handler
+	if handler == nil {
+		if handler, err = NewDatabaseHandler(rel.DBType); err != nil {
+			return "", nil, err
 		}
-
-		// Build row value expression
-		colExpr, placeholders := buildRowValueExpression(rel.DBType, keyColNames, startPos)
-		placeholderExpr := buildPlaceholderExpression(placeholders)
-
-		// Build WHERE clause: (key1, key2) > (?, ?)
-		whereClause := fmt.Sprintf("%s %s %s", colExpr, op, placeholderExpr)
-
-		// Build args from currentKeys
-		args := make([]any, len(currentKeys))
-		copy(args, currentKeys)
-
-		return whereClause, args
 	}
-
-	// Search below current position
-	var whereParts []string
-	var args []any
-	paramPos := 1
-
-	// Sort column condition
-	if sortCol != nil {
-		quotedSortCol := quoteIdent(rel.DBType, sortCol.Name)
-		whereParts = append(whereParts, fmt.Sprintf("%s >= %s", quotedSortCol, rel.placeholder(paramPos)))
-		args = append(args, sortColVal)
-		paramPos++
+	refs, err := handler.LoadIncomingReferences(rel.DB, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load incoming references: %w", err)
 	}
+	return table, refs, nil
+}
 
-	// Key progression conditions
-	keyWhere, keyArgs := buildKeyWhere(">", paramPos)
-	whereParts = append(whereParts, keyWhere)
-	args = append(args, keyArgs...)
-	paramPos += len(keyArgs)
-
-	// Search column match
-	whereParts = append(whereParts, fmt.Sprintf("%s = %s", quotedSearchCol, rel.placeholder(paramPos)))
-	args = append(args, findColVal)
+// referencingWhere builds the WHERE clause matching rows of ref.Table that point at
+// the referenced values. values is keyed by referenced column name. Returns false
+// if any referenced value is NULL, since such a row cannot be referenced.
+func (rel *Relation) referencingWhere(ref IncomingReference, values map[string]any) (string, []any, bool) {
+	link, ok := referencingLink(ref, values)
+	if !ok {
+		return "", nil, false
+	}
+	whereParts := make([]string, len(link))
+	args := make([]any, len(link))
+	for i, qf := range link {
+		whereParts[i] = fmt.Sprintf("%s = %s", quoteIdent(rel.DBType, qf.Column), rel.placeholder(i+1))
+		args[i] = qf.Value
+	}
+	return strings.Join(whereParts, " AND "), args, true
+}
 
-	// ORDER BY
-	var orderParts []string
-	if sortCol != nil {
-		if sortCol.Asc {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, sortCol.Name)+" ASC")
-		} else {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, sortCol.Name)+" DESC")
-		}
+// referencingLink returns a condition per referencing column of ref, ordered by
+// column name, or false if any referenced value is NULL
+func referencingLink(ref IncomingReference, values map[string]any) ([]QuickFilter, bool) {
+	cols := make([]string, 0, len(ref.Columns))
+	for col := range ref.Columns {
+		cols = append(cols, col)
 	}
-	for _, keyIdx := range rel.Key {
-		if keyIdx < len(rel.Columns) {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, rel.Columns[keyIdx].Name)+" ASC")
+	sort.Strings(cols)
+
+	link := make([]QuickFilter, 0, len(cols))
+	for _, col := range cols {
+		val, ok := values[ref.Columns[col]]
+		if !ok || val == nil {
+			return nil, false
 		}
+		link = append(link, QuickFilter{Column: col, Value: val})
 	}
+	return link, true
+}
 
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT 1",
-		selectClause, quotedTable, strings.Join(whereParts, " AND "), strings.Join(orderParts, ", "))
-
-	row := rel.DB.QueryRow(query, args...)
-	foundKeys := make([]any, len(rel.Key))
-	scanArgs := make([]any, len(rel.Key))
-	for i := range foundKeys {
-		scanArgs[i] = &foundKeys[i]
+// CountReferencingRows counts the rows in ref.Table that reference the given values.
+// values is keyed by referenced column name.
+func (rel *Relation) CountReferencingRows(ref IncomingReference, values map[string]any) (int64, error) {
+	where, args, ok := rel.referencingWhere(ref, values)
+	if !ok {
+		return 0, nil
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteQualified(rel.DBType, ref.Table), where)
+	var count int64
+	if err := rel.DB.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rows in %s: %w", ref.Table, err)
 	}
+	return count, nil
+}
 
-	err := row.Scan(scanArgs...)
-	if err == nil {
-		return foundKeys, true, nil // Found below
+// NewReferencingRelation opens ref.Table restricted to the rows that reference the
+// given values, bound as its Link. values is keyed by referenced column name.
+func (rel *Relation) NewReferencingRelation(ref IncomingReference, values map[string]any) (*Relation, error) {
+	link, ok := referencingLink(ref, values)
+	if !ok {
+		return nil, fmt.Errorf("referenced values must not be null")
 	}
-	if err != sql.ErrNoRows {
-		return nil, false, fmt.Errorf("search below failed: %w", err)
+	referencing, err := NewRelation(rel.DB, rel.DBType, ref.Table)
+	if err != nil {
+		return nil, err
 	}
+	referencing.Link = link
+	return referencing, nil
+}
 
-	// Wrap around: search from top up to current position
-	whereParts = whereParts[:0]
-	args = args[:0]
-	paramPos = 1
+// WritableBaseTable returns the table that receives inserts and deletes. Tables
+// write to themselves; views and custom SQL write to their base table, which must
+// be the only table in the result and have its key selected. The error explains
+// why rows can't be inserted or deleted otherwise.
+func (rel *Relation) WritableBaseTable() (string, error) {
+	if !rel.IsView && !rel.IsCustomSQL {
+		return rel.Name, nil
+	}
+	baseRel, _, err := rel.writableBase()
+	if err != nil {
+		return "", err
+	}
+	return baseRel.Name, nil
+}
 
-	// Sort column condition (reversed)
-	if sortCol != nil {
-		quotedSortCol := quoteIdent(rel.DBType, sortCol.Name)
-		whereParts = append(whereParts, fmt.Sprintf("%s <= %s", quotedSortCol, rel.placeholder(paramPos)))
-		args = append(args, sortColVal)
-		paramPos++
+// writableBase loads the base table relation for a view or custom SQL along with
+// its entry in rel.Tables, whose Key maps the base key onto result columns. The
+// outcome is kept on rel, unless the base table failed to load.
+func (rel *Relation) writableBase() (*Relation, *Table, error) {
+	if rel.base != nil || rel.baseErr != nil {
+		return rel.base, rel.baseTable, rel.baseErr
 	}
 
-	// Key progression conditions (reversed)
-	keyWhere, keyArgs = buildKeyWhere("<", paramPos)
-	whereParts = append(whereParts, keyWhere)
-	args = append(args, keyArgs...)
-	paramPos += len(keyArgs)
+	names := make([]string, 0, len(rel.Tables))
+	for name := range rel.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	// Search column match
-	whereParts = append(whereParts, fmt.Sprintf("%s = %s", quotedSearchCol, rel.placeholder(paramPos)))
-	args = append(args, findColVal)
+	switch len(names) {
+	case 0:
+		rel.baseErr = fmt.Errorf("rows can't be inserted or deleted: no base table found for this result")
+		return nil, nil, rel.baseErr
+	case 1:
+	default:
+		rel.baseErr = fmt.Errorf("rows can't be inserted or deleted: result combines tables %s, only single-table results map to one row",
+			strings.Join(names, ", "))
+		return nil, nil, rel.baseErr
+	}
 
-	// ORDER BY (reversed)
-	orderParts = orderParts[:0]
-	if sortCol != nil {
-		if sortCol.Asc {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, sortCol.Name)+" DESC")
-		} else {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, sortCol.Name)+" ASC")
-		}
+	table := rel.Tables[names[0]]
+	baseRel, err := NewRelation(rel.DB, rel.DBType, table.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load base table: %w", err)
 	}
-	for _, keyIdx := range rel.Key {
-		if keyIdx < len(rel.Columns) {
-			orderParts = append(orderParts, quoteIdent(rel.DBType, rel.Columns[keyIdx].Name)+" DESC")
+	if len(baseRel.Key) == 0 || len(table.Key) != len(baseRel.Key) {
+		keyNames := make([]string, 0, len(baseRel.Key))
+		for _, keyIdx := range baseRel.Key {
+			keyNames = append(keyNames, baseRel.Columns[keyIdx].Name)
 		}
+		rel.baseErr = fmt.Errorf("rows can't be inserted or deleted: key of %s (%s) is not part of the result",
+			table.Name, strings.Join(keyNames, ", "))
+		return nil, nil, rel.baseErr
 	}
+	rel.base, rel.baseTable = baseRel, table
+	return baseRel, table, nil
+}
 
-	query = fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT 1",
-		selectClause, quotedTable, strings.Join(whereParts, " AND "), strings.Join(orderParts, ", "))
+// insertViewRecord inserts a row entered through a view or custom SQL into the base
+// table. Result columns that pass through base columns supply the values; derived
+// columns are ignored. The inserted row is returned in result column order, with
+// nil for columns that don't come from the base table.
+func (rel *Relation) insertViewRecord(newRecordRow []any) ([]any, error) {
+	baseRel, table, err := rel.writableBase()
+	if err != nil {
+		return nil, err
+	}
 
-	row = rel.DB.QueryRow(query, args...)
-	err = row.Scan(scanArgs...)
-	if err == sql.ErrNoRows {
-		return nil, false, nil // Not found at all
+	baseRow := make([]any, len(baseRel.Columns))
+	for i := range baseRow {
+		baseRow[i] = EmptyCellValue
+	}
+	for i, col := range rel.Columns {
+		if col.Table != table.Name || col.BaseColumn == "" || col.Generated {
+			continue
+		}
+		if baseIdx, ok := baseRel.ColumnIndex[col.BaseColumn]; ok {
+			baseRow[baseIdx] = newRecordRow[i]
+		}
 	}
+
+	// The base table reports the insert to this relation's observer
+	baseRel.OnWrite = rel.OnWrite
+	inserted, err := baseRel.InsertDBRecord(baseRow)
+	baseRel.OnWrite = nil
 	if err != nil {
-		return nil, false, fmt.Errorf("wrap search failed: %w", err)
+		return nil, err
 	}
 
-	return foundKeys, false, nil // Found after wrapping
+	viewRow := make([]any, len(rel.Columns))
+	for i, col := range rel.Columns {
+		if col.Table != table.Name || col.BaseColumn == "" {
+			continue
+		}
+		if baseIdx, ok := baseRel.ColumnIndex[col.BaseColumn]; ok && baseIdx < len(inserted) {
+			viewRow[i] = inserted[baseIdx]
+		}
+	}
+	return viewRow, nil
 }
@@ -0,0 +1,175 @@
+package dblib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WriteEvent describes a statement that modified data, reported after it succeeds.
+type WriteEvent struct {
+	Op        string         // UPDATE, INSERT or DELETE
+	Table     string         // table the statement wrote to
+	DBType    DatabaseType   // dialect of Statement
+	Statement string         // statement with placeholders
+	Params    []any          // values bound to the placeholders
+	Before    map[string]any // row image before the write, nil for inserts
+	After     map[string]any // row image after the write, nil for deletes
+}
+
+// notifyWrite reports a successful write to the relation's OnWrite, if set
+func (rel *Relation) notifyWrite(op, table, statement string, params []any, before, after map[string]any) {
+	if rel.OnWrite == nil {
+		return
+	}
+	rel.OnWrite(WriteEvent{
+		Op:        op,
+		Table:     table,
+		DBType:    rel.DBType,
+		Statement: statement,
+		Params:    params,
+		Before:    before,
+		After:     after,
+	})
+}
+
+// rowImage maps column names to values for a row ordered like columns
+func rowImage(columns []Column, values []any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	image := make(map[string]any, len(columns))
+	for i, col := range columns {
+		if i < len(values) {
+			image[col.Name] = values[i]
+		}
+	}
+	return image
+}
+
+// baseRowImage maps the base column names of table to values for a row ordered like
+// columns, leaving out the columns that don't come from table
+func baseRowImage(columns []Column, table string, values []any) map[string]any {
+	image := make(map[string]any, len(columns))
+	for i, col := range columns {
+		if col.Table != table || i >= len(values) {
+			continue
+		}
+		name := col.BaseColumn
+		if name == "" {
+			name = col.Name
+		}
+		image[name] = values[i]
+	}
+	return image
+}
+
+// storedImage reads the row of table identified by keyCols and keyVals for the Before
+// image of a write, since rows loaded for display leave out hidden columns. It returns
+// loaded when nobody observes writes or the row can't be read.
+func (rel *Relation) storedImage(table string, keyCols []Column, keyVals []any, loaded map[string]any) map[string]any {
+	if rel.OnWrite == nil || len(keyCols) == 0 {
+		return loaded
+	}
+	whereParts := make([]string, len(keyCols))
+	for i, keyCol := range keyCols {
+		whereParts[i] = fmt.Sprintf("%s = %s", quoteIdent(rel.DBType, keyCol.Name), rel.placeholder(i+1))
+	}
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s", quoteQualified(rel.DBType, table), strings.Join(whereParts, " AND "))
+	rows, err := rel.DB.Query(query, keyVals...)
+	if err != nil {
+		return loaded
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil || !rows.Next() {
+		return loaded
+	}
+	values := make([]any, len(names))
+	scanArgs := make([]any, len(names))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return loaded
+	}
+	image := make(map[string]any, len(names))
+	for i, name := range names {
+		image[name] = values[i]
+	}
+	return image
+}
+
+// InlineParams renders statement with its placeholders replaced by SQL literals,
+// producing a script that can be run without bind parameters. Placeholders inside
+// quoted strings and identifiers are left alone.
+func InlineParams(dbType DatabaseType, statement string, params []any) string {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy quoted text through the closing quote; doubled quotes stay inside
+			end := i + 1
+			for end < len(statement) {
+				if statement[end] == c {
+					if end+1 < len(statement) && statement[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end, len(statement)-1)
+			b.WriteString(statement[i : end+1])
+			i = end
+		case c == '?':
+			if next < len(params) {
+				b.WriteString(sqlLiteral(dbType, params[next]))
+			} else {
+				b.WriteByte(c)
+			}
+			next++
+		case c == '$' && i+1 < len(statement) && statement[i+1] >= '0' && statement[i+1] <= '9':
+			end := i + 1
+			for end < len(statement) && statement[end] >= '0' && statement[end] <= '9' {
+				end++
+			}
+			n, _ := strconv.Atoi(statement[i+1 : end])
+			if n >= 1 && n <= len(params) {
+				b.WriteString(sqlLiteral(dbType, params[n-1]))
+			} else {
+				b.WriteString(statement[i:end])
+			}
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// sqlLiteral renders a bound value as a literal for the given database
+func sqlLiteral(dbType DatabaseType, val any) string {
+	switch v := val.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return "'" + strings.ReplaceAll(string(v), "'", "''") + "'"
+		}
+		if dbType == PostgreSQL {
+			return "'\\x" + hex.EncodeToString(v) + "'::bytea"
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999999-07:00") + "'"
+	case int:
+		return strconv.Itoa(v)
+	}
+	rel := &Relation{DBType: dbType}
+	return rel.formatLiteral(val, "")
+}
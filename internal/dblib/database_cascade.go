@@ -0,0 +1,153 @@
+package dblib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCascadeDepth is how many levels of ON DELETE CASCADE DeleteImpact follows by default.
+const DefaultCascadeDepth = 3
+
+// DeleteEffect describes what deleting a row does to the rows of one table that
+// reference it, directly or through cascaded deletes.
+type DeleteEffect struct {
+	Table  string // referencing table
+	Action string // ON DELETE action of the foreign key
+	Count  int64  // referencing rows affected
+	Depth  int    // 1 for rows referencing the deleted row, 2 for rows referencing those, ...
+	More   bool   // cascaded rows are referenced further than the depth limit
+
+	Unenforced bool // the database ignores the foreign key, as SQLite does with foreign_keys off
+}
+
+// Blocks reports whether the effect makes the database reject the delete.
+// NO ACTION is checked at the end of the statement, so it blocks like RESTRICT.
+func (eff DeleteEffect) Blocks() bool {
+	return !eff.Unenforced && (eff.Action == "RESTRICT" || eff.Action == "NO ACTION")
+}
+
+// DeleteImpact inspects the foreign keys referencing row and counts the rows each
+// would cascade-delete, set NULL/DEFAULT, or block the delete with. Cascaded deletes
+// are followed up to maxDepth levels. Only references with matching rows are returned.
+// When the database doesn't enforce foreign keys, the rows directly referencing row
+// are returned marked Unenforced, as the delete leaves them alone.
+func (rel *Relation) DeleteImpact(row []any, maxDepth int) ([]DeleteEffect, error) {
+	table, keyCols, keyVals, err := rel.deleteKey(row)
+	if err != nil {
+		return nil, err
+	}
+	handler := rel.handler
+	if handler == nil {
+		if handler, err = NewDatabaseHandler(rel.DBType); err != nil {
+			return nil, err
+		}
+	}
+
+	enforced, err := handler.ForeignKeysEnforced(rel.DB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check foreign key enforcement: %w", err)
+	}
+
+	whereParts := make([]string, len(keyCols))
+	for i, keyCol := range keyCols {
+		if keyVals[i] == nil {
+			return nil, nil // a row without a key can't be referenced
+		}
+		whereParts[i] = fmt.Sprintf("%s = %s", quoteIdent(rel.DBType, keyCol.Name), rel.placeholder(i+1))
+	}
+
+	refCache := map[string][]IncomingReference{}
+	loadRefs := func(table string) ([]IncomingReference, error) {
+		if refs, ok := refCache[table]; ok {
+			return refs, nil
+		}
+		refs, err := handler.LoadIncomingReferences(rel.DB, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load incoming references: %w", err)
+		}
+		refCache[table] = refs
+		return refs, nil
+	}
+
+	var effects []DeleteEffect
+	// Each level selects the affected rows with a subquery over the level above, so the
+	// key placeholders appear exactly once in every query
+	var walk func(table, where string, depth int) error
+	walk = func(table, where string, depth int) error {
+		refs, err := loadRefs(table)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			childWhere := referencingSubquery(rel.DBType, ref, table, where)
+			query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteQualified(rel.DBType, ref.Table), childWhere)
+			var count int64
+			if err := rel.DB.QueryRow(query, keyVals...).Scan(&count); err != nil {
+				return fmt.Errorf("failed to count rows in %s: %w", ref.Table, err)
+			}
+			if count == 0 {
+				continue
+			}
+			effect := DeleteEffect{Table: ref.Table, Action: ref.OnDelete, Count: count, Depth: depth, Unenforced: !enforced}
+			if effect.Action == "" {
+				effect.Action = "NO ACTION"
+			}
+			if effect.Action == "CASCADE" && enforced {
+				if depth < maxDepth {
+					effects = append(effects, effect)
+					if err := walk(ref.Table, childWhere, depth+1); err != nil {
+						return err
+					}
+					continue
+				}
+				next, err := loadRefs(ref.Table)
+				if err != nil {
+					return err
+				}
+				// Only rows that reference the cascaded ones are left undescribed
+				for _, nref := range next {
+					query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteQualified(rel.DBType, nref.Table),
+						referencingSubquery(rel.DBType, nref, ref.Table, childWhere))
+					var more int64
+					if err := rel.DB.QueryRow(query, keyVals...).Scan(&more); err != nil {
+						return fmt.Errorf("failed to count rows in %s: %w", nref.Table, err)
+					}
+					if more > 0 {
+						effect.More = true
+						break
+					}
+				}
+			}
+			effects = append(effects, effect)
+		}
+		return nil
+	}
+	if err := walk(table, strings.Join(whereParts, " AND "), 1); err != nil {
+		return nil, err
+	}
+	return effects, nil
+}
+
+// referencingSubquery builds a WHERE clause matching the rows of ref.Table that
+// reference rows of table selected by where.
+func referencingSubquery(dbType DatabaseType, ref IncomingReference, table, where string) string {
+	cols := make([]string, 0, len(ref.Columns))
+	for col := range ref.Columns {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	local := make([]string, len(cols))
+	target := make([]string, len(cols))
+	for i, col := range cols {
+		local[i] = quoteIdent(dbType, col)
+		target[i] = quoteIdent(dbType, ref.Columns[col])
+	}
+	lhs := local[0]
+	if len(local) > 1 {
+		lhs = "(" + strings.Join(local, ", ") + ")"
+	}
+	return fmt.Sprintf("%s IN (SELECT %s FROM %s WHERE %s)",
+		lhs, strings.Join(target, ", "), quoteQualified(dbType, table), where)
+}
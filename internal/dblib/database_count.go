@@ -0,0 +1,256 @@
+package dblib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExactCountLimit is the estimated row count up to which rows are counted exactly.
+// Larger tables show their estimate and place rows by sampling.
+const ExactCountLimit = 100000
+
+// keySampleSize is roughly how many rows SampleKeys reads
+const keySampleSize = 256
+
+// filterSampleSize is roughly how many rows are read to estimate how many of a large
+// table's rows a filter keeps
+const filterSampleSize = 10000
+
+// CountRows returns how many rows the relation shows. Tables whose planner estimate
+// exceeds ExactCountLimit return the estimate with exact false, scaled by a random
+// sample when filtered; smaller tables, views, custom SQL and databases without
+// estimates are counted.
+func (rel *Relation) CountRows() (count int64, exact bool, err error) {
+	if rel.handler != nil && !rel.IsView && !rel.IsCustomSQL {
+		estimate, ok, err := rel.handler.EstimateRowCount(rel.DB, rel.Name)
+		if err == nil && ok && estimate > ExactCountLimit {
+			if rel.HasFilter() {
+				return rel.estimateFilteredRows(estimate)
+			}
+			return estimate, false, nil
+		}
+	}
+
+	args := &queryArgs{dbType: rel.DBType}
+	query := "SELECT COUNT(*) FROM " + rel.source()
+	if filter := rel.filterPredicate(args); filter != "" {
+		query += " WHERE " + filter
+	}
+	if err := rel.DB.QueryRow(query, args.values...).Scan(&count); err != nil {
+		return 0, false, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return count, true, nil
+}
+
+// estimateFilteredRows scales a table's estimated row count by the share of a random
+// sample of its rows that the filters keep
+func (rel *Relation) estimateFilteredRows(estimate int64) (int64, bool, error) {
+	args := &queryArgs{dbType: rel.DBType}
+	query := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0) FROM %s WHERE %s",
+		rel.filterPredicate(args), rel.source(), randomBelow(rel.DBType, float64(filterSampleSize)/float64(estimate)))
+	var sampled, kept int64
+	if err := rel.DB.QueryRow(query, args.values...).Scan(&sampled, &kept); err != nil {
+		return 0, false, fmt.Errorf("failed to estimate rows: %w", err)
+	}
+	if sampled == 0 {
+		return 0, false, nil
+	}
+	return estimate * kept / sampled, false, nil
+}
+
+// RowPosition returns how many rows come before the row at cursor in the order of
+// sortCols and the key. cursor holds the row's sort values followed by its key.
+func (rel *Relation) RowPosition(sortCols []SortColumn, cursor []any) (int64, error) {
+	terms := rel.orderTerms(sortCols)
+	if len(cursor) != len(terms) {
+		return 0, fmt.Errorf("cursor has %d values, expected %d", len(cursor), len(terms))
+	}
+	args := &queryArgs{dbType: rel.DBType}
+	var conditions []string
+	if filter := rel.filterPredicate(args); filter != "" {
+		conditions = append(conditions, filter)
+	}
+	conditions = append(conditions, keysetPredicate(terms, cursor, false, false, args))
+
+	var position int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", rel.source(), strings.Join(conditions, " AND "))
+	if err := rel.DB.QueryRow(query, args.values...).Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to count preceding rows: %w", err)
+	}
+	return position, nil
+}
+
+// KeySample is an ordered random sample of row cursors, the quantiles used to jump
+// to a fraction of the way through a relation and to estimate where a row lies
+// without OFFSET or counting.
+type KeySample struct {
+	cursors [][]any
+	terms   []orderTerm
+}
+
+// randomBelow renders a condition true for about fraction of the rows
+func randomBelow(dbType DatabaseType, fraction float64) string {
+	switch dbType {
+	case SQLite:
+		// random() returns a signed 64-bit integer
+		return fmt.Sprintf("abs(random() %% 1000000) < %d", int64(fraction*1000000))
+	case MySQL:
+		return fmt.Sprintf("RAND() < %g", fraction)
+	default:
+		return fmt.Sprintf("random() < %g", fraction)
+	}
+}
+
+// SampleKeys reads the cursors of a random sample of about keySampleSize rows, in the
+// order of sortCols and the key. total is the relation's row count, estimated or not.
+func (rel *Relation) SampleKeys(sortCols []SortColumn, total int64) (*KeySample, error) {
+	terms := rel.orderTerms(sortCols)
+	cols := make([]string, len(terms))
+	for i, t := range terms {
+		cols[i] = t.expr
+	}
+
+	args := &queryArgs{dbType: rel.DBType}
+	var conditions []string
+	if filter := rel.filterPredicate(args); filter != "" {
+		conditions = append(conditions, filter)
+	}
+	if total > keySampleSize {
+		conditions = append(conditions, randomBelow(rel.DBType, float64(keySampleSize)/float64(total)))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), rel.source())
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY " + orderByClause(terms, true)
+
+	rows, err := rel.DB.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample rows: %w", err)
+	}
+	defer rows.Close()
+	sample := &KeySample{terms: terms}
+	for rows.Next() {
+		cursor := make([]any, len(terms))
+		scanArgs := make([]any, len(cursor))
+		for i := range cursor {
+			scanArgs[i] = &cursor[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("failed to sample rows: %w", err)
+		}
+		sample.cursors = append(sample.cursors, cursor)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to sample rows: %w", err)
+	}
+	return sample, nil
+}
+
+// Len returns how many rows were sampled
+func (s *KeySample) Len() int {
+	return len(s.cursors)
+}
+
+// At returns the cursor of the sampled row about fraction of the way through, or nil
+// if nothing was sampled
+func (s *KeySample) At(fraction float64) []any {
+	if len(s.cursors) == 0 {
+		return nil
+	}
+	i := int(fraction * float64(len(s.cursors)))
+	return s.cursors[max(0, min(i, len(s.cursors)-1))]
+}
+
+// Fraction estimates how far through the relation the row at cursor lies, from 0 to 1.
+// Values are compared in Go, so text collations only approximate the database's order.
+func (s *KeySample) Fraction(cursor []any) float64 {
+	if len(s.cursors) == 0 || len(cursor) != len(s.terms) {
+		return 0
+	}
+	before := sort.Search(len(s.cursors), func(i int) bool {
+		return compareCursors(s.terms, s.cursors[i], cursor) >= 0
+	})
+	return float64(before) / float64(len(s.cursors))
+}
+
+// compareCursors orders two cursors the way orderByClause sorts rows
+func compareCursors(terms []orderTerm, a, b []any) int {
+	for i, t := range terms {
+		c := compareValues(a[i], b[i])
+		if !t.asc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareValues orders two scanned values, with NULL after everything else
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y)
+		}
+	}
+	return strings.Compare(compareText(a), compareText(b))
+}
+
+// compareText returns the text of a value for comparison, keeping bytes as they are
+func compareText(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// toFloat converts numeric scanned values for comparison
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -187,7 +187,7 @@ func (h *DuckDBHandler) CheckIsView(db *sql.DB, relationName string) (bool, erro
 // LoadColumns loads column metadata for a DuckDB table.
 func (h *DuckDBHandler) LoadColumns(db *sql.DB, tableName string) ([]Column, map[string]int, error) {
 	query := `
-		SELECT column_name, data_type, is_nullable
+		SELECT column_name, data_type, is_nullable, column_default
 		FROM information_schema.columns
 		WHERE table_name = ?
 		ORDER BY ordinal_position`
@@ -205,8 +205,9 @@ func (h *DuckDBHandler) LoadColumns(db *sql.DB, tableName string) ([]Column, map
 		var col Column
 		col.Reference = -1
 		var nullable string
+		var dflt sql.NullString
 
-		err = rows.Scan(&col.Name, &col.Type, &nullable)
+		err = rows.Scan(&col.Name, &col.Type, &nullable, &dflt)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -214,6 +215,9 @@ func (h *DuckDBHandler) LoadColumns(db *sql.DB, tableName string) ([]Column, map
 		col.Nullable = nullable == "YES"
 		col.Table = tableName
 		col.BaseColumn = col.Name
+		col.Default = dflt.String
+		col.HasDefault = dflt.Valid
+		col.AutoIncrement = strings.HasPrefix(dflt.String, "nextval(")
 
 		idx := len(columns)
 		columns = append(columns, col)
@@ -236,6 +240,16 @@ func (h *DuckDBHandler) LoadForeignKeys(db *sql.DB, dbType DatabaseType, tableNa
 	return []Reference{}, updatedColumns, nil
 }
 
+// LoadIncomingReferences returns no references for DuckDB, matching LoadForeignKeys.
+func (h *DuckDBHandler) LoadIncomingReferences(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	return []IncomingReference{}, nil
+}
+
+// ForeignKeysEnforced reports that DuckDB always enforces foreign keys.
+func (h *DuckDBHandler) ForeignKeysEnforced(db *sql.DB) (bool, error) {
+	return true, nil
+}
+
 // LoadEnumAndCustomTypes is a no-op for DuckDB (minimal enum support).
 func (h *DuckDBHandler) LoadEnumAndCustomTypes(db *sql.DB, tableName string, columns []Column) ([]Column, error) {
 	// DuckDB has enum support but it's not widely used yet
@@ -255,6 +269,11 @@ func (h *DuckDBHandler) GetViewDefinition(db *sql.DB, viewName string) (string,
 	return sqlDef, nil
 }
 
+// EstimateRowCount returns no estimate for DuckDB, which counts rows quickly.
+func (h *DuckDBHandler) EstimateRowCount(db *sql.DB, tableName string) (int64, bool, error) {
+	return 0, false, nil
+}
+
 // GetBestKey identifies the best key column(s) for a DuckDB table.
 func (h *DuckDBHandler) GetBestKey(db *sql.DB, tableName string) ([]string, error) {
 	return getBestKeyDuckDB(db, tableName)
@@ -0,0 +1,68 @@
+package dblib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QuickFilter keeps only the rows whose Column equals Value, or with Exclude, drops them.
+// A nil Value matches NULL.
+type QuickFilter struct {
+	Column  string
+	Value   any
+	Exclude bool
+}
+
+// predicate renders the condition, binding Value through args
+func (qf QuickFilter) predicate(dbType DatabaseType, args *queryArgs) string {
+	col := quoteIdent(dbType, qf.Column)
+	switch {
+	case qf.Value == nil && qf.Exclude:
+		return col + " IS NOT NULL"
+	case qf.Value == nil:
+		return col + " IS NULL"
+	case qf.Exclude:
+		// NULL is not the excluded value, so those rows stay
+		return fmt.Sprintf("(%s <> %s OR %s IS NULL)", col, args.add(qf.Value), col)
+	default:
+		return fmt.Sprintf("%s = %s", col, args.add(qf.Value))
+	}
+}
+
+// SetFilter restricts the rows the relation returns to those matching the SQL
+// predicate, or to all rows when filter is empty. The predicate is checked against
+// the database first, leaving the current filter in place if it is invalid.
+func (rel *Relation) SetFilter(filter string) error {
+	filter = strings.TrimSpace(filter)
+	if filter != "" {
+		// 1 = 0 lets the database parse and resolve the predicate without scanning rows
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE (%s) AND 1 = 0", rel.source(), filter)
+		var count int64
+		if err := rel.DB.QueryRow(query).Scan(&count); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	rel.Filter = filter
+	return nil
+}
+
+// HasFilter reports whether a filter, quick filters or a link restrict the rows
+func (rel *Relation) HasFilter() bool {
+	return rel.Filter != "" || len(rel.QuickFilters) > 0 || len(rel.Link) > 0
+}
+
+// filterPredicate renders the Filter, QuickFilters and Link as one predicate, binding
+// values through args, or returns "" when the relation is unfiltered
+func (rel *Relation) filterPredicate(args *queryArgs) string {
+	var parts []string
+	if rel.Filter != "" {
+		parts = append(parts, "("+rel.Filter+")")
+	}
+	for _, qf := range rel.QuickFilters {
+		parts = append(parts, qf.predicate(rel.DBType, args))
+	}
+	for _, qf := range rel.Link {
+		parts = append(parts, qf.predicate(rel.DBType, args))
+	}
+	return strings.Join(parts, " AND ")
+}
@@ -0,0 +1,257 @@
+package dblib
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FindMode selects how Find compares column values with the search text
+type FindMode int
+
+const (
+	FindExact     FindMode = iota // value equals the search text
+	FindPrefix                    // value starts with the search text
+	FindSubstring                 // value contains the search text
+	FindRegex                     // value matches the search text as a regular expression
+)
+
+func (m FindMode) String() string {
+	switch m {
+	case FindPrefix:
+		return "prefix"
+	case FindSubstring:
+		return "substring"
+	case FindRegex:
+		return "regex"
+	default:
+		return "exact"
+	}
+}
+
+// FindOptions controls how FindRow matches rows
+type FindOptions struct {
+	Mode       FindMode
+	IgnoreCase bool
+	AllColumns bool // match any text column instead of the find column
+	Backward   bool // search above the current row first
+}
+
+// isTextColumn reports whether a column holds text worth searching across columns
+func isTextColumn(col Column) bool {
+	t := strings.ToLower(col.Type)
+	return t == "" || len(col.EnumValues) > 0 ||
+		strings.Contains(t, "char") || strings.Contains(t, "text") || strings.Contains(t, "clob") ||
+		strings.Contains(t, "string") || strings.Contains(t, "json") || strings.Contains(t, "uuid")
+}
+
+// escapeLike escapes the LIKE wildcards in s with backslashes
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// escapeGlob escapes the GLOB wildcards in s with character classes
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`[`, `[[]`, `*`, `[*]`, `?`, `[?]`).Replace(s)
+}
+
+// matchColumn renders the condition for one column matching value
+func (rel *Relation) matchColumn(col Column, value any, opts FindOptions, args *queryArgs) string {
+	expr := quoteIdent(rel.DBType, col.Name)
+	if opts.Mode == FindExact && !opts.IgnoreCase {
+		return fmt.Sprintf("%s = %s", expr, args.add(value))
+	}
+
+	// Text comparisons need text on both sides, which PostgreSQL and DuckDB won't coerce
+	text := expr
+	if (rel.DBType == PostgreSQL || rel.DBType == DuckDB) && !strings.Contains(strings.ToLower(col.Type), "text") {
+		text = "CAST(" + expr + " AS TEXT)"
+	}
+	search := fmt.Sprint(value)
+
+	switch opts.Mode {
+	case FindPrefix, FindSubstring:
+		pattern, glob := escapeLike(search)+"%", escapeGlob(search)+"*"
+		if opts.Mode == FindSubstring {
+			pattern, glob = "%"+pattern, "*"+glob
+		}
+		switch {
+		case rel.DBType == SQLite && !opts.IgnoreCase:
+			// SQLite's LIKE ignores case, GLOB doesn't
+			return fmt.Sprintf("%s GLOB %s", text, args.add(glob))
+		case rel.DBType == SQLite:
+			return fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, text, args.add(pattern))
+		case rel.DBType == MySQL && opts.IgnoreCase:
+			return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", text, args.add(pattern))
+		case rel.DBType == MySQL:
+			// Most MySQL collations ignore case, a binary comparison doesn't
+			return fmt.Sprintf("BINARY %s LIKE %s", text, args.add(pattern))
+		case opts.IgnoreCase:
+			return fmt.Sprintf(`%s ILIKE %s ESCAPE '\'`, text, args.add(pattern))
+		default:
+			return fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, text, args.add(pattern))
+		}
+	case FindRegex:
+		switch rel.DBType {
+		case PostgreSQL:
+			if opts.IgnoreCase {
+				return fmt.Sprintf("%s ~* %s", text, args.add(search))
+			}
+			return fmt.Sprintf("%s ~ %s", text, args.add(search))
+		case MySQL:
+			return fmt.Sprintf("REGEXP_LIKE(%s, %s, '%s')", text, args.add(search), regexFlag(opts.IgnoreCase))
+		case DuckDB:
+			return fmt.Sprintf("regexp_matches(%s, %s, '%s')", text, args.add(search), regexFlag(opts.IgnoreCase))
+		default:
+			// REGEXP calls the function registered with SQLiteDriverName
+			if opts.IgnoreCase {
+				search = "(?i)" + search
+			}
+			return fmt.Sprintf("%s REGEXP %s", text, args.add(search))
+		}
+	default:
+		return fmt.Sprintf("LOWER(%s) = LOWER(%s)", text, args.add(search))
+	}
+}
+
+// regexFlag returns the MySQL/DuckDB regex option for case sensitivity
+func regexFlag(ignoreCase bool) string {
+	if ignoreCase {
+		return "i"
+	}
+	return "c"
+}
+
+// findPredicate renders the condition for a row matching value, in the column at
+// index findCol or, with AllColumns, in any text column
+func (rel *Relation) findPredicate(findCol int, value any, opts FindOptions, args *queryArgs) (string, error) {
+	if !opts.AllColumns {
+		if findCol < 0 || findCol >= len(rel.Columns) {
+			return "", fmt.Errorf("findCol index out of range")
+		}
+		return rel.matchColumn(rel.Columns[findCol], value, opts, args), nil
+	}
+
+	var parts []string
+	for _, col := range rel.Columns {
+		if isTextColumn(col) {
+			parts = append(parts, rel.matchColumn(col, value, opts, args))
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("no text columns to search")
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", nil
+}
+
+// findWhere combines the relation's filters, the match and an optional keyset
+// predicate, rendering them in placeholder order
+func (rel *Relation) findWhere(findCol int, value any, opts FindOptions, keyset func(*queryArgs) string, args *queryArgs) (string, error) {
+	var conditions []string
+	if filter := rel.filterPredicate(args); filter != "" {
+		conditions = append(conditions, filter)
+	}
+	match, err := rel.findPredicate(findCol, value, opts, args)
+	if err != nil {
+		return "", err
+	}
+	conditions = append(conditions, match)
+	if keyset != nil {
+		conditions = append(conditions, keyset(args))
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// FindRow searches for the nearest row after the current one matching value as opts
+// describe, in the order given by sortCols and then the key, wrapping around to the
+// nearest match before it. With opts.Backward the search goes up first. sortVals are
+// the current row's values for sortCols. Rows outside the relation's filters are skipped.
+// Returns: (keys of found row, true if found below/false if above, error)
+func (rel *Relation) FindRow(findCol int, value any, opts FindOptions, sortCols []SortColumn, sortVals []any, currentKeys []any) ([]any, bool, error) {
+	if !opts.AllColumns && (findCol < 0 || findCol >= len(rel.Columns)) {
+		return nil, false, fmt.Errorf("findCol index out of range")
+	}
+	if len(currentKeys) != len(rel.Key) {
+		return nil, false, fmt.Errorf("currentKeys length mismatch: expected %d, got %d", len(rel.Key), len(currentKeys))
+	}
+	if len(sortVals) != len(sortCols) {
+		return nil, false, fmt.Errorf("sortVals length mismatch: expected %d, got %d", len(sortCols), len(sortVals))
+	}
+
+	keyCols := make([]string, len(rel.Key))
+	for i, keyIdx := range rel.Key {
+		keyCols[i] = quoteIdent(rel.DBType, rel.Columns[keyIdx].Name)
+	}
+
+	terms := rel.orderTerms(sortCols)
+	cursor := append(append([]any{}, sortVals...), currentKeys...)
+	foundKeys := make([]any, len(rel.Key))
+	scanArgs := make([]any, len(rel.Key))
+	for i := range foundKeys {
+		scanArgs[i] = &foundKeys[i]
+	}
+
+	// Search in the find direction, then wrap around to the nearest match the other way
+	directions := []bool{true, false}
+	if opts.Backward {
+		directions = []bool{false, true}
+	}
+	for i, below := range directions {
+		args := &queryArgs{dbType: rel.DBType}
+		where, err := rel.findWhere(findCol, value, opts, func(args *queryArgs) string {
+			return keysetPredicate(terms, cursor, below, false, args)
+		}, args)
+		if err != nil {
+			return nil, false, err
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT 1",
+			strings.Join(keyCols, ", "), rel.source(), where, orderByClause(terms, below))
+
+		err = rel.DB.QueryRow(query, args.values...).Scan(scanArgs...)
+		if err == nil {
+			return foundKeys, below, nil
+		}
+		if err != sql.ErrNoRows {
+			if i == 0 {
+				return nil, false, fmt.Errorf("search failed: %w", err)
+			}
+			return nil, false, fmt.Errorf("wrap search failed: %w", err)
+		}
+	}
+	return nil, false, nil // Not found at all
+}
+
+// CountMatches returns how many rows match value as opts describe, and how many of
+// those come up to and including the row with foundKeys in the order of sortCols and
+// the key. foundKeys may be nil to only count the total.
+func (rel *Relation) CountMatches(findCol int, value any, opts FindOptions, sortCols []SortColumn, foundKeys []any) (position, total int64, err error) {
+	count := func(keyset func(*queryArgs) string) (int64, error) {
+		args := &queryArgs{dbType: rel.DBType}
+		where, err := rel.findWhere(findCol, value, opts, keyset, args)
+		if err != nil {
+			return 0, err
+		}
+		var n int64
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", rel.source(), where)
+		if err := rel.DB.QueryRow(query, args.values...).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count matches: %w", err)
+		}
+		return n, nil
+	}
+
+	if total, err = count(nil); err != nil || foundKeys == nil {
+		return 0, total, err
+	}
+
+	cursor := foundKeys
+	if len(sortCols) > 0 {
+		if cursor, err = rel.sortCursor(sortCols, foundKeys); err != nil {
+			return 0, total, err
+		}
+	}
+	terms := rel.orderTerms(sortCols)
+	position, err = count(func(args *queryArgs) string {
+		return keysetPredicate(terms, cursor, false, true, args)
+	})
+	return position, total, err
+}
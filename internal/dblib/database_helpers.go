@@ -10,7 +10,8 @@ import (
 // direction is always secondarily sorted by key cols
 // `select col, ... from tbl where col > ?, ... order by sortCol, keyCol, ...`
 // for initial load, params are nil
-func selectQuery(dbType DatabaseType, tableName string, columns []string, sortCol *SortColumn, keyCols []string, hasParams, inclusive, scrollDown bool) (string, error) {
+// filter is an extra predicate ANDed in, its placeholders numbered after the key params
+func selectQuery(dbType DatabaseType, tableName string, columns []string, sortCol *SortColumn, keyCols []string, filter string, hasParams, inclusive, scrollDown bool) (string, error) {
 	if len(keyCols) == 0 {
 		panic("keyCols is empty")
 	}
@@ -67,6 +68,13 @@ func selectQuery(dbType DatabaseType, tableName string, columns []string, sortCo
 		builder.WriteString(colExpr)
 		builder.WriteString(operator)
 		builder.WriteString(placeholderExpr)
+		if filter != "" {
+			builder.WriteString(" AND ")
+			builder.WriteString(filter)
+		}
+	} else if filter != "" {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(filter)
 	}
 	builder.WriteString(" ORDER BY ")
 	if sortCol != nil {
@@ -206,13 +214,18 @@ func GetForeignRow(db *sql.DB, table *Relation, foreignKey map[string]any, colum
 // - isAbove=true means the updated row is above the first visible row
 // - isBelow=true means the updated row is below the last visible row
 // If both are false, the row is within the current viewport
-func CompareRowPosition(db *sql.DB, table *Relation, sortCol *SortColumn, updatedKeys, firstKeys, lastKeys []any) (bool, bool, error) {
+// When sorting, firstKeys and lastKeys hold the boundary rows' sort values followed
+// by their keys, and the updated row is compared using its stored sort values.
+func CompareRowPosition(db *sql.DB, table *Relation, sortCols []SortColumn, updatedKeys, firstKeys, lastKeys []any) (bool, bool, error) {
 	if len(updatedKeys) == 0 || len(firstKeys) == 0 || len(lastKeys) == 0 {
 		return false, false, fmt.Errorf("key data cannot be empty")
 	}
 	if len(table.Key) == 0 {
 		return false, false, fmt.Errorf("table has no key columns")
 	}
+	if len(sortCols) > 0 {
+		return table.compareSortedRowPosition(sortCols, updatedKeys, firstKeys, lastKeys)
+	}
 
 	// Build comparison using row value syntax for composite keys
 	// Query: SELECT (updated) < (first), (updated) > (last)
@@ -47,6 +47,16 @@ type DatabaseHandler interface {
 	LoadForeignKeys(db *sql.DB, dbType DatabaseType, tableName string,
 		columnIndex map[string]int, columns []Column) ([]Reference, []Column, error)
 
+	// LoadIncomingReferences finds foreign keys in other tables that reference tableName.
+	// Self-references are included. Column names in each IncomingReference map the
+	// referencing column to the referenced column of tableName.
+	// Returns an empty slice if the backend does not expose foreign key metadata.
+	LoadIncomingReferences(db *sql.DB, tableName string) ([]IncomingReference, error)
+
+	// ForeignKeysEnforced reports whether the database checks foreign keys and runs
+	// their ON DELETE actions. SQLite only does with PRAGMA foreign_keys on.
+	ForeignKeysEnforced(db *sql.DB) (bool, error)
+
 	// LoadEnumAndCustomTypes fetches enum values and custom type information for columns.
 	// For databases with ENUM types (PostgreSQL, MySQL), populates the Enum field.
 	// For databases without native ENUMs (SQLite), returns columns unchanged.
@@ -58,6 +68,11 @@ type DatabaseHandler interface {
 	// Returns an error if the view doesn't exist or cannot be accessed.
 	GetViewDefinition(db *sql.DB, viewName string) (string, error)
 
+	// EstimateRowCount returns the row count the planner statistics record for a table,
+	// which is cheap to read but may be stale. ok is false when the database keeps no
+	// estimate or the table has never been analyzed, and the rows should be counted.
+	EstimateRowCount(db *sql.DB, tableName string) (count int64, ok bool, err error)
+
 	// Key selection methods
 
 	// GetBestKey identifies the best key column(s) for a relation using database system tables.
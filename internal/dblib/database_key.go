@@ -0,0 +1,108 @@
+package dblib
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeyNames returns the names of the key columns
+func (rel *Relation) KeyNames() []string {
+	names := make([]string, len(rel.Key))
+	for i, keyIdx := range rel.Key {
+		names[i] = rel.Columns[keyIdx].Name
+	}
+	return names
+}
+
+// ParseKey converts typed key values into the key of a row. Composite keys are given
+// comma-separated in key column order, quoting values that contain commas as in CSV.
+// Numbers and booleans are converted by the column type; other values stay text.
+func (rel *Relation) ParseKey(text string) ([]any, error) {
+	if len(rel.Key) == 0 {
+		return nil, fmt.Errorf("no lookup key configured")
+	}
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.TrimLeadingSpace = true
+	fields, err := reader.Read()
+	if err != nil || len(fields) != len(rel.Key) {
+		if len(rel.Key) == 1 {
+			return nil, fmt.Errorf("enter a value for %s", rel.Columns[rel.Key[0]].Name)
+		}
+		return nil, fmt.Errorf("enter %d comma-separated values for %s", len(rel.Key), strings.Join(rel.KeyNames(), ", "))
+	}
+
+	keys := make([]any, len(fields))
+	for i, keyIdx := range rel.Key {
+		raw := strings.TrimSpace(fields[i])
+		col := rel.Columns[keyIdx]
+		t := strings.ToLower(col.Type)
+		switch {
+		case strings.Contains(t, "bool"):
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", col.Name)
+			}
+			keys[i] = v
+		case strings.Contains(t, "int") || col.Name == "rowid":
+			v, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a whole number", col.Name)
+			}
+			keys[i] = v
+		case strings.Contains(t, "real") || strings.Contains(t, "double") || strings.Contains(t, "float") ||
+			strings.Contains(t, "numeric") || strings.Contains(t, "decimal"):
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", col.Name)
+			}
+			keys[i] = v
+		default:
+			keys[i] = fields[i]
+		}
+	}
+	return keys, nil
+}
+
+// NearestKey returns the key of the row with keys, or of the first row after it in
+// key order when there is none. Rows outside the relation's filters are skipped.
+// Returns: (key of the row found or nil when no row follows, true if it has keys exactly, error)
+func (rel *Relation) NearestKey(keys []any) ([]any, bool, error) {
+	if len(keys) != len(rel.Key) {
+		return nil, false, fmt.Errorf("keys length mismatch: expected %d, got %d", len(rel.Key), len(keys))
+	}
+
+	keyCols := make([]string, len(rel.Key))
+	for i, name := range rel.KeyNames() {
+		keyCols[i] = quoteIdent(rel.DBType, name)
+	}
+	terms := rel.orderTerms(nil)
+	args := &queryArgs{dbType: rel.DBType}
+	// CASE turns a comparison against NULL into false
+	exactExpr := fmt.Sprintf("CASE WHEN %s THEN 1 ELSE 0 END", rel.keyWhere(keys, args))
+	var conditions []string
+	if filter := rel.filterPredicate(args); filter != "" {
+		conditions = append(conditions, filter)
+	}
+	conditions = append(conditions, keysetPredicate(terms, keys, true, true, args))
+	query := fmt.Sprintf("SELECT %s, %s FROM %s WHERE %s ORDER BY %s LIMIT 1",
+		strings.Join(keyCols, ", "), exactExpr, rel.source(), strings.Join(conditions, " AND "), orderByClause(terms, true))
+
+	found := make([]any, len(rel.Key))
+	var exact bool
+	scanArgs := make([]any, len(found), len(found)+1)
+	for i := range found {
+		scanArgs[i] = &found[i]
+	}
+	scanArgs = append(scanArgs, &exact)
+	if err := rel.DB.QueryRow(query, args.values...).Scan(scanArgs...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to look up row: %w", err)
+	}
+
+	return found, exact, nil
+}
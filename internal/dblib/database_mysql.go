@@ -117,7 +117,7 @@ func getShortestLookupKeyMySQL(db *sql.DB, tableName string, sizeOf func(string,
 
 // loadColumnsMySQL loads columns for a MySQL table.
 func loadColumnsMySQL(db *sql.DB, tableName string) ([]Column, map[string]int, error) {
-	query := `SELECT column_name, data_type, is_nullable
+	query := `SELECT column_name, data_type, is_nullable, column_default, extra
 			FROM information_schema.columns
 			WHERE table_name = ? AND table_schema = DATABASE()
 			ORDER BY ordinal_position`
@@ -134,8 +134,10 @@ func loadColumnsMySQL(db *sql.DB, tableName string) ([]Column, map[string]int, e
 		var col Column
 		col.Reference = -1 // Initialize to -1 (not a foreign key)
 		var nullable string
+		var dflt sql.NullString
+		var extra string
 
-		err = rows.Scan(&col.Name, &col.Type, &nullable)
+		err = rows.Scan(&col.Name, &col.Type, &nullable, &dflt, &extra)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -143,6 +145,12 @@ func loadColumnsMySQL(db *sql.DB, tableName string) ([]Column, map[string]int, e
 		col.Nullable = strings.ToLower(nullable) == "yes"
 		col.Table = tableName // For tables, Table is the table name itself
 		col.BaseColumn = col.Name // For tables, BaseColumn is the same as Name
+		// extra holds auto_increment and VIRTUAL/STORED GENERATED markers
+		extra = strings.ToLower(extra)
+		col.Generated = strings.Contains(extra, "virtual generated") || strings.Contains(extra, "stored generated")
+		col.AutoIncrement = strings.Contains(extra, "auto_increment")
+		col.Default = dflt.String
+		col.HasDefault = dflt.Valid && !col.Generated
 
 		idx := len(columns)
 		columns = append(columns, col)
@@ -235,6 +243,42 @@ func loadForeignKeysMySQL(db *sql.DB, dbType DatabaseType, tableName string, col
 	return references, updatedColumns, nil
 }
 
+// loadIncomingReferencesMySQL loads foreign key constraints in the current schema that reference tableName.
+func loadIncomingReferencesMySQL(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	fkQuery := `
+            SELECT k.TABLE_NAME, k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_COLUMN_NAME, r.DELETE_RULE
+            FROM information_schema.KEY_COLUMN_USAGE k
+            JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+              ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.TABLE_NAME = k.TABLE_NAME
+             AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+            WHERE k.REFERENCED_TABLE_SCHEMA = DATABASE() AND k.REFERENCED_TABLE_NAME = ?
+            ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION`
+	rows, err := db.Query(fkQuery, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var references []IncomingReference
+	byName := map[string]int{}
+	for rows.Next() {
+		var refTable, cname, col, targetCol, onDelete string
+		if err := rows.Scan(&refTable, &cname, &col, &targetCol, &onDelete); err != nil {
+			return nil, err
+		}
+		// Constraint names are only unique per table
+		id := refTable + "." + cname
+		idx, ok := byName[id]
+		if !ok {
+			idx = len(references)
+			byName[id] = idx
+			references = append(references, IncomingReference{Table: refTable, Columns: make(map[string]string), OnDelete: onDelete})
+		}
+		references[idx].Columns[col] = targetCol
+	}
+	return references, rows.Err()
+}
+
 // loadEnumAndCustomTypesMySQL fetches enum values for MySQL columns.
 func loadEnumAndCustomTypesMySQL(db *sql.DB, tableName string, columns []Column) ([]Column, error) {
 	updatedColumns := make([]Column, len(columns))
@@ -526,6 +570,20 @@ func (h *MySQLHandler) LoadForeignKeys(db *sql.DB, dbType DatabaseType, tableNam
 	return loadForeignKeysMySQL(db, dbType, tableName, columnIndex, columns)
 }
 
+// LoadIncomingReferences finds foreign keys in other MySQL tables that reference tableName.
+func (h *MySQLHandler) LoadIncomingReferences(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	return loadIncomingReferencesMySQL(db, tableName)
+}
+
+// ForeignKeysEnforced reads MySQL's foreign_key_checks session variable.
+func (h *MySQLHandler) ForeignKeysEnforced(db *sql.DB) (bool, error) {
+	var enabled bool
+	if err := db.QueryRow("SELECT @@foreign_key_checks").Scan(&enabled); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
 // LoadEnumAndCustomTypes fetches enum values for MySQL columns.
 func (h *MySQLHandler) LoadEnumAndCustomTypes(db *sql.DB, tableName string, columns []Column) ([]Column, error) {
 	return loadEnumAndCustomTypesMySQL(db, tableName, columns)
@@ -536,6 +594,21 @@ func (h *MySQLHandler) GetViewDefinition(db *sql.DB, viewName string) (string, e
 	return getViewDefinitionMySQL(db, viewName)
 }
 
+// EstimateRowCount reads InnoDB's row estimate for a MySQL table from information_schema.
+func (h *MySQLHandler) EstimateRowCount(db *sql.DB, tableName string) (int64, bool, error) {
+	var count sql.NullInt64
+	err := db.QueryRow(`SELECT TABLE_ROWS FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`, tableName).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read row estimate: %w", err)
+	}
+	// Views have no TABLE_ROWS
+	return count.Int64, count.Valid, nil
+}
+
 // GetBestKey identifies the best key column(s) for a MySQL table.
 func (h *MySQLHandler) GetBestKey(db *sql.DB, tableName string) ([]string, error) {
 	return getBestKeyMySQL(db, tableName)
@@ -126,7 +126,8 @@ func loadColumnsPostgreSQL(db *sql.DB, tableName string) ([]Column, map[string]i
 		rel = rel[dot+1:]
 	}
 
-	query := `SELECT column_name, data_type, is_nullable
+	query := `SELECT column_name, data_type, is_nullable, column_default,
+			       is_identity = 'YES', is_generated = 'ALWAYS'
 			FROM information_schema.columns
 			WHERE table_schema = $1 AND table_name = $2
 			ORDER BY ordinal_position`
@@ -143,8 +144,10 @@ func loadColumnsPostgreSQL(db *sql.DB, tableName string) ([]Column, map[string]i
 		var col Column
 		col.Reference = -1 // Initialize to -1 (not a foreign key)
 		var nullable string
+		var dflt sql.NullString
+		var identity, generated bool
 
-		err = rows.Scan(&col.Name, &col.Type, &nullable)
+		err = rows.Scan(&col.Name, &col.Type, &nullable, &dflt, &identity, &generated)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -152,6 +155,11 @@ func loadColumnsPostgreSQL(db *sql.DB, tableName string) ([]Column, map[string]i
 		col.Nullable = strings.ToLower(nullable) == "yes"
 		col.Table = tableName     // For tables, Table is the table name itself
 		col.BaseColumn = col.Name // For tables, BaseColumn is the same as Name
+		col.Generated = generated
+		col.Default = dflt.String
+		col.HasDefault = dflt.Valid && !generated
+		// serial columns are backed by a sequence default
+		col.AutoIncrement = identity || strings.HasPrefix(dflt.String, "nextval(")
 
 		idx := len(columns)
 		columns = append(columns, col)
@@ -257,6 +265,59 @@ func loadForeignKeysPostgreSQL(db *sql.DB, dbType DatabaseType, tableName string
 	return references, updatedColumns, nil
 }
 
+// loadIncomingReferencesPostgreSQL loads foreign key constraints in any schema that reference tableName.
+func loadIncomingReferencesPostgreSQL(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	schema := "public"
+	rel := tableName
+	if dot := strings.IndexByte(rel, '.'); dot != -1 {
+		schema = rel[:dot]
+		rel = rel[dot+1:]
+	}
+	fkQuery := `
+            SELECT con.oid::text AS id, nsp.nspname AS ref_schema, crel.relname AS ref_table,
+                   att.attname AS col, u.ord AS ord, fatt.attname AS target_col,
+                   CASE con.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL'
+                        WHEN 'd' THEN 'SET DEFAULT' WHEN 'r' THEN 'RESTRICT' ELSE 'NO ACTION' END AS on_delete
+            FROM pg_constraint con
+            JOIN pg_class frel ON frel.oid = con.confrelid
+            JOIN pg_namespace fnsp ON fnsp.oid = frel.relnamespace
+            JOIN pg_class crel ON crel.oid = con.conrelid
+            JOIN pg_namespace nsp ON nsp.oid = crel.relnamespace
+            JOIN unnest(con.conkey) WITH ORDINALITY AS u(attnum, ord) ON true
+            JOIN pg_attribute att ON att.attrelid = crel.oid AND att.attnum = u.attnum
+            JOIN unnest(con.confkey) WITH ORDINALITY AS fu(attnum, ord) ON fu.ord = u.ord
+            JOIN pg_attribute fatt ON fatt.attrelid = frel.oid AND fatt.attnum = fu.attnum
+            WHERE con.contype = 'f' AND frel.relname = $1 AND fnsp.nspname = $2
+            ORDER BY nsp.nspname, crel.relname, con.oid, u.ord`
+	rows, err := db.Query(fkQuery, rel, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var references []IncomingReference
+	byID := map[string]int{}
+	for rows.Next() {
+		var id, refSchema, refTable, col, targetCol, onDelete string
+		var ord int
+		if err := rows.Scan(&id, &refSchema, &refTable, &col, &ord, &targetCol, &onDelete); err != nil {
+			return nil, err
+		}
+		idx, ok := byID[id]
+		if !ok {
+			name := refTable
+			if refSchema != "public" {
+				name = refSchema + "." + refTable
+			}
+			idx = len(references)
+			byID[id] = idx
+			references = append(references, IncomingReference{Table: name, Columns: make(map[string]string), OnDelete: onDelete})
+		}
+		references[idx].Columns[col] = targetCol
+	}
+	return references, rows.Err()
+}
+
 // getBestKeyPostgreSQL identifies the best key for a PostgreSQL table using system catalogs.
 // Ranking: primary key > unique (NOT NULL/NULLS NOT DISTINCT) > fewer columns > shorter > earlier.
 func getBestKeyPostgreSQL(db *sql.DB, tableName string) ([]string, error) {
@@ -565,6 +626,16 @@ func (h *PostgresHandler) LoadForeignKeys(db *sql.DB, dbType DatabaseType, table
 	return loadForeignKeysPostgreSQL(db, dbType, tableName, columnIndex, columns)
 }
 
+// LoadIncomingReferences finds foreign keys in other PostgreSQL tables that reference tableName.
+func (h *PostgresHandler) LoadIncomingReferences(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	return loadIncomingReferencesPostgreSQL(db, tableName)
+}
+
+// ForeignKeysEnforced reports that PostgreSQL always enforces foreign keys.
+func (h *PostgresHandler) ForeignKeysEnforced(db *sql.DB) (bool, error) {
+	return true, nil
+}
+
 // LoadEnumAndCustomTypes fetches enum values for PostgreSQL columns.
 func (h *PostgresHandler) LoadEnumAndCustomTypes(db *sql.DB, tableName string, columns []Column) ([]Column, error) {
 	return loadEnumAndCustomTypesPostgreSQL(db, tableName, columns)
@@ -575,6 +646,21 @@ func (h *PostgresHandler) GetViewDefinition(db *sql.DB, viewName string) (string
 	return getViewDefinitionPostgreSQL(db, viewName)
 }
 
+// EstimateRowCount reads the planner's row estimate for a PostgreSQL table from pg_class.
+func (h *PostgresHandler) EstimateRowCount(db *sql.DB, tableName string) (int64, bool, error) {
+	var count sql.NullInt64
+	err := db.QueryRow(`SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)`,
+		quoteQualified(PostgreSQL, tableName)).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read row estimate: %w", err)
+	}
+	// reltuples is -1 until the table is first analyzed
+	return count.Int64, count.Valid && count.Int64 >= 0, nil
+}
+
 // GetBestKey identifies the best key column(s) for a PostgreSQL table.
 func (h *PostgresHandler) GetBestKey(db *sql.DB, tableName string) ([]string, error) {
 	return getBestKeyPostgreSQL(db, tableName)
@@ -0,0 +1,269 @@
+package dblib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ReplaceChange is one row a replace rewrites
+type ReplaceChange struct {
+	Keys     []any // key of the row in the relation
+	baseKeys []any // key of the row in the table written to
+	Before   any
+	After    string
+}
+
+// ReplacePlan is a find and replace in one column, worked out ahead of running it so
+// it can be previewed. Rows are only rewritten if a text or integer column still
+// holds the value seen when planning; other values may not compare equal after a
+// round trip.
+type ReplacePlan struct {
+	Table   string          // table the UPDATE writes to
+	Column  string          // column in Table
+	Matched int             // rows the find matched, including those the replacement leaves alone
+	Changes []ReplaceChange // rows whose value changes, in key order
+
+	dbType   DatabaseType
+	keyNames []string // key columns of Table
+	guard    bool     // whether the UPDATE checks the column still holds Before
+}
+
+// replacer returns a function rewriting a value found with opts. Literal modes replace
+// the found text with replacement as is; regex mode expands $1 and ${name} groups and
+// also returns the expression, as values are matched with it rather than with the
+// database's regex dialect.
+func replacer(find, replacement string, opts FindOptions) (func(string) string, *regexp.Regexp, error) {
+	if find == "" && opts.Mode != FindExact {
+		return nil, nil, fmt.Errorf("nothing to find")
+	}
+	flags := ""
+	if opts.IgnoreCase {
+		flags = "(?i)"
+	}
+
+	switch opts.Mode {
+	case FindPrefix, FindSubstring:
+		pattern := regexp.QuoteMeta(find)
+		if opts.Mode == FindPrefix {
+			pattern = "^" + pattern
+		}
+		re := regexp.MustCompile(flags + pattern)
+		return func(s string) string { return re.ReplaceAllLiteralString(s, replacement) }, nil, nil
+	case FindRegex:
+		re, err := regexp.Compile(flags + find)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return func(s string) string { return re.ReplaceAllString(s, replacement) }, re, nil
+	default:
+		return func(s string) string {
+			if s == find || (opts.IgnoreCase && strings.EqualFold(s, find)) {
+				return replacement
+			}
+			return s
+		}, nil, nil
+	}
+}
+
+// guardsValue reports whether a column's values read back compare equal in a WHERE
+// clause: text and integers do, while floats, times and JSON may not
+func guardsValue(col Column) bool {
+	t := strings.ToLower(col.Type)
+	if strings.Contains(t, "json") || strings.Contains(t, "interval") || strings.Contains(t, "point") {
+		return false
+	}
+	return isTextColumn(col) || strings.Contains(t, "int")
+}
+
+// replaceText returns the text a replace works on for a column value
+func replaceText(v any) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return val, true
+	case []byte:
+		return string(val), true
+	default:
+		return fmt.Sprint(val), true
+	}
+}
+
+// PlanReplace finds the rows whose column at findCol matches find as opts describe,
+// within the relation's filters, and works out their replaced values. Only columns
+// that IsColumnEditable allows can be replaced; views and custom SQL write to the
+// column's base table.
+func (rel *Relation) PlanReplace(findCol int, find, replacement string, opts FindOptions) (*ReplacePlan, error) {
+	if findCol < 0 || findCol >= len(rel.Columns) {
+		return nil, fmt.Errorf("findCol index out of range")
+	}
+	if len(rel.Key) == 0 {
+		return nil, fmt.Errorf("no lookup key configured")
+	}
+	col := rel.Columns[findCol]
+	if !rel.IsColumnEditable(findCol) {
+		return nil, fmt.Errorf("column %s is not editable", col.Name)
+	}
+	replace, match, err := replacer(find, replacement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ReplacePlan{Table: rel.Name, Column: col.Name, dbType: rel.DBType, guard: guardsValue(col)}
+	baseKey := rel.Key
+	if rel.IsView || rel.IsCustomSQL {
+		table, ok := rel.Tables[col.Table]
+		if !ok {
+			return nil, fmt.Errorf("base table %s not found", col.Table)
+		}
+		plan.Table, baseKey = table.Name, table.Key
+		if col.BaseColumn != "" {
+			plan.Column = col.BaseColumn
+		}
+	}
+	for _, keyIdx := range baseKey {
+		name := rel.Columns[keyIdx].Name
+		if (rel.IsView || rel.IsCustomSQL) && rel.Columns[keyIdx].BaseColumn != "" {
+			name = rel.Columns[keyIdx].BaseColumn
+		}
+		plan.keyNames = append(plan.keyNames, name)
+	}
+
+	// Select the relation key, the base key and the value
+	var selectCols []string
+	for _, keyIdx := range append(append([]int{}, rel.Key...), baseKey...) {
+		selectCols = append(selectCols, quoteIdent(rel.DBType, rel.Columns[keyIdx].Name))
+	}
+	selectCols = append(selectCols, quoteIdent(rel.DBType, col.Name))
+
+	opts.AllColumns = false
+	args := &queryArgs{dbType: rel.DBType}
+	var where string
+	if match != nil {
+		// Regex matches are found below, among the values the filters leave
+		where = quoteIdent(rel.DBType, col.Name) + " IS NOT NULL"
+		if filter := rel.filterPredicate(args); filter != "" {
+			where = filter + " AND " + where
+		}
+	} else if where, err = rel.findWhere(findCol, find, opts, nil, args); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s",
+		strings.Join(selectCols, ", "), rel.source(), where, orderByClause(rel.orderTerms(nil), true))
+	rows, err := rel.DB.Query(query, args.values...)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]any, len(selectCols))
+	scanArgs := make([]any, len(values))
+	for rows.Next() {
+		for i := range values {
+			values[i] = nil
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		before := values[len(values)-1]
+		text, ok := replaceText(before)
+		if !ok || (match != nil && !match.MatchString(text)) {
+			continue
+		}
+		plan.Matched++
+		after := replace(text)
+		if after == text {
+			continue
+		}
+		plan.Changes = append(plan.Changes, ReplaceChange{
+			Keys:     append([]any{}, values[:len(rel.Key)]...),
+			baseKeys: append([]any{}, values[len(rel.Key):len(values)-1]...),
+			Before:   before,
+			After:    after,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	return plan, nil
+}
+
+// statement renders the UPDATE for one change. When the plan guards the column, it
+// only matches the row while the column still holds the value the plan saw.
+func (p *ReplacePlan) statement(change ReplaceChange) (string, []any) {
+	args := &queryArgs{dbType: p.dbType}
+	column := quoteIdent(p.dbType, p.Column)
+	set := fmt.Sprintf("%s = %s", column, args.add(change.After))
+	where := make([]string, 0, len(p.keyNames)+1)
+	for i, name := range p.keyNames {
+		where = append(where, fmt.Sprintf("%s = %s", quoteIdent(p.dbType, name), args.add(change.baseKeys[i])))
+	}
+	if p.guard {
+		where = append(where, fmt.Sprintf("%s = %s", column, args.add(change.Before)))
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteQualified(p.dbType, p.Table), set, strings.Join(where, " AND ")), args.values
+}
+
+// Statements returns the plan's UPDATEs as a script, one per changed row
+func (p *ReplacePlan) Statements() []string {
+	statements := make([]string, len(p.Changes))
+	for i, change := range p.Changes {
+		query, params := p.statement(change)
+		statements[i] = InlineParams(p.dbType, query, params)
+	}
+	return statements
+}
+
+// Preview returns the UPDATE for the first changed row, noting how many follow it
+func (p *ReplacePlan) Preview() string {
+	if len(p.Changes) == 0 {
+		return ""
+	}
+	query, params := p.statement(p.Changes[0])
+	preview := InlineParams(p.dbType, query, params)
+	if more := len(p.Changes) - 1; more > 0 {
+		preview += fmt.Sprintf(" (+%d more)", more)
+	}
+	return preview
+}
+
+// ExecuteReplace runs the plan's UPDATEs in one transaction. If any row was removed
+// since the plan was made, or its guarded column changed, nothing is written.
+func (rel *Relation) ExecuteReplace(plan *ReplacePlan) error {
+	tx, err := rel.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx failed: %w", err)
+	}
+	for _, change := range plan.Changes {
+		query, args := plan.statement(change)
+		res, err := tx.Exec(query, args...)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("update failed: %w", err)
+		}
+		if ra, _ := res.RowsAffected(); ra == 0 {
+			_ = tx.Rollback()
+			return fmt.Errorf("row %v changed since the preview, nothing was replaced", change.Keys)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	for _, change := range plan.Changes {
+		query, args := plan.statement(change)
+		before := make(map[string]any, len(plan.keyNames)+1)
+		for i, name := range plan.keyNames {
+			before[name] = change.baseKeys[i]
+		}
+		after := make(map[string]any, len(before))
+		for name, v := range before {
+			after[name] = v
+		}
+		before[plan.Column], after[plan.Column] = change.Before, change.After
+		rel.notifyWrite("UPDATE", plan.Table, query, args, before, after)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package dblib
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// orderTerm is one column of a keyset ordering: the sort columns followed by the key
+type orderTerm struct {
+	expr     string // quoted column
+	asc      bool
+	nullable bool // NULLs sort after every value, so ascending puts them last
+}
+
+// queryArgs collects bind values while a query is built, numbering placeholders in order
+type queryArgs struct {
+	dbType DatabaseType
+	values []any
+}
+
+// add binds v and returns its placeholder
+func (a *queryArgs) add(v any) string {
+	a.values = append(a.values, v)
+	if databaseFeatures[a.dbType].positionalPlaceholder {
+		return fmt.Sprintf("$%d", len(a.values))
+	}
+	return "?"
+}
+
+// orderTerms returns the ordering for sortCols followed by the key columns
+func (rel *Relation) orderTerms(sortCols []SortColumn) []orderTerm {
+	terms := make([]orderTerm, 0, len(sortCols)+len(rel.Key))
+	for _, sc := range sortCols {
+		nullable := true
+		if idx, ok := rel.ColumnIndex[sc.Name]; ok && idx < len(rel.Columns) {
+			nullable = rel.Columns[idx].Nullable
+		}
+		terms = append(terms, orderTerm{expr: quoteIdent(rel.DBType, sc.Name), asc: sc.Asc, nullable: nullable})
+	}
+	for _, keyIdx := range rel.Key {
+		terms = append(terms, orderTerm{expr: quoteIdent(rel.DBType, rel.Columns[keyIdx].Name), asc: true})
+	}
+	return terms
+}
+
+// orderByClause renders terms as an ORDER BY list, reversed when forward is false.
+// NULLs are ordered explicitly since databases disagree on where they go.
+func orderByClause(terms []orderTerm, forward bool) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		dir := "ASC"
+		if t.asc != forward {
+			dir = "DESC"
+		}
+		if t.nullable {
+			parts = append(parts, fmt.Sprintf("(%s IS NULL) %s", t.expr, dir))
+		}
+		parts = append(parts, t.expr+" "+dir)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetPredicate builds a WHERE clause matching the rows after the cursor in the
+// order of terms, or before it when forward is false. cursor holds one value per term.
+// Row value syntax can't express mixed directions or NULLs, so the comparison is
+// expanded: (t1 > v1) OR (t1 = v1 AND t2 > v2) OR ...
+func keysetPredicate(terms []orderTerm, cursor []any, forward, inclusive bool, args *queryArgs) string {
+	// Placeholders are bound in the order they appear, so each comparison is rendered
+	// where it is used rather than shared between alternatives
+	equal := func(i int) string {
+		if cursor[i] == nil {
+			return terms[i].expr + " IS NULL"
+		}
+		return fmt.Sprintf("%s = %s", terms[i].expr, args.add(cursor[i]))
+	}
+	beyond := func(i int) string {
+		t, val := terms[i], cursor[i]
+		switch {
+		case val == nil:
+			return t.expr + " IS NOT NULL"
+		case t.asc != forward:
+			return fmt.Sprintf("%s < %s", t.expr, args.add(val))
+		case t.nullable:
+			return fmt.Sprintf("(%s > %s OR %s IS NULL)", t.expr, args.add(val), t.expr)
+		default:
+			return fmt.Sprintf("%s > %s", t.expr, args.add(val))
+		}
+	}
+
+	var alternatives []string
+	for i, t := range terms {
+		if cursor[i] == nil && t.asc == forward {
+			continue // nothing sorts after NULL
+		}
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, equal(j))
+		}
+		parts = append(parts, beyond(i))
+		alternatives = append(alternatives, strings.Join(parts, " AND "))
+	}
+	if inclusive {
+		parts := make([]string, len(terms))
+		for i := range terms {
+			parts[i] = equal(i)
+		}
+		alternatives = append(alternatives, strings.Join(parts, " AND "))
+	}
+	if len(alternatives) == 0 {
+		return "1 = 0"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// source returns the FROM clause target for the relation
+func (rel *Relation) source() string {
+	if rel.IsCustomSQL {
+		return "(" + rel.SQLStatement + ") AS custom_query"
+	}
+	return quoteQualified(rel.DBType, rel.Name)
+}
+
+// keyWhere matches the row with the given key values
+func (rel *Relation) keyWhere(keys []any, args *queryArgs) string {
+	parts := make([]string, len(rel.Key))
+	for i, keyIdx := range rel.Key {
+		parts[i] = fmt.Sprintf("%s = %s", quoteIdent(rel.DBType, rel.Columns[keyIdx].Name), args.add(keys[i]))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// sortCursor returns the sort values of the row with the given key followed by the key,
+// the cursor that positions a sorted query at that row
+func (rel *Relation) sortCursor(sortCols []SortColumn, keys []any) ([]any, error) {
+	cols := make([]string, len(sortCols))
+	for i, sc := range sortCols {
+		cols[i] = quoteIdent(rel.DBType, sc.Name)
+	}
+	args := &queryArgs{dbType: rel.DBType}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(cols, ", "), rel.source(), rel.keyWhere(keys, args))
+
+	values := make([]any, len(sortCols))
+	scanArgs := make([]any, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rel.DB.QueryRow(query, args.values...).Scan(scanArgs...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("row to position at no longer exists")
+		}
+		return nil, fmt.Errorf("failed to read sort values: %w", err)
+	}
+	return append(values, keys...), nil
+}
+
+// querySortedRows pages through the rows matching the relation's filter, ordered by
+// sortCols and then the key. cursor is either the key of the row to start at, or its
+// sort values followed by the key.
+func (rel *Relation) querySortedRows(columns []string, sortCols []SortColumn, cursor []any, inclusive, scrollDown bool) (*sql.Rows, error) {
+	terms := rel.orderTerms(sortCols)
+	if len(sortCols) > 0 && len(cursor) > 0 && len(cursor) == len(rel.Key) {
+		var err error
+		if cursor, err = rel.sortCursor(sortCols, cursor); err != nil {
+			return nil, err
+		}
+	}
+	if len(cursor) > 0 && len(cursor) != len(terms) {
+		return nil, fmt.Errorf("cursor has %d values, expected %d", len(cursor), len(terms))
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = quoteIdent(rel.DBType, col)
+	}
+	args := &queryArgs{dbType: rel.DBType}
+	var builder strings.Builder
+	builder.WriteString("SELECT ")
+	builder.WriteString(strings.Join(quotedColumns, ", "))
+	builder.WriteString(" FROM ")
+	builder.WriteString(rel.source())
+	var conditions []string
+	if filter := rel.filterPredicate(args); filter != "" {
+		conditions = append(conditions, filter)
+	}
+	if len(cursor) > 0 {
+		conditions = append(conditions, keysetPredicate(terms, cursor, scrollDown, inclusive, args))
+	}
+	if len(conditions) > 0 {
+		builder.WriteString(" WHERE ")
+		builder.WriteString(strings.Join(conditions, " AND "))
+	}
+	builder.WriteString(" ORDER BY ")
+	builder.WriteString(orderByClause(terms, scrollDown))
+
+	return rel.DB.Query(builder.String(), args.values...)
+}
+
+// compareSortedRowPosition reports whether the row with updatedKeys sorts before
+// the first cursor or after the last one
+func (rel *Relation) compareSortedRowPosition(sortCols []SortColumn, updatedKeys, firstCursor, lastCursor []any) (bool, bool, error) {
+	terms := rel.orderTerms(sortCols)
+	if len(firstCursor) != len(terms) || len(lastCursor) != len(terms) {
+		return false, false, fmt.Errorf("cursor length mismatch: expected %d values", len(terms))
+	}
+	args := &queryArgs{dbType: rel.DBType}
+	// CASE turns comparisons against NULL into false
+	query := fmt.Sprintf("SELECT CASE WHEN %s THEN 1 ELSE 0 END AS is_above, CASE WHEN %s THEN 1 ELSE 0 END AS is_below FROM %s WHERE %s",
+		keysetPredicate(terms, firstCursor, false, false, args),
+		keysetPredicate(terms, lastCursor, true, false, args),
+		rel.source(), rel.keyWhere(updatedKeys, args))
+
+	var isAbove, isBelow bool
+	if err := rel.DB.QueryRow(query, args.values...).Scan(&isAbove, &isBelow); err != nil {
+		return false, false, fmt.Errorf("failed to compare row positions: %w", err)
+	}
+	return isAbove, isBelow, nil
+}
@@ -3,11 +3,54 @@ package dblib
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
 )
 
+// SQLiteDriverName is go-sqlite3 registered with the regexp function behind SQLite's
+// REGEXP operator, which SQLite leaves for the application to provide
+const SQLiteDriverName = "sqlite3_ted"
+
+func init() {
+	sql.Register(SQLiteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+}
+
+// sqliteRegexps caches compiled patterns, since regexp runs once per row
+var sqliteRegexps sync.Map
+
+// sqliteRegexp implements X REGEXP Y, which SQLite calls as regexp(Y, X)
+func sqliteRegexp(pattern string, value any) (bool, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		text = fmt.Sprint(v)
+	}
+	re, ok := sqliteRegexps.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		re, _ = sqliteRegexps.LoadOrStore(pattern, compiled)
+	}
+	return re.(*regexp.Regexp).MatchString(text), nil
+}
+
 // SQLiteHandler implements DatabaseHandler for SQLite databases.
 // It maintains state for primary key columns to use as fallback in GetShortestLookupKey.
 type SQLiteHandler struct {
@@ -144,7 +187,9 @@ func getShortestLookupKeySQLite(db *sql.DB, tableName string, sizeOf func(string
 // Returns 4 values including primary key columns for internal use.
 // This is an internal helper used by loadColumnsSQLite and SQLiteHandler.
 func loadColumnsSQLiteWithPrimaryKeys(db *sql.DB, tableName string) ([]Column, map[string]int, []string, error) {
-	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
+	// table_xinfo includes generated columns; hidden is 1 for virtual table
+	// hidden columns and 2/3 for virtual/stored generated columns
+	query := fmt.Sprintf("PRAGMA table_xinfo(%s)", tableName)
 	rows, err := db.Query(query)
 	if err != nil {
 		return nil, nil, nil, err
@@ -154,6 +199,7 @@ func loadColumnsSQLiteWithPrimaryKeys(db *sql.DB, tableName string) ([]Column, m
 	var columns []Column
 	columnIndex := make(map[string]int)
 	var primaryKeyColumns []string
+	pkCount := 0
 
 	for rows.Next() {
 		var col Column
@@ -162,18 +208,28 @@ func loadColumnsSQLiteWithPrimaryKeys(db *sql.DB, tableName string) ([]Column, m
 		var cid int
 		var dfltValue sql.NullString
 		var pk int
+		var hidden int
 
-		err = rows.Scan(&cid, &col.Name, &col.Type, &nullable, &dfltValue, &pk)
+		err = rows.Scan(&cid, &col.Name, &col.Type, &nullable, &dfltValue, &pk, &hidden)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		if hidden == 1 {
+			continue
+		}
 
 		col.Nullable = nullable != "1"
 		col.Table = tableName     // For tables, Table is the table name itself
 		col.BaseColumn = col.Name // For tables, BaseColumn is the same as Name
+		col.Generated = hidden >= 2
+		col.Default = dfltValue.String
+		col.HasDefault = dfltValue.Valid
 		if pk == 1 {
 			primaryKeyColumns = append(primaryKeyColumns, col.Name)
 		}
+		if pk > 0 {
+			pkCount++
+		}
 
 		idx := len(columns)
 		columns = append(columns, col)
@@ -184,6 +240,14 @@ func loadColumnsSQLiteWithPrimaryKeys(db *sql.DB, tableName string) ([]Column, m
 		return nil, nil, nil, err
 	}
 
+	// A lone INTEGER PRIMARY KEY aliases the rowid and is assigned on insert
+	if pkCount == 1 && len(primaryKeyColumns) == 1 {
+		idx := columnIndex[primaryKeyColumns[0]]
+		if strings.EqualFold(columns[idx].Type, "INTEGER") {
+			columns[idx].AutoIncrement = true
+		}
+	}
+
 	return columns, columnIndex, primaryKeyColumns, nil
 }
 
@@ -527,6 +591,110 @@ func sizeOf(typ string, charLen int) int {
 	}
 }
 
+// loadIncomingReferencesSQLite scans every table's foreign keys for references to tableName.
+// SQLite has no catalog view of inbound foreign keys, so each table is inspected with PRAGMA.
+func loadIncomingReferencesSQLite(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	tableRows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for tableRows.Next() {
+		var name string
+		if err := tableRows.Scan(&name); err != nil {
+			tableRows.Close()
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	tableRows.Close()
+	if err := tableRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Primary key columns of the target, used when a foreign key omits the referenced columns
+	type pkEntry struct {
+		ord  int
+		name string
+	}
+	var pkEntries []pkEntry
+	ti, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(SQLite, tableName)))
+	if err != nil {
+		return nil, err
+	}
+	for ti.Next() {
+		var cid, notnull, pk int
+		var cname, ctype string
+		var dflt sql.NullString
+		if err := ti.Scan(&cid, &cname, &ctype, &notnull, &dflt, &pk); err != nil {
+			ti.Close()
+			return nil, err
+		}
+		if pk > 0 {
+			pkEntries = append(pkEntries, pkEntry{ord: pk, name: cname})
+		}
+	}
+	ti.Close()
+	if err := ti.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(pkEntries, func(i, j int) bool { return pkEntries[i].ord < pkEntries[j].ord })
+
+	var references []IncomingReference
+	for _, table := range tables {
+		// cols: id, seq, table, from, to, on_update, on_delete, match
+		fkRows, err := db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%s)", quoteIdent(SQLite, table)))
+		if err != nil {
+			return nil, err
+		}
+		type fkCol struct {
+			seq   int
+			col   string
+			toCol string
+		}
+		byID := map[int][]fkCol{}
+		onDelete := map[int]string{}
+		var ids []int
+		for fkRows.Next() {
+			var id, seq int
+			var refTable, fromCol, onUpd, onDel, match string
+			var toCol sql.NullString
+			if err := fkRows.Scan(&id, &seq, &refTable, &fromCol, &toCol, &onUpd, &onDel, &match); err != nil {
+				fkRows.Close()
+				return nil, err
+			}
+			if !strings.EqualFold(refTable, tableName) {
+				continue
+			}
+			if _, ok := byID[id]; !ok {
+				ids = append(ids, id)
+			}
+			byID[id] = append(byID[id], fkCol{seq: seq, col: fromCol, toCol: toCol.String})
+			onDelete[id] = strings.ToUpper(onDel)
+		}
+		fkRows.Close()
+		if err := fkRows.Err(); err != nil {
+			return nil, err
+		}
+
+		sort.Ints(ids)
+		for _, id := range ids {
+			cols := byID[id]
+			sort.Slice(cols, func(i, j int) bool { return cols[i].seq < cols[j].seq })
+			ref := IncomingReference{Table: table, Columns: make(map[string]string, len(cols)), OnDelete: onDelete[id]}
+			for i, c := range cols {
+				toCol := c.toCol
+				if toCol == "" && i < len(pkEntries) {
+					toCol = pkEntries[i].name
+				}
+				ref.Columns[c.col] = toCol
+			}
+			references = append(references, ref)
+		}
+	}
+	return references, nil
+}
+
 // DatabaseHandler interface implementation for SQLiteHandler
 
 // CheckIsView returns true if the named relation is a view, false if it's a table.
@@ -557,6 +725,20 @@ func (h *SQLiteHandler) LoadForeignKeys(db *sql.DB, dbType DatabaseType, tableNa
 	return loadForeignKeysSQLite(db, dbType, tableName, columnIndex, columns)
 }
 
+// LoadIncomingReferences finds foreign keys in other SQLite tables that reference tableName.
+func (h *SQLiteHandler) LoadIncomingReferences(db *sql.DB, tableName string) ([]IncomingReference, error) {
+	return loadIncomingReferencesSQLite(db, tableName)
+}
+
+// ForeignKeysEnforced reads SQLite's foreign_keys pragma, which is off by default.
+func (h *SQLiteHandler) ForeignKeysEnforced(db *sql.DB) (bool, error) {
+	var enabled bool
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&enabled); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
 // LoadEnumAndCustomTypes is a no-op for SQLite (no native ENUM support).
 func (h *SQLiteHandler) LoadEnumAndCustomTypes(db *sql.DB, tableName string, columns []Column) ([]Column, error) {
 	return loadEnumAndCustomTypesSQLite(db, tableName, columns)
@@ -567,6 +749,11 @@ func (h *SQLiteHandler) GetViewDefinition(db *sql.DB, viewName string) (string,
 	return getViewDefinitionSQLite(db, viewName)
 }
 
+// EstimateRowCount returns no estimate for SQLite, whose rows are always counted.
+func (h *SQLiteHandler) EstimateRowCount(db *sql.DB, tableName string) (int64, bool, error) {
+	return 0, false, nil
+}
+
 // GetBestKey identifies the best key column(s) for a SQLite table.
 func (h *SQLiteHandler) GetBestKey(db *sql.DB, tableName string) ([]string, error) {
 	return getBestKeySQLite(db, tableName)
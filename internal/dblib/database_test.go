@@ -2,6 +2,7 @@ package dblib
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -74,9 +75,8 @@ func TestFindNextRow_FindBelow(t *testing.T) {
 	findCol := 2 // age column
 	findColVal := int64(25)
 	currentKeys := []any{int64(1)}
-	sortColVal := int64(1) // current row's id value
 
-	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, sortColVal, currentKeys)
+	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, nil, currentKeys)
 	if err != nil {
 		t.Fatalf("FindNextRow failed: %v", err)
 	}
@@ -103,9 +103,8 @@ func TestFindNextRow_WrapAround(t *testing.T) {
 	findCol := 2 // age column
 	findColVal := int64(25)
 	currentKeys := []any{int64(6)}
-	sortColVal := int64(6) // current row's id value
 
-	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, sortColVal, currentKeys)
+	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, nil, currentKeys)
 	if err != nil {
 		t.Fatalf("FindNextRow failed: %v", err)
 	}
@@ -132,9 +131,8 @@ func TestFindNextRow_NotFound(t *testing.T) {
 	findCol := 2 // age column
 	findColVal := int64(99)
 	currentKeys := []any{int64(1)}
-	sortColVal := int64(1)
 
-	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, sortColVal, currentKeys)
+	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, nil, currentKeys)
 	if err != nil {
 		t.Fatalf("FindNextRow failed: %v", err)
 	}
@@ -156,10 +154,10 @@ func TestFindNextRow_WithSortColumn(t *testing.T) {
 	findCol := 2 // age column
 	findColVal := int64(30)
 	currentKeys := []any{int64(1)} // Starting from Alice
-	sortCol := &SortColumn{Name: "age", Asc: true}
-	sortColVal := int64(30) // Alice's age
+	sortCols := []SortColumn{{Name: "age", Asc: true}}
+	sortVals := []any{int64(30)} // Alice's age
 
-	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, sortCol, sortColVal, currentKeys)
+	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, sortCols, sortVals, currentKeys)
 	if err != nil {
 		t.Fatalf("FindNextRow failed: %v", err)
 	}
@@ -203,6 +201,205 @@ func TestFindNextRow_KeyLengthMismatch(t *testing.T) {
 	}
 }
 
+// sortedIDs reads the id column of every row returned by a sorted QueryRows
+func sortedIDs(t *testing.T, rel *Relation, sortCols []SortColumn, cursor []any, inclusive, scrollDown bool) []int64 {
+	t.Helper()
+	rows, err := rel.QueryRows([]string{"id"}, sortCols, cursor, inclusive, scrollDown)
+	if err != nil {
+		t.Fatalf("QueryRows failed: %v", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestQueryRows_Filter(t *testing.T) {
+	_, rel := setupTestDB(t)
+	if err := rel.SetFilter(" age = 25 "); err != nil {
+		t.Fatalf("SetFilter failed: %v", err)
+	}
+
+	if got := sortedIDs(t, rel, nil, nil, false, true); fmt.Sprint(got) != "[2 4 6]" {
+		t.Errorf("Expected filtered ids [2 4 6], got %v", got)
+	}
+	if got := sortedIDs(t, rel, nil, []any{int64(2)}, false, true); fmt.Sprint(got) != "[4 6]" {
+		t.Errorf("Expected filtered ids after 2 to be [4 6], got %v", got)
+	}
+	if got := sortedIDs(t, rel, []SortColumn{{Name: "name"}}, nil, false, true); fmt.Sprint(got) != "[6 4 2]" {
+		t.Errorf("Expected filtered ids by name descending [6 4 2], got %v", got)
+	}
+
+	// Find skips rows outside the filter
+	keys, _, err := rel.FindNextRow(rel.ColumnIndex["age"], 30, nil, nil, []any{int64(2)})
+	if err != nil {
+		t.Fatalf("FindNextRow failed: %v", err)
+	}
+	if keys != nil {
+		t.Errorf("Expected no match outside the filter, got %v", keys)
+	}
+	keys, below, err := rel.FindNextRow(rel.ColumnIndex["name"], "Bob", nil, nil, []any{int64(6)})
+	if err != nil {
+		t.Fatalf("FindNextRow failed: %v", err)
+	}
+	if fmt.Sprint(keys) != "[2]" || below {
+		t.Errorf("Expected wrapped match [2], got %v (below=%v)", keys, below)
+	}
+
+	// An invalid predicate keeps the current filter
+	if err := rel.SetFilter("missing_column = 1"); err == nil {
+		t.Error("Expected error for invalid filter")
+	}
+	if rel.Filter != "age = 25" {
+		t.Errorf("Expected filter to be kept, got %q", rel.Filter)
+	}
+
+	if err := rel.SetFilter(""); err != nil {
+		t.Fatalf("SetFilter failed: %v", err)
+	}
+	if got := sortedIDs(t, rel, nil, nil, false, true); len(got) != 6 {
+		t.Errorf("Expected all 6 rows after clearing the filter, got %v", got)
+	}
+}
+
+func TestQueryRows_QuickFilters(t *testing.T) {
+	db, rel := setupTestDB(t)
+	if _, err := db.Exec("INSERT INTO users (id, name, age) VALUES (7, 'Grace', NULL)"); err != nil {
+		t.Fatalf("Failed to insert row: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		filter     string
+		quick      []QuickFilter
+		sortCols   []SortColumn
+		cursor     []any
+		scrollDown bool
+		expected   []int64
+	}{
+		{"equal", "", []QuickFilter{{Column: "age", Value: int64(25)}}, nil, nil, true, []int64{2, 4, 6}},
+		{"equal after cursor", "", []QuickFilter{{Column: "age", Value: int64(25)}}, nil, []any{int64(2)}, true, []int64{4, 6}},
+		{"equal before cursor", "", []QuickFilter{{Column: "age", Value: int64(25)}}, nil, []any{int64(6)}, false, []int64{4, 2}},
+		{"exclude keeps nulls", "", []QuickFilter{{Column: "age", Value: int64(25), Exclude: true}}, nil, nil, true, []int64{1, 3, 5, 7}},
+		{"null", "", []QuickFilter{{Column: "age"}}, nil, nil, true, []int64{7}},
+		{"exclude null", "", []QuickFilter{{Column: "age", Exclude: true}}, nil, nil, true, []int64{1, 2, 3, 4, 5, 6}},
+		{"stacked on filter", "id > 2", []QuickFilter{{Column: "age", Value: int64(25), Exclude: true}, {Column: "age", Value: int64(35), Exclude: true}}, nil, nil, true, []int64{3, 7}},
+		{"sorted", "", []QuickFilter{{Column: "age", Value: int64(30)}}, []SortColumn{{Name: "name"}}, nil, true, []int64{3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel.Filter = tt.filter
+			rel.QuickFilters = tt.quick
+			got := sortedIDs(t, rel, tt.sortCols, tt.cursor, false, tt.scrollDown)
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected ids %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	rel.Filter = ""
+	rel.QuickFilters = []QuickFilter{{Column: "age", Value: int64(25)}}
+	keys, _, err := rel.FindNextRow(rel.ColumnIndex["name"], "Alice", nil, nil, []any{int64(2)})
+	if err != nil {
+		t.Fatalf("FindNextRow failed: %v", err)
+	}
+	if keys != nil {
+		t.Errorf("Expected no match outside the quick filter, got %v", keys)
+	}
+}
+
+func TestFilterPredicate_Placeholders(t *testing.T) {
+	rel := &Relation{
+		DBType: PostgreSQL,
+		Filter: "city <> 'Oslo'",
+		QuickFilters: []QuickFilter{
+			{Column: "age", Value: 25},
+			{Column: "city", Value: "Rome", Exclude: true},
+			{Column: "note"},
+		},
+		Link: []QuickFilter{{Column: "team_id", Value: 7}},
+	}
+	// One key param is already bound
+	args := &queryArgs{dbType: PostgreSQL, values: []any{1}}
+	got := rel.filterPredicate(args)
+	want := "(city <> 'Oslo') AND age = $2 AND (city <> $3 OR city IS NULL) AND note IS NULL AND team_id = $4"
+	if got != want {
+		t.Errorf("filterPredicate = %q, want %q", got, want)
+	}
+	if fmt.Sprint(args.values) != "[1 25 Rome 7]" {
+		t.Errorf("Expected bound values [1 25 Rome 7], got %v", args.values)
+	}
+}
+
+func TestQueryRows_Sorted(t *testing.T) {
+	db, rel := setupTestDB(t)
+	if _, err := db.Exec("INSERT INTO users (id, name, age) VALUES (7, 'Grace', NULL), (8, 'Heidi', NULL)"); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+	ageAsc := []SortColumn{{Name: "age", Asc: true}}
+	ageNameDesc := []SortColumn{{Name: "age", Asc: false}, {Name: "name", Asc: false}}
+
+	tests := []struct {
+		name       string
+		sortCols   []SortColumn
+		cursor     []any
+		inclusive  bool
+		scrollDown bool
+		expected   []int64
+	}{
+		{"ascending puts nulls last", ageAsc, nil, false, true, []int64{2, 4, 6, 1, 3, 5, 7, 8}},
+		{"forward from key", ageAsc, []any{int64(6)}, false, true, []int64{1, 3, 5, 7, 8}},
+		{"forward from null cursor", ageAsc, []any{nil, int64(7)}, false, true, []int64{8}},
+		{"backward from null cursor", ageAsc, []any{nil, int64(7)}, false, false, []int64{5, 3, 1, 6, 4, 2}},
+		{"descending puts nulls first", ageNameDesc, nil, false, true, []int64{8, 7, 5, 3, 1, 6, 4, 2}},
+		{"multi-column inclusive", ageNameDesc, []any{int64(3)}, true, true, []int64{3, 1, 6, 4, 2}},
+		{"multi-column backward", ageNameDesc, []any{int64(5)}, false, false, []int64{7, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortedIDs(t, rel, tt.sortCols, tt.cursor, tt.inclusive, tt.scrollDown)
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected ids %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// The viewport shows ids 1, 3 and 5 when sorted by age
+	first := []any{int64(30), int64(1)}
+	last := []any{int64(35), int64(5)}
+	for _, tt := range []struct {
+		id            int64
+		above, below bool
+	}{
+		{6, true, false},
+		{3, false, false},
+		{7, false, true},
+	} {
+		above, below, err := CompareRowPosition(db, rel, ageAsc, []any{tt.id}, first, last)
+		if err != nil {
+			t.Fatalf("CompareRowPosition failed: %v", err)
+		}
+		if above != tt.above || below != tt.below {
+			t.Errorf("Row %d: expected above=%v below=%v, got above=%v below=%v", tt.id, tt.above, tt.below, above, below)
+		}
+	}
+
+	// Nothing after Eve has age 25, so the search wraps to the nearest match above her
+	keys, foundBelow, err := rel.FindNextRow(2, int64(25), ageAsc, []any{int64(35)}, []any{int64(5)})
+	if err != nil {
+		t.Fatalf("FindNextRow failed: %v", err)
+	}
+	if foundBelow || len(keys) != 1 || keys[0] != int64(6) {
+		t.Errorf("Expected to wrap to id=6, got %v (below=%v)", keys, foundBelow)
+	}
+}
+
 func TestGetBestKey_PrimaryKey(t *testing.T) {
 	// Create temporary SQLite database
 	tmpFile, err := os.CreateTemp("", "test-*.db")
@@ -1430,6 +1627,7 @@ func TestSelectQuery_CompositeKey(t *testing.T) {
 		columns,
 		nil, // no sortCol
 		[]string{"dept_id", "order_id"}, // composite key
+		"",    // no filter
 		true,  // hasParams
 		false, // exclusive (>)
 		true)  // scrollDown
@@ -1491,6 +1689,7 @@ func TestSelectQuery_CompositeKey_Inclusive(t *testing.T) {
 		columns,
 		nil,
 		[]string{"dept_id", "order_id"},
+		"",   // no filter
 		true, // hasParams
 		true, // inclusive (>=)
 		true) // scrollDown
@@ -1539,9 +1738,8 @@ func TestFindNextRow_CompositeKey(t *testing.T) {
 	findCol := 2 // value column
 	findColVal := "two-two"
 	currentKeys := []any{int64(1), int64(2)} // Starting from (1, 2)
-	sortColVal := int64(1)                    // dept_id value of current row
 
-	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, sortColVal, currentKeys)
+	keys, foundBelow, err := rel.FindNextRow(findCol, findColVal, nil, nil, currentKeys)
 	if err != nil {
 		t.Fatalf("FindNextRow failed: %v", err)
 	}
@@ -1558,3 +1756,904 @@ func TestFindNextRow_CompositeKey(t *testing.T) {
 		t.Errorf("Expected to find (2, 2), got (%v, %v)", keys[0], keys[1])
 	}
 }
+
+func setupForeignKeyDB(t *testing.T) (*sql.DB, *Relation) {
+	tmpFile, err := os.CreateTemp("", "test-fk-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	db, err := sql.Open("sqlite3", tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE customers (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY,
+			customer_id INTEGER REFERENCES customers,
+			total INTEGER
+		);
+		CREATE TABLE notes (
+			id INTEGER PRIMARY KEY,
+			customer_id INTEGER,
+			body TEXT,
+			FOREIGN KEY (customer_id) REFERENCES customers(id)
+		);
+		INSERT INTO customers (id, name) VALUES (1, 'Alice'), (2, 'Bob');
+		INSERT INTO orders (id, customer_id, total) VALUES (1, 1, 10), (2, 1, 20), (3, 2, 30), (4, NULL, 40);
+		INSERT INTO notes (id, customer_id, body) VALUES (1, 2, 'vip');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+
+	rel, err := NewRelation(db, SQLite, "customers")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+	return db, rel
+}
+
+func TestIncomingReferences_SQLite(t *testing.T) {
+	_, rel := setupForeignKeyDB(t)
+
+	table, refs, err := rel.IncomingReferences()
+	if err != nil {
+		t.Fatalf("IncomingReferences failed: %v", err)
+	}
+	if table != "customers" {
+		t.Errorf("Expected base table customers, got %s", table)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("Expected 2 incoming references, got %d", len(refs))
+	}
+	// Tables are scanned in name order
+	if refs[0].Table != "notes" || refs[1].Table != "orders" {
+		t.Errorf("Unexpected referencing tables: %s, %s", refs[0].Table, refs[1].Table)
+	}
+	// orders.customer_id omits the referenced column, so it resolves to the primary key
+	if refs[1].Columns["customer_id"] != "id" {
+		t.Errorf("Expected customer_id -> id, got %v", refs[1].Columns)
+	}
+}
+
+func TestCountReferencingRows(t *testing.T) {
+	_, rel := setupForeignKeyDB(t)
+	_, refs, err := rel.IncomingReferences()
+	if err != nil {
+		t.Fatalf("IncomingReferences failed: %v", err)
+	}
+	orders := refs[1]
+
+	count, err := rel.CountReferencingRows(orders, map[string]any{"id": int64(1)})
+	if err != nil {
+		t.Fatalf("CountReferencingRows failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 orders for customer 1, got %d", count)
+	}
+
+	count, err = rel.CountReferencingRows(orders, map[string]any{"id": nil})
+	if err != nil {
+		t.Fatalf("CountReferencingRows failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 rows for null key, got %d", count)
+	}
+
+	filtered, err := rel.NewReferencingRelation(orders, map[string]any{"id": int64(1)})
+	if err != nil {
+		t.Fatalf("NewReferencingRelation failed: %v", err)
+	}
+	if filtered.IsCustomSQL || filtered.Name != orders.Table {
+		t.Errorf("Expected the %s table itself, got %q", orders.Table, filtered.Name)
+	}
+	rows, err := filtered.QueryRows([]string{"id"}, nil, nil, true, true)
+	if err != nil {
+		t.Fatalf("QueryRows failed: %v", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Expected order ids [1 2], got %v", ids)
+	}
+}
+
+func TestDeleteImpact(t *testing.T) {
+	db, _ := setupForeignKeyDB(t)
+	_, err := db.Exec(`
+		CREATE TABLE accounts (id INTEGER PRIMARY KEY);
+		CREATE TABLE projects (
+			id INTEGER PRIMARY KEY,
+			account_id INTEGER REFERENCES accounts(id) ON DELETE CASCADE
+		);
+		CREATE TABLE tasks (
+			id INTEGER PRIMARY KEY,
+			project_id INTEGER REFERENCES projects(id) ON DELETE CASCADE
+		);
+		CREATE TABLE comments (
+			id INTEGER PRIMARY KEY,
+			task_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE
+		);
+		CREATE TABLE invoices (
+			id INTEGER PRIMARY KEY,
+			project_id INTEGER REFERENCES projects(id) ON DELETE RESTRICT
+		);
+		CREATE TABLE members (
+			id INTEGER PRIMARY KEY,
+			account_id INTEGER REFERENCES accounts(id) ON DELETE SET NULL
+		);
+		INSERT INTO accounts (id) VALUES (1), (2);
+		INSERT INTO projects (id, account_id) VALUES (1, 1), (2, 1), (3, 2);
+		INSERT INTO tasks (id, project_id) VALUES (1, 1), (2, 1), (3, 2), (4, 3);
+		INSERT INTO comments (id, task_id) VALUES (1, 1), (2, 3), (3, 4);
+		INSERT INTO invoices (id, project_id) VALUES (1, 2);
+		INSERT INTO members (id, account_id) VALUES (1, 1), (2, 1), (3, 2);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	// The pragma is per connection
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("Failed to enable foreign keys: %v", err)
+	}
+	rel, err := NewRelation(db, SQLite, "accounts")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+
+	effects, err := rel.DeleteImpact([]any{int64(1)}, DefaultCascadeDepth)
+	if err != nil {
+		t.Fatalf("DeleteImpact failed: %v", err)
+	}
+	got := map[string]DeleteEffect{}
+	for _, eff := range effects {
+		got[eff.Table] = eff
+	}
+	want := map[string]DeleteEffect{
+		"members":  {Table: "members", Action: "SET NULL", Count: 2, Depth: 1},
+		"projects": {Table: "projects", Action: "CASCADE", Count: 2, Depth: 1},
+		"tasks":    {Table: "tasks", Action: "CASCADE", Count: 3, Depth: 2},
+		"invoices": {Table: "invoices", Action: "RESTRICT", Count: 1, Depth: 2},
+		"comments": {Table: "comments", Action: "CASCADE", Count: 2, Depth: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d effects, got %+v", len(want), effects)
+	}
+	for table, w := range want {
+		if got[table] != w {
+			t.Errorf("Effect on %s = %+v, want %+v", table, got[table], w)
+		}
+	}
+	if !got["invoices"].Blocks() || got["tasks"].Blocks() {
+		t.Errorf("Expected only RESTRICT to block the delete")
+	}
+
+	// A shallower limit stops at tasks and notes that comments are still affected
+	effects, err = rel.DeleteImpact([]any{int64(1)}, 2)
+	if err != nil {
+		t.Fatalf("DeleteImpact failed: %v", err)
+	}
+	for _, eff := range effects {
+		if eff.Table == "comments" {
+			t.Errorf("Expected comments to be beyond the depth limit")
+		}
+		if eff.Table == "tasks" && !eff.More {
+			t.Errorf("Expected tasks to report further cascades")
+		}
+	}
+
+	// Tasks without comments have nothing further to cascade to
+	if _, err := db.Exec(`INSERT INTO accounts (id) VALUES (3);
+		INSERT INTO projects (id, account_id) VALUES (4, 3);
+		INSERT INTO tasks (id, project_id) VALUES (5, 4);`); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+	effects, err = rel.DeleteImpact([]any{int64(3)}, 2)
+	if err != nil {
+		t.Fatalf("DeleteImpact failed: %v", err)
+	}
+	for _, eff := range effects {
+		if eff.Table == "tasks" && eff.More {
+			t.Errorf("Expected tasks without comments to report no further cascades")
+		}
+	}
+
+	// The existing foreign keys without an action default to NO ACTION, which doesn't
+	// block while SQLite's foreign_keys is off
+	_, customers := setupForeignKeyDB(t)
+	effects, err = customers.DeleteImpact([]any{int64(2), "Bob"}, DefaultCascadeDepth)
+	if err != nil {
+		t.Fatalf("DeleteImpact failed: %v", err)
+	}
+	if len(effects) != 2 || effects[0].Action != "NO ACTION" || !effects[0].Unenforced || effects[0].Blocks() {
+		t.Errorf("Expected unenforced NO ACTION references, got %+v", effects)
+	}
+
+	// Cascades aren't followed when they won't run
+	if _, err := db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatalf("Failed to disable foreign keys: %v", err)
+	}
+	effects, err = rel.DeleteImpact([]any{int64(1)}, DefaultCascadeDepth)
+	if err != nil {
+		t.Fatalf("DeleteImpact failed: %v", err)
+	}
+	if len(effects) != 2 {
+		t.Fatalf("Expected only projects and members, got %+v", effects)
+	}
+	for _, eff := range effects {
+		if !eff.Unenforced || eff.Depth != 1 {
+			t.Errorf("Expected an unenforced direct reference, got %+v", eff)
+		}
+	}
+}
+
+func TestUpdateDBBytes(t *testing.T) {
+	db, rel := setupTestDB(t)
+	if _, err := db.Exec("ALTER TABLE users ADD COLUMN avatar BLOB"); err != nil {
+		t.Fatalf("Failed to add column: %v", err)
+	}
+	rel, err := NewRelation(db, SQLite, "users")
+	if err != nil {
+		t.Fatalf("Failed to reload relation: %v", err)
+	}
+
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	records := [][]any{{int64(1), "Alice", int64(30), nil}}
+	updated, err := rel.UpdateDBBytes(records, 0, "avatar", data)
+	if err != nil {
+		t.Fatalf("UpdateDBBytes failed: %v", err)
+	}
+	got, ok := updated[rel.ColumnIndex["avatar"]].([]byte)
+	if !ok || string(got) != string(data) {
+		t.Errorf("Expected returned avatar %v, got %v", data, updated[rel.ColumnIndex["avatar"]])
+	}
+
+	var stored []byte
+	if err := db.QueryRow("SELECT avatar FROM users WHERE id = 1").Scan(&stored); err != nil {
+		t.Fatalf("Failed to read back blob: %v", err)
+	}
+	if string(stored) != string(data) {
+		t.Errorf("Expected stored blob %v, got %v", data, stored)
+	}
+}
+
+func setupDefaultsDB(t *testing.T) (*sql.DB, *Relation) {
+	tmpFile, err := os.CreateTemp("", "test-defaults-*.db")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tmpFile.Close()
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+
+	db, err := sql.Open("sqlite3", tmpFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE items (
+			id INTEGER PRIMARY KEY,
+			name TEXT DEFAULT 'unnamed',
+			note TEXT,
+			qty INTEGER NOT NULL DEFAULT 1,
+			total INTEGER GENERATED ALWAYS AS (qty * 10) VIRTUAL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	rel, err := NewRelation(db, SQLite, "items")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+	return db, rel
+}
+
+func TestLoadColumns_DefaultsSQLite(t *testing.T) {
+	_, rel := setupDefaultsDB(t)
+
+	if len(rel.Columns) != 5 {
+		t.Fatalf("Expected 5 columns including generated, got %d", len(rel.Columns))
+	}
+	col := func(name string) Column {
+		return rel.Columns[rel.ColumnIndex[name]]
+	}
+	if !col("id").AutoIncrement {
+		t.Errorf("Expected INTEGER PRIMARY KEY to be auto-increment")
+	}
+	if !col("name").HasDefault || col("name").Default != "'unnamed'" {
+		t.Errorf("Expected name default 'unnamed', got %q (has=%v)", col("name").Default, col("name").HasDefault)
+	}
+	if col("note").HasDefault {
+		t.Errorf("Expected note to have no default")
+	}
+	if !col("total").Generated {
+		t.Errorf("Expected total to be generated")
+	}
+	if rel.IsColumnEditable(rel.ColumnIndex["total"]) {
+		t.Errorf("Expected generated column to be read-only")
+	}
+	if !rel.IsColumnEditable(rel.ColumnIndex["qty"]) {
+		t.Errorf("Expected qty to be editable")
+	}
+}
+
+func TestInsertDBRecord_DefaultNullEmpty(t *testing.T) {
+	db, rel := setupDefaultsDB(t)
+
+	// Unset name/qty use defaults, note is an empty string, generated total is ignored
+	row := []any{EmptyCellValue, EmptyCellValue, "", EmptyCellValue, "99"}
+	inserted, err := rel.InsertDBRecord(row)
+	if err != nil {
+		t.Fatalf("InsertDBRecord failed: %v", err)
+	}
+	if got := inserted[rel.ColumnIndex["total"]]; got != int64(10) {
+		t.Errorf("Expected generated total 10, got %v", got)
+	}
+
+	var name string
+	var note sql.NullString
+	if err := db.QueryRow("SELECT name, note FROM items WHERE id = ?", inserted[0]).Scan(&name, &note); err != nil {
+		t.Fatalf("Failed to read back row: %v", err)
+	}
+	if name != "unnamed" {
+		t.Errorf("Expected default name, got %q", name)
+	}
+	if !note.Valid || note.String != "" {
+		t.Errorf("Expected empty string note, got %v", note)
+	}
+
+	// nil is an explicit NULL and overrides the default
+	row = []any{EmptyCellValue, nil, NullGlyph, "3", EmptyCellValue}
+	inserted, err = rel.InsertDBRecord(row)
+	if err != nil {
+		t.Fatalf("InsertDBRecord failed: %v", err)
+	}
+	if inserted[rel.ColumnIndex["name"]] != nil || inserted[rel.ColumnIndex["note"]] != nil {
+		t.Errorf("Expected NULL name and note, got %v", inserted)
+	}
+
+	preview := rel.BuildInsertPreview(row, []DisplayColumn{{Name: "id"}, {Name: "name"}, {Name: "note"}, {Name: "qty"}, {Name: "total"}})
+	if !strings.Contains(preview, "(name, note, qty) VALUES (NULL, NULL, 3)") {
+		t.Errorf("Unexpected insert preview: %s", preview)
+	}
+}
+
+func TestFormatLiteral_EmptyNumeric(t *testing.T) {
+	rel := &Relation{DBType: SQLite}
+	for _, typ := range []string{"INTEGER", "REAL", "DOUBLE PRECISION", "FLOAT", "NUMERIC", "DECIMAL(10,2)"} {
+		if got := rel.formatLiteral("", typ); got != "''" {
+			t.Errorf("Expected an empty string quoted in a %s column, got %s", typ, got)
+		}
+		if got := rel.formatLiteral("3", typ); got != "3" {
+			t.Errorf("Expected 3 bare in a %s column, got %s", typ, got)
+		}
+	}
+}
+
+func TestInsertDBRecord_View(t *testing.T) {
+	db, rel := setupTestDBWithView(t)
+
+	row := []any{EmptyCellValue, "Gizmo", "3.5", "Toys"}
+	inserted, err := rel.InsertDBRecord(row)
+	if err != nil {
+		t.Fatalf("InsertDBRecord through view failed: %v", err)
+	}
+	if inserted[rel.ColumnIndex["id"]] != int64(4) || inserted[rel.ColumnIndex["name"]] != "Gizmo" {
+		t.Errorf("Unexpected inserted row: %v", inserted)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE name = 'Gizmo'").Scan(&count); err != nil {
+		t.Fatalf("Failed to query base table: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row in base table, got %d", count)
+	}
+}
+
+func TestDeleteDBRecord_View(t *testing.T) {
+	db, rel := setupTestDBWithView(t)
+
+	records := [][]any{{int64(2), "Gadget", 25.50, "Electronics"}}
+	if preview := rel.BuildDeletePreview(records, 0); preview != "DELETE FROM products WHERE id = 2" {
+		t.Errorf("Unexpected delete preview: %s", preview)
+	}
+	if err := rel.DeleteDBRecord(records, 0); err != nil {
+		t.Fatalf("DeleteDBRecord through view failed: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = 2").Scan(&count); err != nil {
+		t.Fatalf("Failed to query base table: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected row to be deleted from base table, found %d", count)
+	}
+}
+
+func TestWritableBaseTable(t *testing.T) {
+	db, rel := setupForeignKeyDB(t)
+
+	if table, err := rel.WritableBaseTable(); err != nil || table != "customers" {
+		t.Errorf("Expected customers for a table, got %q (%v)", table, err)
+	}
+
+	single, err := NewRelationFromSQL(db, SQLite, "SELECT id, total FROM orders WHERE total > 10")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+	if table, err := single.WritableBaseTable(); err != nil || table != "orders" {
+		t.Errorf("Expected orders for a single-table query, got %q (%v)", table, err)
+	}
+	// The base table is loaded once and kept
+	base := single.base
+	if base == nil {
+		t.Fatalf("Expected the base relation kept")
+	}
+	if _, err := single.WritableBaseTable(); err != nil || single.base != base {
+		t.Errorf("Expected the kept base relation reused, got %v", err)
+	}
+
+	joined, err := NewRelationFromSQL(db, SQLite, "SELECT o.id, c.id AS cid, c.name FROM orders o JOIN customers c ON c.id = o.customer_id")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+	if _, err := joined.WritableBaseTable(); err == nil || !strings.Contains(err.Error(), "customers, orders") {
+		t.Errorf("Expected multi-table error, got %v", err)
+	}
+
+	noKey, err := NewRelationFromSQL(db, SQLite, "SELECT total FROM orders")
+	if err != nil {
+		t.Fatalf("Failed to create relation: %v", err)
+	}
+	if _, err := noKey.WritableBaseTable(); err == nil || !strings.Contains(err.Error(), "key of orders") {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+}
+
+func TestInlineParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		dbType    DatabaseType
+		statement string
+		params    []any
+		want      string
+	}{
+		{"question marks", SQLite, "UPDATE t SET a = ? WHERE id = ?", []any{"it's", int64(3)}, "UPDATE t SET a = 'it''s' WHERE id = 3"},
+		{"positional", PostgreSQL, "DELETE FROM t WHERE a = $1 AND b = $2", []any{nil, true}, "DELETE FROM t WHERE a = NULL AND b = TRUE"},
+		{"quoted placeholder", SQLite, `INSERT INTO "a?" (x) VALUES (?)`, []any{1.5}, `INSERT INTO "a?" (x) VALUES (1.5)`},
+		{"binary", MySQL, "UPDATE t SET b = ?", []any{[]byte{0xff, 0x00}}, "UPDATE t SET b = X'ff00'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InlineParams(tt.dbType, tt.statement, tt.params); got != tt.want {
+				t.Errorf("InlineParams() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteObserver(t *testing.T) {
+	_, rel := setupTestDB(t)
+
+	var events []WriteEvent
+	rel.OnWrite = func(ev WriteEvent) { events = append(events, ev) }
+
+	records := [][]any{{int64(1), "Alice", int64(30)}}
+	if _, err := rel.UpdateDBValue(records, 0, "name", "Alicia"); err != nil {
+		t.Fatalf("UpdateDBValue failed: %v", err)
+	}
+	if err := rel.DeleteDBRecord(records, 0); err != nil {
+		t.Fatalf("DeleteDBRecord failed: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 write events, got %d", len(events))
+	}
+	if events[0].Op != "UPDATE" || events[0].Before["name"] != "Alice" || events[0].After["name"] != "Alicia" {
+		t.Errorf("Unexpected update event: %+v", events[0])
+	}
+	if events[1].Op != "DELETE" || events[1].Table != "users" || events[1].After != nil {
+		t.Errorf("Unexpected delete event: %+v", events[1])
+	}
+
+	// Writes through a result name both images by the base table's columns
+	db, view := setupTestDBWithView(t)
+	renamed, err := NewRelationFromSQL(db, SQLite, "SELECT id, name AS title FROM products")
+	if err != nil {
+		t.Fatalf("NewRelationFromSQL failed: %v", err)
+	}
+	events = nil
+	renamed.OnWrite = func(ev WriteEvent) { events = append(events, ev) }
+	if _, err := renamed.UpdateDBValue([][]any{{int64(1), "Widget"}}, 0, "title", "Gizmo"); err != nil {
+		t.Fatalf("UpdateDBValue failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Before["name"] != "Widget" || events[0].After["name"] != "Gizmo" || events[0].Before["title"] != nil {
+		t.Errorf("Expected name before and after, got %+v", events)
+	}
+	events = nil
+	if err := renamed.DeleteDBRecord([][]any{{int64(1), "Gizmo"}}, 0); err != nil {
+		t.Fatalf("DeleteDBRecord failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Table != "products" || events[0].Before["name"] != "Gizmo" || events[0].Before["title"] != nil {
+		t.Errorf("Expected the deleted product's name, got %+v", events)
+	}
+	if image := baseRowImage(renamed.Columns, "products", []any{int64(1), "Gizmo"}); image["name"] != "Gizmo" || image["title"] != nil {
+		t.Errorf("Expected the loaded row named by base columns, got %+v", image)
+	}
+
+	// Inserts through a view or custom SQL are reported once, as inserts into the base table
+	for _, through := range []*Relation{view, renamed} {
+		events = nil
+		through.OnWrite = func(ev WriteEvent) { events = append(events, ev) }
+		row := make([]any, len(through.Columns))
+		for i := range row {
+			row[i] = EmptyCellValue
+		}
+		row[through.ColumnIndex["id"]] = int64(10 + len(through.Columns))
+		if i, ok := through.ColumnIndex["name"]; ok {
+			row[i] = "Sprocket"
+		} else {
+			row[through.ColumnIndex["title"]] = "Sprocket"
+		}
+		if _, err := through.InsertDBRecord(row); err != nil {
+			t.Fatalf("InsertDBRecord through %s failed: %v", through.Name, err)
+		}
+		if len(events) != 1 || events[0].Op != "INSERT" || events[0].Table != "products" || events[0].After["name"] != "Sprocket" {
+			t.Errorf("Expected one insert into products through %s, got %+v", through.Name, events)
+		}
+	}
+}
+
+func TestFindRow_Modes(t *testing.T) {
+	db, err := sql.Open(SQLiteDriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE people (id INTEGER PRIMARY KEY, name TEXT, city TEXT, age INTEGER);
+		INSERT INTO people VALUES (1, 'Alice', 'Oslo', 30), (2, 'bob', 'Rome', 25), (3, 'Bobby', 'Oslo', 40),
+			(4, 'Carol', 'Bo_ston', 35), (5, 'Dave', NULL, 20)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	rel, err := NewRelation(db, SQLite, "people")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	name, city := rel.ColumnIndex["name"], rel.ColumnIndex["city"]
+
+	tests := []struct {
+		name      string
+		col       int
+		value     string
+		opts      FindOptions
+		current   int64
+		wantKey   any
+		wantBelow bool
+	}{
+		{"exact", name, "bob", FindOptions{}, 1, int64(2), true},
+		{"exact ignoring case", name, "BOB", FindOptions{IgnoreCase: true}, 1, int64(2), true},
+		{"prefix matches case", name, "Bo", FindOptions{Mode: FindPrefix}, 1, int64(3), true},
+		{"prefix ignoring case", name, "bo", FindOptions{Mode: FindPrefix, IgnoreCase: true}, 1, int64(2), true},
+		{"substring", name, "ob", FindOptions{Mode: FindSubstring}, 2, int64(3), true},
+		{"substring escapes wildcards", city, "_", FindOptions{Mode: FindSubstring, IgnoreCase: true}, 1, int64(4), true},
+		{"regex", name, "^B.*y$", FindOptions{Mode: FindRegex}, 1, int64(3), true},
+		{"regex ignoring case", name, "^b", FindOptions{Mode: FindRegex, IgnoreCase: true}, 3, int64(2), false},
+		{"all columns", -1, "Oslo", FindOptions{Mode: FindExact, AllColumns: true}, 1, int64(3), true},
+		{"backward", name, "bo", FindOptions{Mode: FindPrefix, IgnoreCase: true, Backward: true}, 3, int64(2), false},
+		{"backward wraps", name, "Dave", FindOptions{Backward: true}, 1, int64(5), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, below, err := rel.FindRow(tt.col, tt.value, tt.opts, nil, nil, []any{tt.current})
+			if err != nil {
+				t.Fatalf("FindRow failed: %v", err)
+			}
+			if len(keys) != 1 || keys[0] != tt.wantKey || below != tt.wantBelow {
+				t.Errorf("Expected key %v (below=%v), got %v (below=%v)", tt.wantKey, tt.wantBelow, keys, below)
+			}
+		})
+	}
+
+	opts := FindOptions{Mode: FindSubstring, IgnoreCase: true}
+	position, total, err := rel.CountMatches(name, "o", opts, nil, []any{int64(3)})
+	if err != nil {
+		t.Fatalf("CountMatches failed: %v", err)
+	}
+	if position != 2 || total != 3 {
+		t.Errorf("Expected match 2 of 3, got %d of %d", position, total)
+	}
+	position, total, err = rel.CountMatches(name, "o", opts, []SortColumn{{Name: "name"}}, []any{int64(3)})
+	if err != nil {
+		t.Fatalf("CountMatches failed: %v", err)
+	}
+	if position != 3 || total != 3 {
+		t.Errorf("Expected match 3 of 3 by name descending, got %d of %d", position, total)
+	}
+
+	if _, _, err := rel.FindRow(name, "(", FindOptions{Mode: FindRegex}, nil, nil, []any{int64(1)}); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	db, err := sql.Open(SQLiteDriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE people (id INTEGER PRIMARY KEY, name TEXT, city TEXT, score REAL);
+		INSERT INTO people VALUES (1, 'Smith, Alice', 'Olso', 1.5), (2, 'Jones, Bob', 'olso', 1.5), (3, 'Carol', 'Rome', 2), (4, 'Dave', NULL, NULL);
+		CREATE VIEW people_view AS SELECT id, name, upper(city) AS shout FROM people`)
+	if err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	rel, err := NewRelation(db, SQLite, "people")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	name, city, score := rel.ColumnIndex["name"], rel.ColumnIndex["city"], rel.ColumnIndex["score"]
+
+	values := func(col string) []any {
+		t.Helper()
+		rows, err := db.Query("SELECT " + col + " FROM people ORDER BY id")
+		if err != nil {
+			t.Fatalf("select failed: %v", err)
+		}
+		defer rows.Close()
+		var result []any
+		for rows.Next() {
+			var v any
+			if err := rows.Scan(&v); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			result = append(result, v)
+		}
+		return result
+	}
+
+	// Regex replacements expand capture groups
+	plan, err := rel.PlanReplace(name, `^(\w+), (\w+)$`, "$2 $1", FindOptions{Mode: FindRegex})
+	if err != nil {
+		t.Fatalf("PlanReplace failed: %v", err)
+	}
+	if plan.Matched != 2 || len(plan.Changes) != 2 || plan.Changes[0].After != "Alice Smith" {
+		t.Fatalf("Unexpected plan: %+v", plan)
+	}
+	if got := plan.Statements(); len(got) != 2 || got[1] != `UPDATE people SET name = 'Bob Jones' WHERE id = 2 AND name = 'Jones, Bob'` {
+		t.Errorf("Unexpected statements: %v", got)
+	}
+	if err := rel.ExecuteReplace(plan); err != nil {
+		t.Fatalf("ExecuteReplace failed: %v", err)
+	}
+	if got := values("name"); got[0] != "Alice Smith" || got[1] != "Bob Jones" || got[2] != "Carol" {
+		t.Errorf("Unexpected names after replace: %v", got)
+	}
+
+	// Regex matches respect the filter, and floats aren't checked by value
+	rel.QuickFilters = []QuickFilter{{Column: "id", Value: int64(2), Exclude: true}}
+	plan, err = rel.PlanReplace(score, `^1\.5$`, "2.5", FindOptions{Mode: FindRegex})
+	rel.QuickFilters = nil
+	if err != nil {
+		t.Fatalf("PlanReplace failed: %v", err)
+	}
+	if got := plan.Statements(); plan.Matched != 1 || len(got) != 1 || got[0] != `UPDATE people SET score = '2.5' WHERE id = 1` {
+		t.Fatalf("Unexpected plan: %+v, statements %v", plan, got)
+	}
+	if err := rel.ExecuteReplace(plan); err != nil {
+		t.Fatalf("ExecuteReplace failed: %v", err)
+	}
+	if got := values("score"); got[0] != 2.5 || got[1] != 1.5 {
+		t.Errorf("Unexpected scores after replace: %v", got)
+	}
+
+	// Literal replacements ignoring case skip NULLs and respect the filter
+	rel.QuickFilters = []QuickFilter{{Column: "id", Value: int64(1), Exclude: true}}
+	plan, err = rel.PlanReplace(city, "OLSO", "Oslo", FindOptions{Mode: FindSubstring, IgnoreCase: true})
+	rel.QuickFilters = nil
+	if err != nil {
+		t.Fatalf("PlanReplace failed: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Keys[0] != int64(2) {
+		t.Fatalf("Expected only row 2 to change, got %+v", plan.Changes)
+	}
+
+	// A row changed after planning aborts the whole replace
+	if _, err := db.Exec("UPDATE people SET city = 'Bergen' WHERE id = 2"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := rel.ExecuteReplace(plan); err == nil {
+		t.Error("Expected a stale plan to fail")
+	}
+	if got := values("city"); got[1] != "Bergen" {
+		t.Errorf("Expected stale row to be left alone, got %v", got)
+	}
+
+	// Views write through to the base table, but derived columns can't be replaced
+	view, err := NewRelation(db, SQLite, "people_view")
+	if err != nil {
+		t.Fatalf("NewRelation failed for view: %v", err)
+	}
+	if _, err := view.PlanReplace(view.ColumnIndex["shout"], "ROME", "PARIS", FindOptions{}); err == nil {
+		t.Error("Expected derived view column to be rejected")
+	}
+	plan, err = view.PlanReplace(view.ColumnIndex["name"], "Carol", "Caroline", FindOptions{})
+	if err != nil {
+		t.Fatalf("PlanReplace on view failed: %v", err)
+	}
+	if err := view.ExecuteReplace(plan); err != nil {
+		t.Fatalf("ExecuteReplace on view failed: %v", err)
+	}
+	if got := values("name"); got[2] != "Caroline" {
+		t.Errorf("Expected view replace to update base table, got %v", got)
+	}
+}
+
+func TestGotoKey(t *testing.T) {
+	db, err := sql.Open(SQLiteDriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO items VALUES (10, 'a'), (20, 'b'), (30, 'c');
+		CREATE TABLE grid (x TEXT, y INTEGER, PRIMARY KEY (x, y));
+		INSERT INTO grid VALUES ('a, b', 1), ('a, b', 5), ('c', 2)`)
+	if err != nil {
+		t.Fatalf("Failed to create tables: %v", err)
+	}
+	items, err := NewRelation(db, SQLite, "items")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+
+	if _, err := items.ParseKey("abc"); err == nil {
+		t.Error("Expected a non-numeric id to be rejected")
+	}
+	if _, err := items.ParseKey("1, 2"); err == nil {
+		t.Error("Expected too many values to be rejected")
+	}
+
+	tests := []struct {
+		key       string
+		wantKey   []any
+		wantExact bool
+	}{
+		{"20", []any{int64(20)}, true},
+		{" 11", []any{int64(20)}, false},
+		{"31", nil, false},
+	}
+	for _, tt := range tests {
+		keys, err := items.ParseKey(tt.key)
+		if err != nil {
+			t.Fatalf("ParseKey(%q) failed: %v", tt.key, err)
+		}
+		found, exact, err := items.NearestKey(keys)
+		if err != nil {
+			t.Fatalf("NearestKey(%v) failed: %v", keys, err)
+		}
+		if fmt.Sprint(found) != fmt.Sprint(tt.wantKey) || exact != tt.wantExact {
+			t.Errorf("NearestKey(%q) = %v, %v; want %v, %v", tt.key, found, exact, tt.wantKey, tt.wantExact)
+		}
+	}
+
+	// Filters hide rows from goto
+	items.QuickFilters = []QuickFilter{{Column: "name", Value: "b", Exclude: true}}
+	if found, exact, _ := items.NearestKey([]any{int64(20)}); exact || fmt.Sprint(found) != "[30]" {
+		t.Errorf("Expected filtered row to be skipped, got %v, %v", found, exact)
+	}
+
+	grid, err := NewRelation(db, SQLite, "grid")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	keys, err := grid.ParseKey(`"a, b", 3`)
+	if err != nil {
+		t.Fatalf("ParseKey failed for composite key: %v", err)
+	}
+	if found, exact, err := grid.NearestKey(keys); err != nil || exact || fmt.Sprint(found) != "[a, b 5]" {
+		t.Errorf("NearestKey for composite key = %v, %v, %v", found, exact, err)
+	}
+}
+
+func TestCountRowsAndSampleKeys(t *testing.T) {
+	db, err := sql.Open(SQLiteDriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`CREATE TABLE nums (id INTEGER PRIMARY KEY, grp TEXT);
+		WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 1000)
+		INSERT INTO nums SELECT n, CASE WHEN n % 2 = 0 THEN 'even' ELSE 'odd' END FROM seq`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	rel, err := NewRelation(db, SQLite, "nums")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+
+	if count, exact, err := rel.CountRows(); err != nil || count != 1000 || !exact {
+		t.Errorf("CountRows = %d, %v, %v; want 1000 exact", count, exact, err)
+	}
+	rel.QuickFilters = []QuickFilter{{Column: "grp", Value: "even"}}
+	if count, _, err := rel.CountRows(); err != nil || count != 500 {
+		t.Errorf("CountRows with filter = %d, %v; want 500", count, err)
+	}
+	if position, err := rel.RowPosition(nil, []any{int64(101)}); err != nil || position != 50 {
+		t.Errorf("RowPosition with filter = %d, %v; want 50", position, err)
+	}
+	// Filtered large tables scale the estimate by a sample, here of about half the rows
+	if count, exact, err := rel.estimateFilteredRows(20000); err != nil || exact || count < 7000 || count > 13000 {
+		t.Errorf("estimateFilteredRows = %d, %v, %v; want about 10000 estimated", count, exact, err)
+	}
+	rel.QuickFilters = nil
+
+	sortCols := []SortColumn{{Name: "id"}}
+	if position, err := rel.RowPosition(sortCols, []any{int64(990), int64(990)}); err != nil || position != 10 {
+		t.Errorf("RowPosition descending = %d, %v; want 10", position, err)
+	}
+
+	sample, err := rel.SampleKeys(nil, 1000)
+	if err != nil {
+		t.Fatalf("SampleKeys failed: %v", err)
+	}
+	if sample.Len() < 100 || sample.Len() > 500 {
+		t.Fatalf("Expected about %d sampled rows, got %d", keySampleSize, sample.Len())
+	}
+	middle := sample.At(0.5)[0].(int64)
+	if middle < 300 || middle > 700 {
+		t.Errorf("Expected the middle sample near 500, got %d", middle)
+	}
+	if f := sample.Fraction([]any{int64(250)}); f < 0.15 || f > 0.35 {
+		t.Errorf("Expected row 250 about a quarter of the way, got %.2f", f)
+	}
+
+	// Small relations sample every row, in sort order
+	rel.QuickFilters = []QuickFilter{{Column: "id", Value: int64(7)}}
+	sample, err = rel.SampleKeys(sortCols, 1)
+	if err != nil || sample.Len() != 1 || sample.At(1)[1] != int64(7) {
+		t.Errorf("Expected the one filtered row, got %v, %v", sample, err)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b any
+		want int
+	}{
+		{int64(2), int64(10), -1},
+		{int64(2), 1.5, 1},
+		{"b", []byte("a"), 1},
+		{nil, int64(1), 1},
+		{"x", nil, -1},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareValues(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -77,6 +77,13 @@ type Reference struct {
 	Columns map[string]string // local column name -> foreign column name
 }
 
+// IncomingReference represents a foreign key in another table that points at this table
+type IncomingReference struct {
+	Table    string            // referencing table name
+	Columns  map[string]string // referencing column name -> referenced column name in this table
+	OnDelete string            // CASCADE, SET NULL, SET DEFAULT, RESTRICT or NO ACTION
+}
+
 // database: table, attribute, record
 // sheet: sheet, column, row
 // row id should be file line number, different than lookup key
@@ -95,6 +102,16 @@ type Relation struct {
 	ColumnIndex  map[string]int    // column name -> column index
 	Key          []int             // index into Columns for key columns
 	References   []Reference       // references
+	Filter       string            // SQL predicate restricting the rows queried, empty for all rows
+	QuickFilters []QuickFilter     // column = value conditions applied on top of Filter
+	Link         []QuickFilter     // conditions tying the rows to another relation's row, applied like QuickFilters
+	OnWrite      func(WriteEvent)  // called after every successful write through the relation, if set
+
+	// base table of a view or custom SQL and why rows can't be written through it, worked
+	// out by writableBase on first use
+	base      *Relation
+	baseTable *Table
+	baseErr   error
 }
 
 // Column represents a column in a relation (renamed from Attribute)
@@ -106,6 +123,9 @@ type Column struct {
 	BaseColumn     string   // original column name in base table (if passthrough)
 	Reference      int      // index into Table.References, -1 if not a foreign key column
 	Generated      bool     // if computed column, read-only
+	Default        string   // column default expression as reported by the catalog
+	HasDefault     bool     // true if the column has a default expression
+	AutoIncrement  bool     // identity/serial/rowid alias, value assigned by the database
 	EnumValues     []string // for ENUM types, stores allowed values
 	CustomTypeName string   // for custom types (PostgreSQL), stores the type name
 }
@@ -141,4 +161,7 @@ type DisplayColumn struct {
 	Width    int
 	IsKey    bool
 	Editable bool
+	Default  string // placeholder shown in the insert row while the value is unset
+	Index    int    // position of the column's value in each row, its index in Relation.Columns
+	Wrap     bool   // wrap text over several lines per row instead of cutting it at the width
 }
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"ted/internal/dblib"
+)
+
+// JournalEntry is one write recorded in the audit journal
+type JournalEntry struct {
+	Time       time.Time      `json:"time"`
+	User       string         `json:"user"`
+	Connection string         `json:"connection"`
+	Relation   string         `json:"relation,omitempty"` // relation shown in the editor
+	Table      string         `json:"table,omitempty"`    // table the statement wrote to
+	Op         string         `json:"op"`                 // UPDATE, INSERT, DELETE or SQL
+	Statement  string         `json:"statement"`
+	Params     []any          `json:"params,omitempty"`
+	SQL        string         `json:"sql"` // statement with params inlined, for replay
+	Before     map[string]any `json:"before,omitempty"`
+	After      map[string]any `json:"after,omitempty"`
+}
+
+// getStateDir returns the state directory following XDG Base Directory spec
+func getStateDir() (string, error) {
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "ted"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "state", "ted"), nil
+}
+
+// getJournalPath returns the full path to the journal file
+func getJournalPath() (string, error) {
+	stateDir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, "journal.jsonl"), nil
+}
+
+// appendJournal appends entry as a single JSON line, creating the journal if needed
+func appendJournal(entry JournalEntry) error {
+	path, err := getJournalPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("could not create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not marshal journal entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	// Row images may hold sensitive data, so keep the journal private
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not open journal: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write journal: %w", err)
+	}
+	return f.Close()
+}
+
+// readJournal parses every entry in the journal at r
+func readJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// journalValue converts a database value into something readable in JSON.
+// Text stored as bytes becomes a string and binary data becomes \x-prefixed hex.
+func journalValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		if utf8.Valid(b) {
+			return string(b)
+		}
+		return `\x` + hex.EncodeToString(b)
+	}
+	return v
+}
+
+// journalImage converts every value of a row image with journalValue
+func journalImage(image map[string]any) map[string]any {
+	if image == nil {
+		return nil
+	}
+	out := make(map[string]any, len(image))
+	for k, v := range image {
+		out[k] = journalValue(v)
+	}
+	return out
+}
+
+// currentUsername returns the OS user running ted
+func currentUsername() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// newJournalEntry fills in the fields shared by every entry recorded from the editor
+func (e *Editor) newJournalEntry(op, statement string) JournalEntry {
+	entry := JournalEntry{
+		Time:       time.Now().UTC(),
+		User:       currentUsername(),
+		Connection: e.config.connectionLabel(),
+		Op:         op,
+		Statement:  statement,
+		SQL:        statement,
+	}
+	if e.relation != nil {
+		entry.Relation = e.table.tableName
+	}
+	return entry
+}
+
+// journalWrite records a write reported by dblib
+func (e *Editor) journalWrite(ev dblib.WriteEvent) {
+	entry := e.newJournalEntry(ev.Op, ev.Statement)
+	entry.Table = ev.Table
+	entry.SQL = dblib.InlineParams(ev.DBType, ev.Statement, ev.Params)
+	for _, p := range ev.Params {
+		entry.Params = append(entry.Params, journalValue(p))
+	}
+	entry.Before = journalImage(ev.Before)
+	entry.After = journalImage(ev.After)
+	if err := appendJournal(entry); err != nil {
+		e.SetStatusError("Journal: " + err.Error())
+	}
+}
+
+// journalSQL records a statement executed in SQL mode
+func (e *Editor) journalSQL(statement string) {
+	if err := appendJournal(e.newJournalEntry("SQL", statement)); err != nil {
+		e.SetStatusError("Journal: " + err.Error())
+	}
+}
+
+var (
+	journalReplay bool
+	journalTable  string
+	journalSince  time.Duration
+)
+
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "List writes recorded in the audit journal",
+	Long: `Every UPDATE, INSERT, DELETE and SQL-mode statement run by ted is appended to
+a JSONL journal in the XDG state directory ($XDG_STATE_HOME/ted/journal.jsonl,
+by default ~/.local/state/ted/journal.jsonl).
+
+Examples:
+  ted journal
+  ted journal --table users --since 24h
+  ted journal --sql > replay.sql`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := getJournalPath()
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "No journal at %s\n", path)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		entries, err := readJournal(f)
+		if err != nil {
+			return err
+		}
+		entries = filterJournal(entries, journalTable, journalSince, time.Now())
+		if journalReplay {
+			writeJournalSQL(os.Stdout, entries)
+		} else {
+			writeJournalList(os.Stdout, entries)
+		}
+		return nil
+	},
+}
+
+func init() {
+	journalCmd.Flags().BoolVar(&journalReplay, "sql", false, "Print entries as a SQL script for replay")
+	journalCmd.Flags().StringVar(&journalTable, "table", "", "Only show writes to this table or relation")
+	journalCmd.Flags().DurationVar(&journalSince, "since", 0, "Only show entries newer than this duration (e.g. 24h)")
+	rootCmd.AddCommand(journalCmd)
+}
+
+// filterJournal keeps entries matching table (if set) and newer than since (if set)
+func filterJournal(entries []JournalEntry, table string, since time.Duration, now time.Time) []JournalEntry {
+	var out []JournalEntry
+	for _, entry := range entries {
+		if table != "" && entry.Table != table && entry.Relation != table {
+			continue
+		}
+		if since > 0 && entry.Time.Before(now.Add(-since)) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// writeJournalList prints one summary line per entry followed by its SQL
+func writeJournalList(w io.Writer, entries []JournalEntry) {
+	for _, entry := range entries {
+		target := entry.Table
+		if target == "" {
+			target = entry.Relation
+		}
+		fmt.Fprintf(w, "%s  %-8s %-6s %-20s %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.User, entry.Op, target, entry.Connection)
+		fmt.Fprintf(w, "    %s\n", entry.SQL)
+	}
+}
+
+// writeJournalSQL prints entries as a replayable script in journal order
+func writeJournalSQL(w io.Writer, entries []JournalEntry) {
+	for _, entry := range entries {
+		fmt.Fprintf(w, "-- %s %s %s\n", entry.Time.Format(time.RFC3339), entry.User, entry.Connection)
+		fmt.Fprintf(w, "%s;\n", strings.TrimRight(entry.SQL, "; \t\n"))
+	}
+}
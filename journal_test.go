@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalRoundTrip(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []JournalEntry{
+		{Time: now, User: "alice", Connection: "sqlite:/tmp/a.db", Table: "users", Op: "UPDATE",
+			Statement: "UPDATE users SET name = ? WHERE id = ?", Params: []any{"Bob", int64(1)},
+			SQL: "UPDATE users SET name = 'Bob' WHERE id = 1", Before: map[string]any{"name": "Alice"}},
+		{Time: now.Add(time.Hour), User: "alice", Connection: "sqlite:/tmp/a.db", Relation: "orders", Op: "SQL",
+			Statement: "DELETE FROM orders;", SQL: "DELETE FROM orders;"},
+	}
+	for _, entry := range entries {
+		if err := appendJournal(entry); err != nil {
+			t.Fatalf("appendJournal failed: %v", err)
+		}
+	}
+
+	path, err := getJournalPath()
+	if err != nil {
+		t.Fatalf("getJournalPath failed: %v", err)
+	}
+	if !strings.HasSuffix(path, "/ted/journal.jsonl") {
+		t.Errorf("Unexpected journal path %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	got, err := readJournal(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("readJournal failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Before["name"] != "Alice" || got[1].Op != "SQL" {
+		t.Fatalf("Unexpected entries: %+v", got)
+	}
+
+	tests := []struct {
+		name  string
+		table string
+		since time.Duration
+		want  int
+	}{
+		{"all", "", 0, 2},
+		{"by table", "users", 0, 1},
+		{"by relation", "orders", 0, 1},
+		{"since", "", 30 * time.Minute, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if n := len(filterJournal(got, tt.table, tt.since, now.Add(time.Hour))); n != tt.want {
+				t.Errorf("filterJournal() = %d entries, want %d", n, tt.want)
+			}
+		})
+	}
+
+	var script bytes.Buffer
+	writeJournalSQL(&script, got)
+	want := "UPDATE users SET name = 'Bob' WHERE id = 1;\n"
+	if !strings.Contains(script.String(), want) || !strings.Contains(script.String(), "DELETE FROM orders;\n") {
+		t.Errorf("Unexpected replay script:\n%s", script.String())
+	}
+}
+
+func TestJournalValue(t *testing.T) {
+	if v := journalValue([]byte("text")); v != "text" {
+		t.Errorf("journalValue(text) = %v", v)
+	}
+	if v := journalValue([]byte{0xff, 0x00}); v != `\xff00` {
+		t.Errorf("journalValue(binary) = %v", v)
+	}
+	if v := journalValue(int64(3)); v != int64(3) {
+		t.Errorf("journalValue(int) = %v", v)
+	}
+}
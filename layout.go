@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"ted/internal/dblib"
+)
+
+// ColumnLayout is a column's saved width, visibility and wrapping
+type ColumnLayout struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Hidden bool   `json:"hidden,omitempty"`
+	Pinned bool   `json:"pinned,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+}
+
+// SortLayout is a saved sort column
+type SortLayout struct {
+	Name string `json:"name"`
+	Desc bool   `json:"desc,omitempty"`
+}
+
+// QuickFilterLayout is a saved quick filter
+type QuickFilterLayout struct {
+	Column  string `json:"column"`
+	Value   any    `json:"value"`
+	Exclude bool   `json:"exclude,omitempty"`
+}
+
+// TableLayout is how a table was last shown: its columns in display order with the
+// hidden ones last, its sort, its filters and whether every column wraps
+type TableLayout struct {
+	Columns      []ColumnLayout      `json:"columns"`
+	Sort         []SortLayout        `json:"sort,omitempty"`
+	Filter       string              `json:"filter,omitempty"`
+	QuickFilters []QuickFilterLayout `json:"quick_filters,omitempty"`
+	WrapAll      bool                `json:"wrap_all,omitempty"`
+}
+
+// Layouts holds the saved table layouts by connection label, then by table name
+type Layouts map[string]map[string]TableLayout
+
+// getLayoutsPath returns the full path to layouts.json
+func getLayoutsPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "layouts.json"), nil
+}
+
+// LoadLayouts reads layouts.json, returning no layouts if it doesn't exist
+func LoadLayouts() (Layouts, error) {
+	path, err := getLayoutsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Layouts{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read layouts file: %w", err)
+	}
+
+	// Numbers are decoded exactly so integer filter values stay integers
+	var layouts Layouts
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&layouts); err != nil {
+		return nil, fmt.Errorf("could not parse layouts file: %w", err)
+	}
+	for _, tables := range layouts {
+		for _, layout := range tables {
+			for i, qf := range layout.QuickFilters {
+				if n, ok := qf.Value.(json.Number); ok {
+					if v, err := n.Int64(); err == nil {
+						layout.QuickFilters[i].Value = v
+					} else if v, err := n.Float64(); err == nil {
+						layout.QuickFilters[i].Value = v
+					}
+				}
+			}
+		}
+	}
+	if layouts == nil {
+		layouts = Layouts{}
+	}
+	return layouts, nil
+}
+
+// SaveLayouts writes the layouts to layouts.json, replacing it whole so another
+// session reading it never sees it half written
+func SaveLayouts(layouts Layouts) error {
+	if err := EnsureConfigDir(); err != nil {
+		return err
+	}
+	path, err := getLayoutsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(layouts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal layouts: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "layouts-*.json")
+	if err != nil {
+		return fmt.Errorf("could not write layouts file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("could not write layouts file: %w", err)
+	}
+	return nil
+}
+
+// savableFilterValue reports whether a quick filter value survives a JSON round trip
+func savableFilterValue(value any) bool {
+	switch value.(type) {
+	case nil, string, bool, int64, float64:
+		return true
+	}
+	return false
+}
+
+// layoutHeaders arranges a relation's default headers by a saved layout. Columns
+// the layout doesn't know are shown at the end and columns the relation no longer
+// has are dropped. If no saved column is shown, every column is.
+func layoutHeaders(defaults []dblib.DisplayColumn, layout TableLayout) (shown, hidden []dblib.DisplayColumn, pinned int) {
+	pinning := true
+	for _, saved := range layout.Columns {
+		i := slices.IndexFunc(defaults, func(h dblib.DisplayColumn) bool { return h.Name == saved.Name })
+		if i < 0 {
+			continue
+		}
+		header := defaults[i]
+		if saved.Width > 0 {
+			header.Width = saved.Width
+		}
+		header.Wrap = saved.Wrap
+		if saved.Hidden {
+			hidden = append(hidden, header)
+			continue
+		}
+		// Pinned columns lead the table
+		if pinning && saved.Pinned {
+			pinned++
+		} else {
+			pinning = false
+		}
+		shown = append(shown, header)
+	}
+	for _, header := range defaults {
+		known := slices.ContainsFunc(layout.Columns, func(c ColumnLayout) bool { return c.Name == header.Name })
+		if !known {
+			shown = append(shown, header)
+		}
+	}
+	if len(shown) == 0 {
+		return append(shown, hidden...), nil, 0
+	}
+	return shown, hidden, pinned
+}
+
+// layoutKey returns where the current relation's layout is saved. Custom SQL has no
+// saved layout.
+func (e *Editor) layoutKey() (connection, table string, ok bool) {
+	if e.relation == nil || e.relation.IsCustomSQL || e.config == nil {
+		return "", "", false
+	}
+	return e.config.connectionLabel(), e.relation.Name, true
+}
+
+// currentLayout captures the columns, sort, filters and wrapping shown
+func (e *Editor) currentLayout() TableLayout {
+	var layout TableLayout
+	pinned := e.table.GetPinned()
+	for i, h := range e.table.GetHeaders() {
+		layout.Columns = append(layout.Columns, ColumnLayout{Name: h.Name, Width: h.Width, Pinned: i < pinned, Wrap: h.Wrap})
+	}
+	for _, h := range e.hiddenCols {
+		layout.Columns = append(layout.Columns, ColumnLayout{Name: h.Name, Width: h.Width, Hidden: true, Wrap: h.Wrap})
+	}
+	for _, sc := range e.sortCols {
+		layout.Sort = append(layout.Sort, SortLayout{Name: sc.Name, Desc: !sc.Asc})
+	}
+	layout.Filter = e.relation.Filter
+	layout.WrapAll = e.table.GetWrapAll()
+	for _, qf := range e.relation.QuickFilters {
+		if savableFilterValue(qf.Value) {
+			layout.QuickFilters = append(layout.QuickFilters, QuickFilterLayout{Column: qf.Column, Value: qf.Value, Exclude: qf.Exclude})
+		}
+	}
+	return layout
+}
+
+// layoutChanged notes that the layout shown differs from the one saved. It's saved
+// once the pane shows another relation, its tab is left or the editor exits, rather
+// than on every keystroke adjusting it.
+func (e *Editor) layoutChanged() {
+	e.layoutDirty = true
+}
+
+// saveLayout remembers the current relation's layout for the next session if it
+// changed since last saved
+func (e *Editor) saveLayout() {
+	if !e.layoutDirty {
+		return
+	}
+	e.layoutDirty = false
+	connection, table, ok := e.layoutKey()
+	if !ok {
+		return
+	}
+	layouts, err := LoadLayouts()
+	if err != nil {
+		e.SetStatusError(fmt.Sprintf("Could not save layout: %v", err))
+		return
+	}
+	if layouts[connection] == nil {
+		layouts[connection] = make(map[string]TableLayout)
+	}
+	layouts[connection][table] = e.currentLayout()
+	if err := SaveLayouts(layouts); err != nil {
+		e.SetStatusError(fmt.Sprintf("Could not save layout: %v", err))
+	}
+}
+
+// saveLayouts saves the changed layouts of every pane open, as the editor exits
+func (e *Editor) saveLayouts() {
+	panes := []*pane{e.pane}
+	if e.split != nil {
+		panes = append(panes, e.split.master, e.split.detail)
+	}
+	for _, t := range e.tabs {
+		panes = append(panes, t.pane)
+		if t.split != nil {
+			panes = append(panes, t.split.master, t.split.detail)
+		}
+	}
+	for _, p := range panes {
+		if p != nil && p.layoutDirty {
+			e.withPane(p, e.saveLayout)
+		}
+	}
+}
+
+// savedLayout returns the layout saved for the current relation, if any
+func (e *Editor) savedLayout() (TableLayout, bool) {
+	connection, table, ok := e.layoutKey()
+	if !ok {
+		return TableLayout{}, false
+	}
+	layouts, err := LoadLayouts()
+	if err != nil {
+		e.SetStatusError(fmt.Sprintf("Could not load layout: %v", err))
+	}
+	layout, found := layouts[connection][table]
+	return layout, found
+}
+
+// applyLayoutColumns arranges, hides, pins and wraps the columns as layout saved them
+func (e *Editor) applyLayoutColumns(layout TableLayout) {
+	shown, hidden, pinned := layoutHeaders(e.table.GetHeaders(), layout)
+	e.table.SetHeaders(shown).SetPinned(pinned).SetWrapAll(layout.WrapAll)
+	e.hiddenCols = hidden
+	e.fitPending = false
+}
+
+// restoreLayout applies the current relation's saved layout and loads its rows from
+// the top. Saved filters are skipped when the relation is already filtered, e.g. by
+// --where, and dropped if the database no longer accepts them.
+func (e *Editor) restoreLayout() error {
+	layout, found := e.savedLayout()
+	if !found {
+		return e.loadFromRowId(nil, true, 0)
+	}
+	e.applyLayoutColumns(layout)
+
+	var sortCols []dblib.SortColumn
+	for _, sc := range layout.Sort {
+		if _, ok := e.relation.ColumnIndex[sc.Name]; ok {
+			sortCols = append(sortCols, dblib.SortColumn{Name: sc.Name, Asc: !sc.Desc})
+		}
+	}
+	e.setSort(sortCols)
+
+	restoredFilter := false
+	if !e.relation.HasFilter() {
+		if err := e.relation.SetFilter(layout.Filter); err == nil {
+			restoredFilter = layout.Filter != ""
+		}
+		for _, qf := range layout.QuickFilters {
+			if _, ok := e.relation.ColumnIndex[qf.Column]; ok {
+				e.relation.QuickFilters = append(e.relation.QuickFilters, dblib.QuickFilter{Column: qf.Column, Value: qf.Value, Exclude: qf.Exclude})
+				restoredFilter = true
+			}
+		}
+		e.showFilter()
+	}
+
+	err := e.loadFromRowId(nil, true, 0)
+	if err != nil && restoredFilter {
+		e.relation.Filter, e.relation.QuickFilters = "", nil
+		e.showFilter()
+		if err = e.loadFromRowId(nil, true, 0); err == nil {
+			e.SetStatusError("Saved filter no longer applies and was cleared")
+			e.layoutChanged()
+		}
+	}
+	return err
+}
+
+// resetLayout forgets the current relation's saved layout and shows it with the
+// default columns, unsorted and unfiltered
+func (e *Editor) resetLayout() {
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+	e.layoutDirty = false
+	if connection, table, ok := e.layoutKey(); ok {
+		layouts, err := LoadLayouts()
+		if err == nil && layouts[connection] != nil {
+			delete(layouts[connection], table)
+			if len(layouts[connection]) == 0 {
+				delete(layouts, connection)
+			}
+			err = SaveLayouts(layouts)
+		}
+		if err != nil {
+			e.SetStatusError(fmt.Sprintf("Could not reset layout: %v", err))
+			return
+		}
+	}
+
+	e.relation.Filter, e.relation.QuickFilters = "", nil
+	e.setRelation(e.relation, e.table.tableName)
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.table.Select(0, 0)
+	e.SetStatusMessage("Layout reset")
+}
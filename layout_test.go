@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestLayoutHeaders(t *testing.T) {
+	defaults := []dblib.DisplayColumn{
+		{Name: "id", Width: DefaultColumnWidth, IsKey: true},
+		{Name: "name", Width: DefaultColumnWidth},
+		{Name: "email", Width: DefaultColumnWidth},
+		{Name: "created", Width: DefaultColumnWidth},
+	}
+	// Saved before email was added and while the table still had a dropped column
+	layout := TableLayout{Columns: []ColumnLayout{
+		{Name: "name", Width: 30, Pinned: true},
+		{Name: "dropped", Width: 5},
+		{Name: "id", Width: 4, Wrap: true},
+		{Name: "created", Hidden: true},
+	}}
+
+	shown, hidden, pinned := layoutHeaders(defaults, layout)
+	names := func(headers []dblib.DisplayColumn) (names []string) {
+		for _, h := range headers {
+			names = append(names, h.Name)
+		}
+		return names
+	}
+	if got := names(shown); len(got) != 3 || got[0] != "name" || got[1] != "id" || got[2] != "email" {
+		t.Errorf("Unexpected shown columns %v", got)
+	}
+	if got := names(hidden); len(got) != 1 || got[0] != "created" {
+		t.Errorf("Unexpected hidden columns %v", got)
+	}
+	if pinned != 1 || shown[0].Width != 30 || shown[1].Width != 4 || !shown[1].IsKey || shown[2].Width != DefaultColumnWidth {
+		t.Errorf("Unexpected widths or pinning: pinned %d, %+v", pinned, shown)
+	}
+	if shown[0].Wrap || !shown[1].Wrap {
+		t.Errorf("Expected only id wrapped, got %+v", shown)
+	}
+
+	// A layout whose shown columns were all dropped shows everything
+	shown, hidden, _ = layoutHeaders(defaults[:2], TableLayout{Columns: []ColumnLayout{
+		{Name: "id", Hidden: true}, {Name: "name", Hidden: true}, {Name: "gone"},
+	}})
+	if len(shown) != 2 || len(hidden) != 0 {
+		t.Errorf("Expected every column shown, got %v and %v", names(shown), names(hidden))
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+
+	width := e.table.GetColumnWidth(1) + 5
+	e.adjustColumnWidth(1, 5)
+	e.moveColumn(1, -1)
+	e.toggleSort(0, false)
+	e.toggleSort(0, false)
+	e.addQuickFilter(0, 1, true) // hides Alice, still selected after sorting
+	e.toggleWrap(-1, true)
+
+	// Reopen the table as a new session would
+	relation, err := dblib.NewRelation(db, dblib.SQLite, "users")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.setRelation(relation, "users")
+	if err := e.restoreLayout(); err != nil {
+		t.Fatalf("restoreLayout failed: %v", err)
+	}
+	headers := e.table.GetHeaders()
+	if len(headers) != 2 || headers[0].Name != "name" || headers[0].Width != width {
+		t.Errorf("Expected the resized name column first, got %+v", headers)
+	}
+	if len(e.sortCols) != 1 || e.sortCols[0].Name != "name" || e.sortCols[0].Asc {
+		t.Errorf("Expected a descending sort by name, got %+v", e.sortCols)
+	}
+	if !e.table.GetWrapAll() {
+		t.Errorf("Expected every column wrapped")
+	}
+	qfs := relation.QuickFilters
+	if len(qfs) != 1 || qfs[0].Column != "id" || qfs[0].Value != int64(1) || !qfs[0].Exclude {
+		t.Errorf("Expected the quick filter restored, got %+v", qfs)
+	}
+	if got := e.table.GetCell(0, 0); got != "Bob" {
+		t.Errorf("Expected only Bob after the restored filter, got %v", got)
+	}
+
+	// Reset forgets the layout
+	e.resetLayout()
+	relation, _ = dblib.NewRelation(db, dblib.SQLite, "users")
+	e.setRelation(relation, "users")
+	if err := e.restoreLayout(); err != nil {
+		t.Fatalf("restoreLayout failed: %v", err)
+	}
+	if headers := e.table.GetHeaders(); headers[0].Name != "id" || len(e.sortCols) != 0 || relation.HasFilter() {
+		t.Errorf("Expected the default layout after reset, got %+v sorted by %+v", headers, e.sortCols)
+	}
+}
+
+func TestLayoutSavedOnExit(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.statusBar = nil
+
+	width := e.table.GetColumnWidth(1) + 3
+	for range 3 {
+		e.adjustColumnWidth(1, 1)
+	}
+	e.togglePin(1)
+	if layouts, err := LoadLayouts(); err != nil || len(layouts) != 0 {
+		t.Fatalf("Expected nothing saved while adjusting, got %v, %v", layouts, err)
+	}
+
+	e.saveLayouts()
+	layouts, err := LoadLayouts()
+	if err != nil {
+		t.Fatalf("LoadLayouts failed: %v", err)
+	}
+	columns := layouts[e.config.connectionLabel()]["users"].Columns
+	if len(columns) != 2 || columns[0].Name != "name" || columns[0].Width != width || !columns[0].Pinned {
+		t.Errorf("Expected the widened name column pinned first, got %+v", columns)
+	}
+
+	// The file is replaced whole, leaving no temporary file behind
+	path, _ := getLayoutsPath()
+	if entries, err := os.ReadDir(filepath.Dir(path)); err != nil || len(entries) != 1 {
+		t.Errorf("Expected only layouts.json in the config directory, got %v", entries)
+	}
+}
@@ -32,6 +32,11 @@ var (
 	completion     string
 	vimMode        bool
 	sqlStatement   string
+	dryRun         bool
+	cascadeDepth   int
+	where          string
+	gotoKey        string
+	pinKeys        bool
 )
 
 var rootCmd = &cobra.Command{
@@ -151,6 +156,15 @@ Examples:
 			useVimMode = settings.VimMode
 		}
 
+		// Pin key columns if the flag or settings ask for it
+		usePinKeys := pinKeys || (settings != nil && settings.PinKeys)
+
+		// Colors from the settings' theme, fitted to the terminal
+		colorTheme, err := LoadTheme(settings, terminalColors())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v, using the dark theme\n", err)
+		}
+
 		config := &Config{
 			Database:       getValue(database, dbname),
 			Host:           host,
@@ -158,8 +172,14 @@ Examples:
 			Username:       username,
 			Password:       password,
 			Command:        command,
+			Where:          where,
+			Goto:           gotoKey,
 			DBTypeOverride: dbTypeOverride,
 			VimMode:        useVimMode,
+			PinKeys:        usePinKeys,
+			DryRun:         dryRun,
+			CascadeDepth:   cascadeDepth,
+			Theme:          colorTheme,
 		}
 
 		// Table/view name is now optional - the picker will be shown in the editor if not provided
@@ -192,7 +212,12 @@ func init() {
 	}
 	rootCmd.Flags().StringVar(&completion, "completion", "", "Generate shell completions (bash, zsh, fish, powershell)")
 	rootCmd.Flags().BoolVar(&vimMode, "vim", false, "Enable vim mode for table navigation")
+	rootCmd.Flags().BoolVar(&pinKeys, "pin-keys", false, "Pin key columns at the left while scrolling sideways")
 	rootCmd.Flags().StringVar(&sqlStatement, "sql", "", "Custom SQL SELECT statement to execute")
+	rootCmd.Flags().StringVar(&where, "where", "", "SQL predicate limiting the rows shown, e.g. \"status = 'open'\"")
+	rootCmd.Flags().StringVar(&gotoKey, "goto", "", "Key of the row to open at, comma-separated for composite keys")
+	rootCmd.Flags().IntVar(&cascadeDepth, "cascade-depth", dblib.DefaultCascadeDepth, "Levels of ON DELETE CASCADE to follow when previewing a delete")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Collect edits, inserts and deletes into a SQL script instead of writing them")
 
 	if err := rootCmd.RegisterFlagCompletionFunc("pg", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"hi", "pg"}, cobra.ShellCompDirectiveNoFileComp
@@ -12,6 +12,10 @@ type Settings struct {
 	CrashReportingEnabled bool `json:"crash_reporting_enabled"`
 	FirstRunComplete      bool `json:"first_run_complete"`
 	VimMode               bool `json:"vim_mode"`
+	PinKeys               bool `json:"pin_keys"`
+	// Theme names the built-in color theme, and ThemeColors overrides its elements
+	Theme       string            `json:"theme,omitempty"`
+	ThemeColors map[string]string `json:"theme_colors,omitempty"`
 }
 
 // UnmarshalJSON ensures backward compatibility with legacy telemetry settings.
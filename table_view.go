@@ -2,9 +2,14 @@ package main
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 	"github.com/rivo/tview"
 
 	"ted/internal/dblib"
@@ -16,6 +21,10 @@ type Viewport struct {
 	screen      tcell.Screen // Reference to the tcell screen
 	tableWidth  int          // Total width of the table content
 	screenWidth int          // Width of the visible area
+
+	// Pinned columns at the left don't scroll; scrolled content is hidden under them
+	left   int // Screen x of the table's left edge
+	frozen int // Width of the pinned region, including its borders
 }
 
 // NewViewport creates a new viewport
@@ -45,13 +54,35 @@ func (v *Viewport) SetDimensions(tableWidth, screenWidth int) {
 	}
 }
 
-// SetContent calls screen.SetContent with x adjusted by scrollX
+// SetFrozen pins the first width cells of the table starting at screen x left
+func (v *Viewport) SetFrozen(left, width int) {
+	v.left = left
+	v.frozen = max(width, 0)
+}
+
+// SetContent calls screen.SetContent with x adjusted by scrollX, leaving the pinned
+// region in place
 func (v *Viewport) SetContent(x, y int, ch rune, combc []rune, style tcell.Style) {
-	if v.screen != nil {
+	if v.screen == nil {
+		return
+	}
+	if x-v.left < v.frozen {
+		v.screen.SetContent(x, y, ch, combc, style)
+		return
+	}
+	if x-v.scrollX-v.left >= v.frozen {
 		v.screen.SetContent(x-v.scrollX, y, ch, combc, style)
 	}
 }
 
+// TableX converts a screen x to a position in the table, relative to its left edge
+func (v *Viewport) TableX(screenX int) int {
+	if x := screenX - v.left; x < v.frozen {
+		return x
+	}
+	return screenX - v.left + v.scrollX
+}
+
 // ScrollLeft scrolls the viewport left by one unit
 func (v *Viewport) ScrollLeft() {
 	if v.scrollX > 0 {
@@ -88,17 +119,20 @@ func (v *Viewport) SetScrollX(offset int) {
 // startX is the left edge of the column, endX is the right edge
 func (v *Viewport) EnsureColumnVisible(startX, endX, screenWidth int) {
 	// endX is exclusive (one past the last character of the column)
-	// We need the column to fit within the visible area: [scrollX, scrollX + screenWidth)
+	// We need the column to fit within the visible area right of the pinned columns:
+	// [scrollX + frozen, scrollX + screenWidth)
 
-	if endX-startX >= screenWidth {
-		// Column is wider than screen, just show from the start of the column
-		v.scrollX = startX
+	if endX <= v.frozen {
+		// Pinned columns are always visible
 		return
 	}
 
-	if startX < v.scrollX {
+	if endX-startX >= screenWidth-v.frozen {
+		// Column is wider than screen, just show from the start of the column
+		v.scrollX = startX - v.frozen
+	} else if startX < v.scrollX+v.frozen {
 		// Column starts before visible area - scroll left
-		v.scrollX = startX
+		v.scrollX = startX - v.frozen
 	} else if endX > v.scrollX+screenWidth {
 		// Column ends after visible area - scroll right
 		v.scrollX = endX - screenWidth
@@ -118,15 +152,46 @@ type TableView struct {
 	headers   []dblib.DisplayColumn
 	data      []Row
 	tableName string // Name of the current table to display in header
+	filter    string // WHERE predicate shown after the table name
+
+	// Open tabs, drawn in place of the table name when there are several, the active
+	// one being this table's
+	tabs      []string
+	activeTab int
+	tabSpans  [][2]int // Start and end x of each drawn tab, relative to the table
+
+	// Quick filter chips, shown after the filter and removed by clicking
+	chips     []string
+	chipSpans [][2]int // Start and end x of each drawn chip, relative to the table
+
+	// Row count and position of the first visible row, shown in the header and as a
+	// scrollbar. rowCount is -1 when unknown, firstRow when the position is.
+	rowCount      int64
+	rowCountExact bool
+	firstRow      int64
+
+	// Scrollbar drawn at the right edge, in screen coordinates, and dragged with the mouse
+	scrollbarX, scrollbarY, scrollbarLen int
+	draggingScrollbar                    bool
+	scrollbarSent                        float64   // fraction last reported while dragging
+	scrollbarSentAt                      time.Time // when it was reported
 
 	// Display configuration
-	cellPadding     int
-	borderColor     tcell.Color
-	headerColor     tcell.Color
-	headerBgColor   tcell.Color
-	readOnlyBgColor tcell.Color
-	separatorChar   rune
-	bottom          bool
+	cellPadding   int
+	separatorChar rune
+	bottom        bool
+	sort          []dblib.SortColumn // Sort columns marked in the header, in priority order
+
+	// Number of leading columns pinned at the left during horizontal scrolling
+	pinned int
+
+	// Text wrapping over several lines per row, in every column or those marked Wrap.
+	// Taller rows may not all fit, so drawing starts at rowOffset to keep the
+	// selected row on screen, and rowLines records the row drawn on each data line.
+	wrapAll   bool
+	rowOffset int
+	rowLines  []int
+	dataLines int // Data lines drawn in the last Draw
 
 	// Selection state
 	selectedRow int
@@ -135,6 +200,7 @@ type TableView struct {
 	deleteMode  bool // When true, selected row has red background
 	findMode    bool // When true, selected column has dark blue background
 	vimMode     bool // When true, display "vim mode" indicator
+	dimmed      bool // When true, the table name is grey, marking the pane without focus in a split
 
 	// Callbacks
 	doubleClickFunc     func(row, col int)
@@ -142,6 +208,12 @@ type TableView struct {
 	selectionChangeFunc func(row, col int)
 	mouseScrollFunc     func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse)
 	tableNameClickFunc  func()
+	headerClickFunc     func(col int, add bool)
+	chipClickFunc       func(chip int)
+	tabClickFunc        func(tab int)
+	scrollbarFunc       func(fraction float64)
+	columnResizeFunc    func(col int)
+	resizeFunc          func(width, rows int)
 
 	// Double-click tracking
 	lastClickRow int
@@ -157,6 +229,9 @@ type TableView struct {
 
 	// Viewport information
 	rowsHeight int
+
+	// Size last drawn at, to report changes to resizeFunc
+	drawnWidth, drawnHeight int
 }
 
 // TableViewConfig holds configuration for creating a TableView
@@ -166,26 +241,30 @@ type TableViewConfig struct {
 	SingleClickFunc    func(row, col int)
 	MouseScrollFunc    func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse)
 	TableNameClickFunc func()
+	HeaderClickFunc    func(col int, add bool)
+	ChipClickFunc      func(chip int)
+	TabClickFunc       func(tab int)
+	ScrollbarFunc      func(fraction float64)
+	ColumnResizeFunc   func(col int)
+	ResizeFunc         func(width, rows int)
 }
 
 // NewTableView creates a new table view component with the given configuration
 func NewTableView(height int, config *TableViewConfig) *TableView {
 	tv := &TableView{
-		Box:             tview.NewBox(),
-		cellPadding:     1,
-		borderColor:     tcell.ColorWhite,
-		headerColor:     tcell.ColorWhite,
-		headerBgColor:   tcell.ColorDarkSlateGray,
-		readOnlyBgColor: tcell.ColorDarkGray,
-		separatorChar:   '│',
-		selectedRow:     0,
-		selectedCol:     0,
-		selectable:      true,
-		lastClickRow:    -1,
-		lastClickCol:    -1,
-		resizingColumn:  -1,
-		viewport:        NewViewport(),
-		rowsHeight:      height,
+		Box:            tview.NewBox(),
+		cellPadding:    1,
+		separatorChar:  '│',
+		selectedRow:    0,
+		selectedCol:    0,
+		selectable:     true,
+		lastClickRow:   -1,
+		lastClickCol:   -1,
+		resizingColumn: -1,
+		rowCount:       -1,
+		firstRow:       -1,
+		viewport:       NewViewport(),
+		rowsHeight:     height,
 	}
 
 	tv.SetBorder(false) // We'll draw our own borders
@@ -207,6 +286,24 @@ func NewTableView(height int, config *TableViewConfig) *TableView {
 		if config.TableNameClickFunc != nil {
 			tv.SetTableNameClickFunc(config.TableNameClickFunc)
 		}
+		if config.HeaderClickFunc != nil {
+			tv.SetHeaderClickFunc(config.HeaderClickFunc)
+		}
+		if config.ChipClickFunc != nil {
+			tv.SetChipClickFunc(config.ChipClickFunc)
+		}
+		if config.TabClickFunc != nil {
+			tv.SetTabClickFunc(config.TabClickFunc)
+		}
+		if config.ScrollbarFunc != nil {
+			tv.SetScrollbarFunc(config.ScrollbarFunc)
+		}
+		if config.ColumnResizeFunc != nil {
+			tv.SetColumnResizeFunc(config.ColumnResizeFunc)
+		}
+		if config.ResizeFunc != nil {
+			tv.SetResizeFunc(config.ResizeFunc)
+		}
 	}
 
 	return tv
@@ -225,6 +322,175 @@ func (tv *TableView) SetTableName(name string) *TableView {
 	return tv
 }
 
+// SetSort sets the sort columns shown with ▲/▼ in the header
+func (tv *TableView) SetSort(sortCols []dblib.SortColumn) *TableView {
+	tv.sort = sortCols
+	return tv
+}
+
+// SetFilter sets the row filter shown next to the table name
+func (tv *TableView) SetFilter(filter string) *TableView {
+	tv.filter = filter
+	return tv
+}
+
+// SetTabs sets the names of the open tabs and which is this table's. With fewer
+// than two only the table name is shown.
+func (tv *TableView) SetTabs(tabs []string, active int) *TableView {
+	tv.tabs, tv.activeTab = tabs, active
+	return tv
+}
+
+// SetChips sets the quick filter labels shown after the filter
+func (tv *TableView) SetChips(chips []string) *TableView {
+	tv.chips = chips
+	return tv
+}
+
+// SetRowPosition sets the row count, and how many rows come before the first visible
+// row. count is -1 when unknown and firstRow when the position is; estimated counts
+// and positions are shown as approximate.
+func (tv *TableView) SetRowPosition(firstRow, count int64, exact bool) *TableView {
+	tv.firstRow, tv.rowCount, tv.rowCountExact = firstRow, count, exact
+	return tv
+}
+
+// SetPinned pins the first n columns so they stay in view during horizontal scrolling
+func (tv *TableView) SetPinned(n int) *TableView {
+	tv.pinned = max(0, min(n, len(tv.headers)))
+	return tv
+}
+
+// GetPinned returns how many leading columns are pinned
+func (tv *TableView) GetPinned() int {
+	return tv.pinned
+}
+
+// pinnedWidth returns the width of the pinned columns with the left border and the
+// separator after them, or 0 when nothing is pinned
+func (tv *TableView) pinnedWidth() int {
+	if tv.pinned == 0 {
+		return 0
+	}
+	_, endX := tv.GetColumnPosition(tv.pinned - 1)
+	return endX + 1
+}
+
+// SetDimmed greys out the table name, marking the table without focus in a split
+func (tv *TableView) SetDimmed(dimmed bool) *TableView {
+	tv.dimmed = dimmed
+	return tv
+}
+
+// SetWrapAll wraps text in every column, not just those marked Wrap
+func (tv *TableView) SetWrapAll(wrap bool) *TableView {
+	tv.wrapAll = wrap
+	return tv
+}
+
+// GetWrapAll reports whether text wraps in every column
+func (tv *TableView) GetWrapAll() bool {
+	return tv.wrapAll
+}
+
+// SetColumnWrap sets whether text wraps in a column
+func (tv *TableView) SetColumnWrap(col int, wrap bool) *TableView {
+	if col >= 0 && col < len(tv.headers) {
+		tv.headers[col].Wrap = wrap
+	}
+	return tv
+}
+
+// wraps reports whether any column wraps, so rows may be taller than a line
+func (tv *TableView) wraps() bool {
+	return tv.wrapAll || slices.ContainsFunc(tv.headers, func(h dblib.DisplayColumn) bool { return h.Wrap })
+}
+
+// cellLines splits a cell's text into the lines drawn for it: one unless its column
+// wraps, and at most MaxRowLines with the last ending in … if the text is cut
+func (tv *TableView) cellLines(header dblib.DisplayColumn, text string) []string {
+	if !tv.wrapAll && !header.Wrap {
+		return []string{text}
+	}
+	lines := wrapLines(text, max(header.Width, 1))
+	if len(lines) > MaxRowLines {
+		lines = lines[:MaxRowLines]
+		lines[MaxRowLines-1] = runewidth.Truncate(lines[MaxRowLines-1], header.Width-1, "") + "…"
+	}
+	return lines
+}
+
+// rowHeight returns how many lines row i takes. The insert row is always one line.
+func (tv *TableView) rowHeight(i int) int {
+	if !tv.wraps() || i < 0 || i >= len(tv.data) || tv.data[i].state == RowStateInsert {
+		return 1
+	}
+	height := 1
+	for _, header := range tv.headers {
+		if header.Index < len(tv.data[i].data) {
+			text, _ := formatCellValue(tv.data[i].data[header.Index], tcell.StyleDefault)
+			height = max(height, len(tv.cellLines(header, text)))
+		}
+	}
+	return height
+}
+
+// rowHeights returns how many lines each row takes, or nil when no column wraps and
+// every row takes one. Draw works them out once and uses them throughout.
+func (tv *TableView) rowHeights() []int {
+	if !tv.wraps() {
+		return nil
+	}
+	heights := make([]int, len(tv.data))
+	for i := range heights {
+		heights[i] = tv.rowHeight(i)
+	}
+	return heights
+}
+
+// scrollOffset returns the first row to draw so the selected row fits in lines data
+// lines, moving as little as possible from the last offset. heights are the rows'
+// heights from rowHeights.
+func (tv *TableView) scrollOffset(lines int, heights []int) int {
+	if heights == nil {
+		return 0
+	}
+	offset := min(tv.rowOffset, max(len(tv.data)-1, 0))
+	selected := tv.selectedRow
+	if selected < 0 || selected >= len(tv.data) {
+		return offset
+	}
+	offset = min(offset, selected)
+	used := 0
+	for i := offset; i <= selected; i++ {
+		used += heights[i]
+	}
+	for offset < selected && used > lines {
+		used -= heights[offset]
+		offset++
+	}
+	return offset
+}
+
+// rowAtLine returns the row drawn on a data line, or -1 if none is
+func (tv *TableView) rowAtLine(line int) int {
+	if tv.rowLines == nil {
+		return line
+	}
+	if line < 0 || line >= len(tv.rowLines) {
+		return -1
+	}
+	return tv.rowLines[line]
+}
+
+// RowLine returns the data line a row starts on, or -1 if it isn't drawn
+func (tv *TableView) RowLine(row int) int {
+	if tv.rowLines == nil {
+		return row
+	}
+	return slices.Index(tv.rowLines, row)
+}
+
 // SetVimMode enables or disables vim mode indicator
 func (tv *TableView) SetVimMode(enabled bool) *TableView {
 	tv.vimMode = enabled
@@ -312,29 +578,78 @@ func (tv *TableView) SetTableNameClickFunc(handler func()) *TableView {
 	return tv
 }
 
+// SetHeaderClickFunc sets the function to call when a column header is clicked.
+// add is true when a modifier key is held, to add the column to the sort.
+func (tv *TableView) SetHeaderClickFunc(handler func(col int, add bool)) *TableView {
+	tv.headerClickFunc = handler
+	return tv
+}
+
+// SetChipClickFunc sets the function to call when a quick filter chip is clicked
+func (tv *TableView) SetChipClickFunc(handler func(chip int)) *TableView {
+	tv.chipClickFunc = handler
+	return tv
+}
+
+// SetTabClickFunc sets the function to call when a tab other than the active one is clicked
+func (tv *TableView) SetTabClickFunc(handler func(tab int)) *TableView {
+	tv.tabClickFunc = handler
+	return tv
+}
+
+// SetScrollbarFunc sets the function to call when the scrollbar is clicked or dragged,
+// with how far down it was grabbed from 0 to 1
+func (tv *TableView) SetScrollbarFunc(handler func(fraction float64)) *TableView {
+	tv.scrollbarFunc = handler
+	return tv
+}
+
+// SetColumnResizeFunc sets the function to call when a column has been resized by dragging its border
+func (tv *TableView) SetColumnResizeFunc(handler func(col int)) *TableView {
+	tv.columnResizeFunc = handler
+	return tv
+}
+
+// SetResizeFunc sets a handler called when the table is drawn at a new size, with
+// its width and the number of data rows that fit
+func (tv *TableView) SetResizeFunc(handler func(width, rows int)) *TableView {
+	tv.resizeFunc = handler
+	return tv
+}
+
+// dataIndex returns where column col's value is in each row, or -1
+func (tv *TableView) dataIndex(col int) int {
+	if col < 0 || col >= len(tv.headers) {
+		return -1
+	}
+	return tv.headers[col].Index
+}
+
 // GetCell returns the value at the specified data coordinates
 func (tv *TableView) GetCell(row, col int) any {
-	if row >= 0 && row < len(tv.data) && col >= 0 && col < len(tv.data[row].data) {
-		return tv.data[row].data[col]
+	idx := tv.dataIndex(col)
+	if row >= 0 && row < len(tv.data) && idx >= 0 && idx < len(tv.data[row].data) {
+		return tv.data[row].data[idx]
 	}
 	return nil
 }
 
 // SetCell sets the value at the specified data coordinates
 func (tv *TableView) SetCell(row, col int, value any) *TableView {
-	if row >= 0 && row < len(tv.data) && col >= 0 && col < len(tv.data[row].data) {
-		tv.data[row].data[col] = value
+	idx := tv.dataIndex(col)
+	if row >= 0 && row < len(tv.data) && idx >= 0 && idx < len(tv.data[row].data) {
+		tv.data[row].data[idx] = value
 	}
 	return tv
 }
 
-// UpdateRowsHeightFromRect calculates and updates rowsHeight based on the provided height.
-// This should be called before loading rows from the database to ensure the correct number
-// of rows are fetched based on the current display height. This method accepts the height
-// as a parameter to avoid calling GetRect() from the UI event loop which can cause deadlocks.
-func (tv *TableView) UpdateRowsHeightFromRect(height int) {
-	// Update the rowsHeight field
-	tv.rowsHeight = height - 3
+// fittingRows returns how many data rows fit in height lines, below the table name,
+// borders and header
+func (tv *TableView) fittingRows(height int) int {
+	if tv.tableName != "" {
+		height--
+	}
+	return height - 3
 }
 
 // Draw renders the table view
@@ -346,6 +661,14 @@ func (tv *TableView) Draw(screen tcell.Screen) {
 		return
 	}
 
+	// Report a new size, which the handler may only act on after this draw
+	if width != tv.drawnWidth || height != tv.drawnHeight {
+		tv.drawnWidth, tv.drawnHeight = width, height
+		if tv.resizeFunc != nil {
+			tv.resizeFunc(width, tv.fittingRows(height))
+		}
+	}
+
 	// Initialize viewport with the screen
 	tv.viewport.SetScreen(screen)
 
@@ -361,12 +684,33 @@ func (tv *TableView) Draw(screen tcell.Screen) {
 
 	currentY := y
 
-	// Draw table name header if table name is set
+	// Check if we should draw the bottom border (when final slice is nil)
+	drawBottomBorder := tv.bottom
+	if len(tv.data) > 0 && len(tv.data[len(tv.data)-1].data) == 0 {
+		drawBottomBorder = true
+	}
+	maxDataRows := height - 3 // Reserve space for top border and header
+	if drawBottomBorder {
+		maxDataRows = maxDataRows - 1
+	}
+
+	// Rows taller than a line start lower down to keep the selected row on screen
+	tv.dataLines = min(maxDataRows, tv.fittingRows(height))
+	heights := tv.rowHeights()
+	tv.rowOffset = tv.scrollOffset(tv.dataLines, heights)
+
+	// Draw table name header if table name is set, scrolling as a whole
+	tv.viewport.SetFrozen(x, 0)
 	if tv.tableName != "" {
 		tv.drawTableNameHeader(x, currentY, tableWidth)
 		currentY++
 	}
 
+	// Pinned columns stay put unless they leave no room for the others
+	if pinned := tv.pinnedWidth(); pinned < width {
+		tv.viewport.SetFrozen(x, pinned)
+	}
+
 	// Draw top border
 	tv.drawTopBorder(x, currentY, tableWidth)
 	currentY++
@@ -383,33 +727,35 @@ func (tv *TableView) Draw(screen tcell.Screen) {
 		currentY++
 	}
 
-	// Check if we should draw the bottom border (when final slice is nil)
-	drawBottomBorder := tv.bottom
-	if len(tv.data) > 0 && len(tv.data[len(tv.data)-1].data) == 0 {
-		drawBottomBorder = true
-	}
-
 	// Draw data rows
+	dataY := currentY
 	dataRowsDrawn := 0
-	maxDataRows := height - 3 // Reserve space for top border and header
-	if drawBottomBorder {
-		maxDataRows = maxDataRows - 1
-	}
 
-	// Draw data rows (including insert row if present)
-	for i := 0; i < len(tv.data) && dataRowsDrawn < maxDataRows && currentY < y+height; i++ {
+	// Draw data rows (including insert row if present), a line at a time
+	tv.rowLines = tv.rowLines[:0]
+	for i := tv.rowOffset; i < len(tv.data) && dataRowsDrawn < maxDataRows && currentY < y+height; i++ {
 		if tv.data[i].data == nil || len(tv.data[i].data) == 0 {
 			break // Stop drawing when we hit a nil slice
 		}
-		tv.drawDataRow(x, currentY, tableWidth, i)
-		currentY++
-		dataRowsDrawn++
+		rowHeight := 1
+		if heights != nil {
+			rowHeight = heights[i]
+		}
+		for line := 0; line < rowHeight && dataRowsDrawn < maxDataRows && currentY < y+height; line++ {
+			tv.drawDataRow(x, currentY, tableWidth, i, line)
+			tv.rowLines = append(tv.rowLines, i)
+			currentY++
+			dataRowsDrawn++
+		}
 	}
 
 	// Draw bottom border (if enabled or if final slice is nil)
 	if drawBottomBorder && currentY < y+height {
 		tv.drawBottomBorder(x, currentY, tableWidth)
 	}
+
+	// Scrollbar over the right edge of the screen, which doesn't scroll sideways
+	tv.drawScrollbar(screen, x+width-1, dataY, min(maxDataRows, y+height-dataY))
 }
 
 // calculateTableWidth calculates the total width needed for the table
@@ -442,55 +788,235 @@ func (tv *TableView) drawTableNameHeader(x, y, tableWidth int) {
 	if isSQL {
 		// SQL: italic, no dropdown arrow
 		headerText = fmt.Sprintf(" %s", tv.tableName)
-		style = tcell.StyleDefault.Foreground(tcell.ColorWhite).Italic(true)
+		style = theme.Title.Italic(true)
 	} else {
 		// Table/view: normal, with dropdown
 		headerText = fmt.Sprintf(" %s", tv.tableName)
-		style = tcell.StyleDefault.Foreground(tcell.ColorWhite)
+		style = theme.Title
+	}
+	if tv.dimmed {
+		style = over(style, theme.Muted)
 	}
 
-	// Vim mode indicator to display on the right
+	// Row position and vim mode indicator to display on the right
 	vimModeText := ""
+	if position := formatRowPosition(tv.visibleFirstRow(), int64(tv.visibleRows()), tv.rowCount, tv.rowCountExact); position != "" {
+		vimModeText = " " + position + " "
+	}
 	if tv.vimMode {
-		vimModeText = " vim mode"
+		vimModeText += " vim mode"
 	}
 
-	// Draw the header text left-aligned
+	// Draw the header text left-aligned, among the other tabs when there are several
+	limit := x + tableWidth - utf8.RuneCountInString(vimModeText)
+	tabs, active := tv.tabs, tv.activeTab
+	if len(tabs) < 2 {
+		tabs, active = nil, 0
+	}
 	pos := x
-	for _, ch := range headerText {
+	tv.tabSpans = tv.tabSpans[:0]
+	tabStyle := theme.Muted
+	for i := range max(len(tabs), 1) {
+		start := pos
+		if i == active {
+			for _, ch := range headerText + " ▾" {
+				tv.viewport.SetContent(pos, y, ch, nil, style)
+				pos++
+			}
+		} else {
+			for _, ch := range " " + truncateTabName(tabs[i]) + " " {
+				if pos >= limit {
+					break
+				}
+				tv.viewport.SetContent(pos, y, ch, nil, tabStyle)
+				pos++
+			}
+		}
+		tv.tabSpans = append(tv.tabSpans, [2]int{start - x, pos - x})
+		if i < len(tabs)-1 && pos < limit {
+			tv.viewport.SetContent(pos, y, '│', nil, tabStyle)
+			pos++
+		}
+	}
+
+	// Active filter and quick filter chips, clipped before the right-aligned text
+	if tv.filter != "" {
+		filterStyle := theme.Filter
+		for _, ch := range "  WHERE " + tv.filter {
+			if pos >= limit {
+				break
+			}
+			tv.viewport.SetContent(pos, y, ch, nil, filterStyle)
+			pos++
+		}
+	}
+	tv.chipSpans = tv.chipSpans[:0]
+	chipStyle := theme.Chip
+	for _, chip := range tv.chips {
+		if pos+2 >= limit {
+			break
+		}
+		tv.viewport.SetContent(pos, y, ' ', nil, style)
+		tv.viewport.SetContent(pos+1, y, ' ', nil, style)
+		pos += 2
+		start := pos
+		for _, ch := range " " + chip + " ✕ " {
+			if pos >= limit {
+				break
+			}
+			tv.viewport.SetContent(pos, y, ch, nil, chipStyle)
+			pos++
+		}
+		tv.chipSpans = append(tv.chipSpans, [2]int{start - x, pos - x})
+	}
+
+	// Fill the middle with spaces, stopping before the right-aligned text
+	for pos < limit {
+		tv.viewport.SetContent(pos, y, ' ', nil, style)
+		pos++
+	}
+
+	// Draw position and vim mode text right-aligned
+	for _, ch := range vimModeText {
 		tv.viewport.SetContent(pos, y, ch, nil, style)
 		pos++
 	}
-	tv.viewport.SetContent(pos, y, ' ', nil, style)
-	pos++
-	tv.viewport.SetContent(pos, y, '▾', nil, style)
-	pos++
+}
 
-	// Calculate where vim mode text should start (right-aligned)
-	vimModeStartPos := x + tableWidth - len(vimModeText)
+// truncateTabName shortens the name of a tab other than the active one
+func truncateTabName(name string) string {
+	const maxLen = 20
+	name = strings.Join(strings.Fields(name), " ") // SQL on one line
+	if runes := []rune(name); len(runes) > maxLen {
+		return string(runes[:maxLen-1]) + "…"
+	}
+	return name
+}
 
-	// Fill the middle with spaces, stopping before vim mode text
-	endPos := x + tableWidth
-	if tv.vimMode {
-		endPos = vimModeStartPos
+// visibleRows counts the data rows on screen, excluding the insert row and borders.
+// With wrapped text only rows drawn whole count.
+func (tv *TableView) visibleRows() int {
+	wraps := tv.wraps()
+	n, lines := 0, tv.dataLines
+	for i := tv.rowOffset; i < len(tv.data) && len(tv.data[i].data) > 0; i++ {
+		if wraps {
+			if lines -= tv.rowHeight(i); lines < 0 {
+				break
+			}
+		}
+		if tv.data[i].state != RowStateInsert {
+			n++
+		}
 	}
-	for pos < endPos {
-		tv.viewport.SetContent(pos, y, ' ', nil, style)
-		pos++
+	if wraps {
+		return n
 	}
+	return min(n, max(tv.rowsHeight, 0))
+}
 
-	// Draw vim mode text right-aligned
-	if tv.vimMode {
-		for _, ch := range vimModeText {
-			tv.viewport.SetContent(pos, y, ch, nil, style)
-			pos++
+// visibleFirstRow returns the position of the first row on screen, or -1 if unknown
+func (tv *TableView) visibleFirstRow() int64 {
+	if tv.firstRow < 0 {
+		return -1
+	}
+	return tv.firstRow + int64(tv.rowOffset)
+}
+
+// groupDigits formats n with thousands separators
+func groupDigits(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatRowPosition describes which rows are visible, e.g. "1,201–1,240 of 48,213".
+// Estimates are marked with ~. Returns "" when the count is unknown.
+func formatRowPosition(firstRow, visible, count int64, exact bool) string {
+	if count < 0 {
+		return ""
+	}
+	approx := ""
+	if !exact {
+		approx = "~"
+	}
+	if firstRow < 0 || visible == 0 {
+		if count == 1 && exact {
+			return "1 row"
+		}
+		return approx + groupDigits(count) + " rows"
+	}
+	last := firstRow + visible
+	if exact {
+		last = min(last, count)
+	}
+	return fmt.Sprintf("%s%s–%s of %s%s", approx, groupDigits(firstRow+1), groupDigits(last), approx, groupDigits(count))
+}
+
+// scrollThumb places the scrollbar thumb on a track of length cells for visible rows
+// starting after firstRow of count rows
+func scrollThumb(firstRow, visible, count int64, length int) (start, size int) {
+	if count <= 0 || length <= 0 {
+		return 0, length
+	}
+	size = max(1, min(length, int(float64(visible)/float64(count)*float64(length)+0.5)))
+	start = int(float64(firstRow) / float64(count) * float64(length))
+	if firstRow+visible >= count {
+		start = length - size // keep the end visible at the bottom
+	}
+	return max(0, min(start, length-size)), size
+}
+
+// drawScrollbar draws the scrollbar down the right edge of the data rows
+func (tv *TableView) drawScrollbar(screen tcell.Screen, x, y, length int) {
+	tv.scrollbarLen = 0
+	visible := int64(tv.visibleRows())
+	if tv.rowCount <= visible || tv.firstRow < 0 || length <= 0 {
+		return
+	}
+	tv.scrollbarX, tv.scrollbarY, tv.scrollbarLen = x, y, length
+	start, size := scrollThumb(tv.visibleFirstRow(), visible, tv.rowCount, length)
+	trackStyle := theme.Muted
+	thumbStyle := theme.Thumb
+	for i := 0; i < length; i++ {
+		if i >= start && i < start+size {
+			screen.SetContent(x, y+i, '┃', nil, thumbStyle)
+		} else {
+			screen.SetContent(x, y+i, '│', nil, trackStyle)
 		}
 	}
 }
 
+// scrollbarDragInterval is the least time between the rows reloads of a scrollbar drag
+const scrollbarDragInterval = 50 * time.Millisecond
+
+// dragScrollbar reports the scrollbar dragged to screenY when it moved, at most every
+// scrollbarDragInterval so each reload isn't followed by another at once. The release
+// is always reported.
+func (tv *TableView) dragScrollbar(screenY int, release bool) {
+	fraction := tv.scrollbarFraction(screenY)
+	if fraction == tv.scrollbarSent || !release && time.Since(tv.scrollbarSentAt) < scrollbarDragInterval {
+		return
+	}
+	tv.scrollbarSent, tv.scrollbarSentAt = fraction, time.Now()
+	tv.scrollbarFunc(fraction)
+}
+
+// scrollbarFraction returns how far down the scrollbar screenY is, from 0 to 1
+func (tv *TableView) scrollbarFraction(screenY int) float64 {
+	if tv.scrollbarLen <= 1 {
+		return 0
+	}
+	return max(0, min(1, float64(screenY-tv.scrollbarY)/float64(tv.scrollbarLen-1)))
+}
+
 func (tv *TableView) drawTopBorder(x, y, tableWidth int) {
 	// Left corner
-	tv.viewport.SetContent(x, y, '┌', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+	tv.viewport.SetContent(x, y, '┌', nil, theme.Border)
 	pos := x + 1
 
 	// Column sections
@@ -499,7 +1025,7 @@ func (tv *TableView) drawTopBorder(x, y, tableWidth int) {
 
 		// Horizontal line for this column
 		for j := 0; j < cellWidth; j++ {
-			tv.viewport.SetContent(pos+j, y, '─', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos+j, y, '─', nil, theme.Border)
 		}
 		pos += cellWidth
 
@@ -507,10 +1033,10 @@ func (tv *TableView) drawTopBorder(x, y, tableWidth int) {
 		if i < len(tv.headers)-1 {
 			// Use special junction after last key column
 			junction := '┬'
-			tv.viewport.SetContent(pos, y, junction, nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, junction, nil, theme.Border)
 			pos++
 		} else {
-			tv.viewport.SetContent(pos, y, '┐', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '┐', nil, theme.Border)
 		}
 	}
 }
@@ -518,55 +1044,80 @@ func (tv *TableView) drawTopBorder(x, y, tableWidth int) {
 // drawHeaderRow draws the header content row
 func (tv *TableView) drawHeaderRow(x, y int) {
 	// Left border
-	tv.viewport.SetContent(x, y, '│', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+	tv.viewport.SetContent(x, y, '│', nil, theme.Border)
 	pos := x + 1
 
 	// Header cells
 	for i, header := range tv.headers {
-		// Determine background color based on editability
-		bgColor := tv.headerBgColor
+		// Determine the style based on editability
+		headerStyle := theme.Header
 		if !header.Editable {
-			bgColor = tv.readOnlyBgColor
+			headerStyle = theme.ReadOnlyHeader
 		}
 
 		// Padding before content
 		for j := 0; j < tv.cellPadding; j++ {
 			if header.IsKey {
-				tv.viewport.SetContent(pos+j, y, '✦', nil, tcell.StyleDefault.Foreground(tv.headerColor).Background(bgColor))
+				tv.viewport.SetContent(pos+j, y, '✦', nil, headerStyle)
 			} else {
-				tv.viewport.SetContent(pos+j, y, ' ', nil, tcell.StyleDefault.Foreground(tv.headerColor).Background(bgColor))
+				tv.viewport.SetContent(pos+j, y, ' ', nil, headerStyle)
 			}
 		}
 		pos += tv.cellPadding
 
-		// Header text
-		headerText := padCellToWidth(header.Name, header.Width)
-		for j, ch := range headerText {
-			tv.viewport.SetContent(pos+j, y, ch, nil, tcell.StyleDefault.Bold(true).Foreground(tv.headerColor).Background(bgColor))
+		// Header text, with the sort marker right-aligned
+		marker := tv.sortMarker(header.Name)
+		markerWidth := len([]rune(marker))
+		if markerWidth+1 >= header.Width {
+			marker, markerWidth = "", 0
+		}
+		headerText := padCellToWidth(header.Name, header.Width-markerWidth)
+		tv.printText(pos, y, headerText, headerStyle.Bold(true))
+		for j, ch := range []rune(marker) {
+			tv.viewport.SetContent(pos+header.Width-markerWidth+j, y, ch, nil, over(headerStyle.Bold(true), theme.SortMarker))
 		}
 		pos += header.Width
 
 		// Padding after content
 		for j := 0; j < tv.cellPadding; j++ {
-			tv.viewport.SetContent(pos+j, y, ' ', nil, tcell.StyleDefault.Foreground(tv.headerColor).Background(bgColor))
+			tv.viewport.SetContent(pos+j, y, ' ', nil, headerStyle)
 		}
 		pos += tv.cellPadding
 
 		// Column separator
 		if i < len(tv.headers)-1 {
-			tv.viewport.SetContent(pos, y, '│', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '│', nil, theme.Border)
 			pos++
 		}
 	}
 
 	// Right border
-	tv.viewport.SetContent(pos, y, '│', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+	tv.viewport.SetContent(pos, y, '│', nil, theme.Border)
+}
+
+// sortMarker returns the ▲/▼ shown after a sorted column's name, numbered by
+// priority when several columns are sorted
+func (tv *TableView) sortMarker(name string) string {
+	for i, sc := range tv.sort {
+		if sc.Name != name {
+			continue
+		}
+		arrow := "▼"
+		if sc.Asc {
+			arrow = "▲"
+		}
+		if len(tv.sort) > 1 {
+			return fmt.Sprintf(" %s%d", arrow, i+1)
+		}
+		return " " + arrow
+	}
+	return ""
 }
 
 // drawHeaderSeparator draws the heavy line separator between header and data
 func (tv *TableView) drawHeaderSeparator(x, y, tableWidth int) {
 	// Left junction
-	tv.viewport.SetContent(x, y, '┝', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+	tv.viewport.SetContent(x, y, '┝', nil, theme.Border)
 	pos := x + 1
 
 	// Column sections
@@ -575,22 +1126,23 @@ func (tv *TableView) drawHeaderSeparator(x, y, tableWidth int) {
 
 		// Heavy horizontal line for this column
 		for j := 0; j < cellWidth; j++ {
-			tv.viewport.SetContent(pos+j, y, '━', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos+j, y, '━', nil, theme.Border)
 		}
 		pos += cellWidth
 
 		// Junction or right junction
 		if i < len(tv.headers)-1 {
-			tv.viewport.SetContent(pos, y, '┿', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '┿', nil, theme.Border)
 			pos++
 		} else {
-			tv.viewport.SetContent(pos, y, '┥', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '┥', nil, theme.Border)
 		}
 	}
 }
 
-// drawDataRow draws a data row
-func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
+// drawDataRow draws a line of a data row, the first being 0. Wrapped cells continue
+// their text on later lines and other cells are blank.
+func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx, line int) {
 	// Get the row state for background color
 	var rowState RowState
 	if rowIdx < len(tv.data) {
@@ -601,47 +1153,50 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 	isNewRecordRow := rowState == RowStateInsert
 
 	// Left border
-	borderStyle := tcell.StyleDefault.Foreground(tv.borderColor)
+	borderStyle := theme.Border
 	isSelectedRowInDeleteMode := tv.selectable && tv.deleteMode && rowIdx == tv.selectedRow
 	if isNewRecordRow {
-		borderStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+		borderStyle = theme.InsertBorder
 	} else if isSelectedRowInDeleteMode {
-		borderStyle = tcell.StyleDefault.Background(tcell.ColorRed).Foreground(tcell.ColorWhite)
+		borderStyle = theme.Delete
 	} else if rowState == RowStateNew {
-		borderStyle = borderStyle.Background(tcell.ColorDarkGreen)
+		borderStyle = over(borderStyle, theme.New)
 	} else if rowState == RowStateDeleted {
-		borderStyle = borderStyle.Background(tcell.ColorDarkRed)
+		borderStyle = over(borderStyle, theme.Deleted)
 	}
 	tv.viewport.SetContent(x, y, '│', nil, borderStyle)
 	pos := x + 1
 
-	// Data cells
+	// Data cells, each header naming where its value is in the row
 	for i, header := range tv.headers {
+		var value any
+		if rowIdx < len(tv.data) && header.Index < len(tv.data[rowIdx].data) {
+			value = tv.data[rowIdx].data[header.Index]
+		}
+
 		// Check if this specific cell is modified
 		isCellModified := false
 		if rowIdx < len(tv.data) && tv.data[rowIdx].modified != nil {
 			for _, modIdx := range tv.data[rowIdx].modified {
-				if modIdx == i {
+				if modIdx == header.Index {
 					isCellModified = true
 					break
 				}
 			}
 		}
 
-		// Base style - use cyan background for insert mode row
+		// Base style from the row's state, or the cell's
 		baseCellStyle := tcell.StyleDefault
 		if isNewRecordRow {
-			baseCellStyle = baseCellStyle.Background(tcell.ColorRoyalBlue)
+			baseCellStyle = over(baseCellStyle, theme.InsertRow)
 		} else if rowState == RowStateNew {
-			baseCellStyle = baseCellStyle.Background(tcell.ColorDarkGreen)
+			baseCellStyle = over(baseCellStyle, theme.New)
 		} else if rowState == RowStateDeleted {
-			baseCellStyle = baseCellStyle.Background(tcell.ColorDarkRed)
+			baseCellStyle = over(baseCellStyle, theme.Deleted)
 		} else if isCellModified {
-			// Modified cell - use dark green background
-			baseCellStyle = baseCellStyle.Background(tcell.ColorDarkGreen)
+			baseCellStyle = over(baseCellStyle, theme.Modified)
 		} else if !header.Editable {
-			// Non-editable column - use grey background
-			baseCellStyle = baseCellStyle.Foreground(tv.readOnlyBgColor)
+			baseCellStyle = over(baseCellStyle, theme.ReadOnly)
 		}
 
 		// Apply selection highlight on top of base style
@@ -649,16 +1204,16 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 
 		// In Find mode, highlight the entire selected column
 		if tv.findMode && i == tv.selectedCol {
-			cellStyle = cellStyle.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+			cellStyle = over(cellStyle, theme.FindColumn)
 		}
 
 		if tv.selectable && rowIdx == tv.selectedRow {
 			if tv.deleteMode {
 				// In delete mode, make the entire selected row red
-				cellStyle = cellStyle.Background(tcell.ColorRed).Foreground(tcell.ColorWhite)
+				cellStyle = over(cellStyle, theme.Delete)
 			} else if i == tv.selectedCol {
 				// In normal mode, only highlight the selected cell
-				cellStyle = cellStyle.Background(tcell.ColorBlue).Foreground(tcell.ColorWhite)
+				cellStyle = over(cellStyle, theme.Selected)
 			}
 		}
 
@@ -673,27 +1228,31 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 			// For insert mode row, render cell value with special styling
 			// dblib.EmptyCellValue means empty (column not included in INSERT) - show as ·
 			// nil means null
-			if tv.data[rowIdx].data[i] == dblib.EmptyCellValue {
+			if value == dblib.EmptyCellValue && header.Default != "" {
+				// Unset cell with a column default - show what the database will fill in
+				placeholder := padCellToWidth(header.Default, header.Width)
+				placeholderStyle := over(cellStyle.Italic(true), theme.Muted)
+				tv.printText(pos, y, placeholder, placeholderStyle)
+			} else if value == dblib.EmptyCellValue {
 				// Empty cell in insert mode - show repeating dots
 				for k := 0; k < header.Width; k++ {
 					tv.viewport.SetContent(pos+k, y, '·', nil, cellStyle)
 				}
 			} else {
-				cellText, cellStyle := formatCellValue(tv.data[rowIdx].data[i], cellStyle)
-				cellText = padCellToWidth(cellText, header.Width)
-				for j, ch := range cellText {
-					tv.viewport.SetContent(pos+j, y, ch, nil, cellStyle)
-				}
+				cellText, cellStyle := formatCellValue(value, cellStyle)
+				tv.printText(pos, y, padCellToWidth(cellText, header.Width), cellStyle)
 			}
 
 		} else {
 			// Normal rendering
-			if rowIdx < len(tv.data) && i < len(tv.data[rowIdx].data) {
-				cellText, cellStyle := formatCellValue(tv.data[rowIdx].data[i], cellStyle)
-				cellText = padCellToWidth(cellText, header.Width)
-				for j, ch := range cellText {
-					tv.viewport.SetContent(pos+j, y, ch, nil, cellStyle)
+			if rowIdx < len(tv.data) && header.Index < len(tv.data[rowIdx].data) {
+				cellText, cellStyle := formatCellValue(value, cellStyle)
+				lines := tv.cellLines(header, cellText)
+				cellText = ""
+				if line < len(lines) {
+					cellText = lines[line]
 				}
+				tv.printText(pos, y, padCellToWidth(cellText, header.Width), cellStyle)
 			}
 		}
 		pos += header.Width
@@ -706,15 +1265,15 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 
 		// Column separator
 		if i < len(tv.headers)-1 {
-			sepStyle := tcell.StyleDefault.Foreground(tv.borderColor)
+			sepStyle := theme.Border
 			if isNewRecordRow {
 				sepStyle = baseCellStyle
 			} else if isSelectedRowInDeleteMode {
 				sepStyle = cellStyle
 			} else if rowState == RowStateNew {
-				sepStyle = sepStyle.Background(tcell.ColorDarkGreen)
+				sepStyle = over(sepStyle, theme.New)
 			} else if rowState == RowStateDeleted {
-				sepStyle = sepStyle.Background(tcell.ColorDarkRed)
+				sepStyle = over(sepStyle, theme.Deleted)
 			}
 			tv.viewport.SetContent(pos, y, '│', nil, sepStyle)
 			pos++
@@ -722,15 +1281,15 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 	}
 
 	// Right border
-	rightBorderStyle := tcell.StyleDefault.Foreground(tv.borderColor)
+	rightBorderStyle := theme.Border
 	if isNewRecordRow {
-		rightBorderStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+		rightBorderStyle = theme.InsertBorder
 	} else if isSelectedRowInDeleteMode {
-		rightBorderStyle = tcell.StyleDefault.Background(tcell.ColorRed).Foreground(tcell.ColorWhite)
+		rightBorderStyle = theme.Delete
 	} else if rowState == RowStateNew {
-		rightBorderStyle = rightBorderStyle.Background(tcell.ColorDarkGreen)
+		rightBorderStyle = over(rightBorderStyle, theme.New)
 	} else if rowState == RowStateDeleted {
-		rightBorderStyle = rightBorderStyle.Background(tcell.ColorDarkRed)
+		rightBorderStyle = over(rightBorderStyle, theme.Deleted)
 	}
 	tv.viewport.SetContent(pos, y, '│', nil, rightBorderStyle)
 }
@@ -738,7 +1297,7 @@ func (tv *TableView) drawDataRow(x, y, tableWidth, rowIdx int) {
 // drawBottomBorder draws the bottom border of the table
 func (tv *TableView) drawBottomBorder(x, y, tableWidth int) {
 	// Left corner
-	tv.viewport.SetContent(x, y, '└', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+	tv.viewport.SetContent(x, y, '└', nil, theme.Border)
 	pos := x + 1
 
 	// Column sections
@@ -747,16 +1306,16 @@ func (tv *TableView) drawBottomBorder(x, y, tableWidth int) {
 
 		// Horizontal line for this column
 		for j := 0; j < cellWidth; j++ {
-			tv.viewport.SetContent(pos+j, y, '─', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos+j, y, '─', nil, theme.Border)
 		}
 		pos += cellWidth
 
 		// Junction or corner
 		if i < len(tv.headers)-1 {
-			tv.viewport.SetContent(pos, y, '┴', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '┴', nil, theme.Border)
 			pos++
 		} else {
-			tv.viewport.SetContent(pos, y, '┘', nil, tcell.StyleDefault.Foreground(tv.borderColor))
+			tv.viewport.SetContent(pos, y, '┘', nil, theme.Border)
 		}
 	}
 }
@@ -793,6 +1352,19 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 		// Get mouse position
 		x, y := event.Position()
 
+		// Scrollbar drags continue outside our bounds
+		if tv.draggingScrollbar {
+			switch action {
+			case tview.MouseMove:
+				tv.dragScrollbar(y, false)
+				return true, tv // Continue capturing
+			case tview.MouseLeftUp:
+				tv.draggingScrollbar = false
+				tv.dragScrollbar(y, true)
+				return true, nil // Release capture
+			}
+		}
+
 		// Check if click is within our bounds
 		if !tv.InRect(x, y) {
 			return false, nil
@@ -804,6 +1376,15 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 			setFocus(tv)
 			consumed = true
 
+			// Grab the scrollbar to jump through the rows
+			if tv.scrollbarFunc != nil && tv.scrollbarLen > 0 && x == tv.scrollbarX &&
+				y >= tv.scrollbarY && y < tv.scrollbarY+tv.scrollbarLen {
+				tv.draggingScrollbar = true
+				tv.scrollbarSent, tv.scrollbarSentAt = tv.scrollbarFraction(y), time.Now()
+				tv.scrollbarFunc(tv.scrollbarSent)
+				return true, tv // Capture further mouse events
+			}
+
 			// Check if clicked on column separator for drag resize
 			separatorCol := tv.GetColumnSeparatorAtPosition(x, y)
 			if separatorCol >= 0 {
@@ -834,6 +1415,9 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 		case tview.MouseLeftUp:
 			// End drag resize
 			if tv.resizingColumn >= 0 {
+				if tv.columnResizeFunc != nil {
+					tv.columnResizeFunc(tv.resizingColumn)
+				}
 				tv.resizingColumn = -1
 				return true, nil // Release capture
 			}
@@ -842,6 +1426,22 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 			_, innerY, _, _ := tv.GetInnerRect()
 			relativeY := y - innerY
 
+			// Quick filter chips share the table name row
+			if relativeY == 0 && tv.tableName != "" && tv.chipClickFunc != nil {
+				if chip := tv.GetChipAtPosition(x); chip >= 0 {
+					tv.chipClickFunc(chip)
+					return true, nil
+				}
+			}
+
+			// Clicking another tab switches to it
+			if relativeY == 0 && tv.tableName != "" && tv.tabClickFunc != nil {
+				if tab := tv.GetTabAtPosition(x); tab >= 0 && tab != tv.activeTab {
+					tv.tabClickFunc(tab)
+					return true, nil
+				}
+			}
+
 			// Table name is at relativeY == 0 (if tableName is set)
 			if relativeY == 0 && tv.tableName != "" && tv.tableNameClickFunc != nil {
 				tv.tableNameClickFunc()
@@ -849,6 +1449,11 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 				return consumed, nil
 			}
 
+			if col := tv.GetHeaderColumnAtPosition(x, y); col >= 0 && tv.headerClickFunc != nil {
+				tv.headerClickFunc(col, event.Modifiers()&(tcell.ModShift|tcell.ModCtrl|tcell.ModAlt) != 0)
+				return true, nil
+			}
+
 			row, col := tv.GetCellAtPosition(x, y)
 			if tv.singleClickFunc != nil && row >= 0 && col >= 0 {
 				tv.singleClickFunc(row, col)
@@ -858,6 +1463,15 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 			consumed = true
 			return consumed, nil
 		case tview.MouseLeftDoubleClick:
+			// Double-clicking a column separator fits the column to its content
+			if col := tv.GetColumnSeparatorAtPosition(x, y); col >= 0 {
+				tv.FitColumn(col)
+				if tv.columnResizeFunc != nil {
+					tv.columnResizeFunc(col)
+				}
+				return true, nil
+			}
+
 			// Handle double-click on a cell - only if both clicks are on the same cell
 			if tv.doubleClickFunc != nil {
 				row, col := tv.GetCellAtPosition(x, y)
@@ -885,10 +1499,7 @@ func (tv *TableView) MouseHandler() func(action tview.MouseAction, event *tcell.
 
 // UpdateCell updates a cell value and refreshes the display
 func (tv *TableView) UpdateCell(row, col int, value any) *TableView {
-	if row >= 0 && row < len(tv.data) && col >= 0 && col < len(tv.data[row].data) {
-		tv.data[row].data[col] = value
-	}
-	return tv
+	return tv.SetCell(row, col, value)
 }
 
 // GetColumnWidth returns the width of a column
@@ -899,6 +1510,23 @@ func (tv *TableView) GetColumnWidth(col int) int {
 	return 0
 }
 
+// FitColumn sets a column's width to the display width of its header and the values
+// in the rows loaded, up to MaxFitColumnWidth
+func (tv *TableView) FitColumn(col int) *TableView {
+	if col < 0 || col >= len(tv.headers) {
+		return tv
+	}
+	header := tv.headers[col]
+	width := runewidth.StringWidth(header.Name)
+	for _, row := range tv.data {
+		if header.Index < len(row.data) {
+			text, _ := formatCellValue(row.data[header.Index], tcell.StyleDefault)
+			width = max(width, runewidth.StringWidth(text))
+		}
+	}
+	return tv.SetColumnWidth(col, min(width, MaxFitColumnWidth))
+}
+
 // SetColumnWidth updates a column width
 func (tv *TableView) SetColumnWidth(col int, width int) *TableView {
 	if col >= 0 && col < len(tv.headers) {
@@ -968,14 +1596,14 @@ func (tv *TableView) GetCellAtPosition(screenX, screenY int) (row, col int) {
 		return -1, -1 // Clicked on border/header, not a data cell
 	}
 
-	dataRow := relativeY - headerOffset
+	dataRow := tv.rowAtLine(relativeY - headerOffset)
 	if dataRow < 0 || dataRow >= len(tv.data) || tv.data[dataRow].data == nil {
 		return -1, -1 // Beyond available data
 	}
 
 	// Calculate which column was clicked
 	// Account for viewport scrolling - adjust screen coordinate to table coordinate
-	relativeX := screenX - x + tv.viewport.GetScrollX()
+	relativeX := tv.viewport.TableX(screenX)
 	if relativeX < 1 {
 		return -1, -1 // Clicked on left border
 	}
@@ -1004,6 +1632,58 @@ func (tv *TableView) GetCellAtPosition(screenX, screenY int) (row, col int) {
 	return -1, -1 // Clicked beyond table content
 }
 
+// GetChipAtPosition returns the quick filter chip drawn at screen column screenX, or -1
+func (tv *TableView) GetChipAtPosition(screenX int) int {
+	x, _, _, _ := tv.GetInnerRect()
+	relativeX := screenX - x + tv.viewport.GetScrollX() // the title row isn't pinned
+	for i, span := range tv.chipSpans {
+		if relativeX >= span[0] && relativeX < span[1] {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetTabAtPosition returns the tab drawn at screen column screenX, or -1
+func (tv *TableView) GetTabAtPosition(screenX int) int {
+	if len(tv.tabs) < 2 {
+		return -1
+	}
+	x, _, _, _ := tv.GetInnerRect()
+	relativeX := screenX - x + tv.viewport.GetScrollX() // the title row isn't pinned
+	for i, span := range tv.tabSpans {
+		if relativeX >= span[0] && relativeX < span[1] {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetHeaderColumnAtPosition returns the column whose header name is at the screen
+// position, or -1 if the position is not on a header cell
+func (tv *TableView) GetHeaderColumnAtPosition(screenX, screenY int) int {
+	x, y, width, _ := tv.GetInnerRect()
+	if screenX < x || screenX >= x+width {
+		return -1
+	}
+	headerRow := 1
+	if tv.tableName != "" {
+		headerRow = 2
+	}
+	if screenY-y != headerRow {
+		return -1
+	}
+
+	relativeX := tv.viewport.TableX(screenX)
+	for i := range tv.headers {
+		startX, endX := tv.GetColumnPosition(i)
+		if relativeX >= startX && relativeX < endX {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetColumnSeparatorAtPosition returns the column index if the position is on a column separator,
 // or -1 if not on a separator. Uses tolerance of ±1 for easier clicking.
 func (tv *TableView) GetColumnSeparatorAtPosition(screenX, screenY int) int {
@@ -1029,7 +1709,7 @@ func (tv *TableView) GetColumnSeparatorAtPosition(screenX, screenY int) int {
 	}
 
 	// Account for viewport scrolling - adjust screen coordinate to table coordinate
-	relativeX := screenX - x + tv.viewport.GetScrollX()
+	relativeX := tv.viewport.TableX(screenX)
 	if relativeX < 1 {
 		return -1 // Before left border
 	}
@@ -1052,18 +1732,26 @@ func (tv *TableView) GetColumnSeparatorAtPosition(screenX, screenY int) int {
 	return -1 // Not on any separator
 }
 
-// padCellToWidth pads text to a specific width, truncating if too long
+// padCellToWidth pads text with spaces to a display width, truncating it if too wide
 func padCellToWidth(text string, width int) string {
-	if len(text) >= width {
+	if runewidth.StringWidth(text) > width {
+		tail := ""
 		if width >= 3 {
-			return text[:width-1] + "…"
+			tail = "…"
 		}
-		return text[:width]
+		text = runewidth.Truncate(text, width, tail)
 	}
-	// Pad with spaces to reach desired width
-	spaces := ""
-	for i := 0; i < width-len(text); i++ {
-		spaces += " "
+	return runewidth.FillRight(text, width)
+}
+
+// printText draws text from x, advancing by each rune's display width
+func (tv *TableView) printText(x, y int, text string, style tcell.Style) {
+	for _, ch := range text {
+		width := runewidth.RuneWidth(ch)
+		if width == 0 {
+			continue
+		}
+		tv.viewport.SetContent(x, y, ch, nil, style)
+		x += width
 	}
-	return text + spaces
 }
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"ted/internal/dblib"
+)
+
+func TestPinnedColumns(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Failed to init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(20, 10)
+
+	tv := NewTableView(5, &TableViewConfig{Headers: []dblib.DisplayColumn{
+		{Name: "id", Width: 4, Index: 0},
+		{Name: "name", Width: 10, Index: 1},
+		{Name: "email", Width: 10, Index: 2},
+		{Name: "note", Width: 10, Index: 3},
+	}})
+	tv.SetRect(0, 0, 20, 10)
+	tv.SetDataReferences([]Row{{data: []any{int64(7), "Alice", "a@example.com", "hi"}}, BottomBorderRow})
+	tv.SetPinned(1)
+	tv.viewport.SetScrollX(10)
+	tv.Draw(screen)
+
+	// The id column stays at the left edge, the rest scrolls under it
+	if ch, _, _, _ := screen.GetContent(2, 3); ch != '7' {
+		t.Errorf("Expected the pinned id at x=2, got %q", ch)
+	}
+	if ch, _, _, _ := screen.GetContent(7, 3); ch != '│' {
+		t.Errorf("Expected the separator after the pinned column at x=7, got %q", ch)
+	}
+	if _, col := tv.GetCellAtPosition(2, 3); col != 0 {
+		t.Errorf("Expected a click at x=2 to hit the pinned column, got %d", col)
+	}
+	if _, col := tv.GetCellAtPosition(12, 3); col != 2 {
+		t.Errorf("Expected a click at x=12 to hit the scrolled email column, got %d", col)
+	}
+	if sep := tv.GetColumnSeparatorAtPosition(7, 3); sep != 0 {
+		t.Errorf("Expected the pinned column's separator at x=7, got %d", sep)
+	}
+
+	// Pinned columns never need scrolling; the first scrolled one shows right after them
+	tv.viewport.EnsureColumnVisible(0, 8, 20)
+	if got := tv.viewport.GetScrollX(); got != 10 {
+		t.Errorf("Expected scrollX to stay 10 for a pinned column, got %d", got)
+	}
+	tv.viewport.EnsureColumnVisible(7, 21, 20)
+	if got := tv.viewport.GetScrollX(); got != 0 {
+		t.Errorf("Expected scrollX 0 to show the name column, got %d", got)
+	}
+}
+
+func TestFitColumn(t *testing.T) {
+	tv := NewTableView(5, &TableViewConfig{Headers: []dblib.DisplayColumn{
+		{Name: "id", Width: DefaultColumnWidth, Index: 0},
+		{Name: "city", Width: DefaultColumnWidth, Index: 1},
+		{Name: "notes", Width: DefaultColumnWidth, Index: 2},
+	}})
+	tv.SetDataReferences([]Row{
+		{data: []any{int64(1), "東京都", strings.Repeat("x", 100)}},
+		{data: []any{int64(22), nil, "short"}},
+		BottomBorderRow,
+	})
+	for col := range tv.GetHeaders() {
+		tv.FitColumn(col)
+	}
+
+	// Wide runes take two cells, short columns keep the minimum, long values are capped
+	want := []int{3, 6, MaxFitColumnWidth}
+	for col, width := range want {
+		if got := tv.GetColumnWidth(col); got != width {
+			t.Errorf("Column %d: expected width %d, got %d", col, width, got)
+		}
+	}
+}
+
+func TestPadCellToWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"Alice", 8, "Alice   "},
+		{"Alice", 5, "Alice"},
+		{"Alice", 4, "Ali…"},
+		{"東京都", 6, "東京都"},
+		{"東京都", 5, "東京…"},
+		{"東京", 5, "東京 "},
+	}
+	for _, tt := range tests {
+		if got := padCellToWidth(tt.text, tt.width); got != tt.want {
+			t.Errorf("padCellToWidth(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWrappedRows(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Failed to init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(20, 12)
+
+	tv := NewTableView(5, &TableViewConfig{Headers: []dblib.DisplayColumn{
+		{Name: "id", Width: 4, Index: 0},
+		{Name: "note", Width: 5, Index: 1, Wrap: true},
+	}})
+	tv.SetRect(0, 0, 20, 12)
+	tv.SetDataReferences([]Row{
+		{data: []any{int64(1), "one two three"}},
+		{data: []any{int64(2), "hi"}},
+		{data: []any{int64(3), "a\nb\nc\nd\ne\nf\ng\nh"}},
+		BottomBorderRow,
+	})
+	tv.Draw(screen)
+
+	// The first row takes three lines, the second starts after them
+	if ch, _, _, _ := screen.GetContent(9, 5); ch != 'r' {
+		t.Errorf("Expected the third line of the wrapped note at y=5, got %q", ch)
+	}
+	if ch, _, _, _ := screen.GetContent(2, 6); ch != '2' {
+		t.Errorf("Expected the second row at y=6, got %q", ch)
+	}
+	if row, _ := tv.GetCellAtPosition(2, 5); row != 0 {
+		t.Errorf("Expected a click on the first row's last line to hit row 0, got %d", row)
+	}
+	if row, _ := tv.GetCellAtPosition(9, 6); row != 1 {
+		t.Errorf("Expected a click at y=6 to hit row 1, got %d", row)
+	}
+	if line := tv.RowLine(2); line != 4 {
+		t.Errorf("Expected the third row to start on data line 4, got %d", line)
+	}
+
+	// Rows are at most MaxRowLines tall, marking the cut text
+	if h := tv.rowHeight(2); h != MaxRowLines {
+		t.Errorf("Expected the eight line note cut to %d lines, got %d", MaxRowLines, h)
+	}
+	if lines := tv.cellLines(tv.GetHeaders()[1], "a\nb\nc\nd\ne\nf\ng\nh"); lines[MaxRowLines-1] != "f…" {
+		t.Errorf("Expected the last line to end in …, got %q", lines[MaxRowLines-1])
+	}
+
+	// Selecting a row below the screen starts drawing lower down
+	tv.Select(2, 0)
+	tv.Draw(screen)
+	if ch, _, _, _ := screen.GetContent(2, 3); ch != '2' {
+		t.Errorf("Expected drawing to start at the second row, got %q", ch)
+	}
+	if row, _ := tv.GetCellAtPosition(2, 4); row != 2 {
+		t.Errorf("Expected the selected row on the second line, got %d", row)
+	}
+
+	// Without wrapping every row is a line
+	tv.SetColumnWrap(1, false)
+	tv.Select(0, 0)
+	tv.Draw(screen)
+	if ch, _, _, _ := screen.GetContent(2, 4); ch != '2' {
+		t.Errorf("Expected the second row at y=4 without wrapping, got %q", ch)
+	}
+}
+
+func TestDragScrollbar(t *testing.T) {
+	var sent []float64
+	tv := NewTableView(5, &TableViewConfig{ScrollbarFunc: func(fraction float64) { sent = append(sent, fraction) }})
+	tv.scrollbarY, tv.scrollbarLen = 0, 11
+	tv.scrollbarSentAt = time.Now()
+
+	// Moves straight after a reload wait, and the release reports where the drag ended
+	tv.dragScrollbar(5, false)
+	tv.dragScrollbar(6, false)
+	if len(sent) != 0 {
+		t.Errorf("Expected moves within the interval dropped, got %v", sent)
+	}
+	tv.dragScrollbar(6, true)
+	if len(sent) != 1 || sent[0] != 0.6 {
+		t.Errorf("Expected the release at 0.6 reported, got %v", sent)
+	}
+	tv.scrollbarSentAt = time.Now().Add(-scrollbarDragInterval)
+	tv.dragScrollbar(6, false)
+	tv.dragScrollbar(10, false)
+	if len(sent) != 2 || sent[1] != 1 {
+		t.Errorf("Expected only the move to the end reported, got %v", sent)
+	}
+}
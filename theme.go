@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/terminfo"
+	"github.com/rivo/tview"
+)
+
+// Theme holds the styles the editor draws with. Row and cell states are drawn over
+// a cell's style, changing only the colors and attributes they set.
+type Theme struct {
+	Border         tcell.Style // table borders and column separators
+	Header         tcell.Style // names of editable columns
+	ReadOnlyHeader tcell.Style // names of columns that can't be edited
+	ReadOnly       tcell.Style // values of columns that can't be edited
+	SortMarker     tcell.Style // ▲/▼ of sorted columns
+	Title          tcell.Style // table name
+	Muted          tcell.Style // NULL, blob summaries, column defaults, other tabs, unfocused panes
+	Filter         tcell.Style // WHERE predicate after the table name
+	Chip           tcell.Style // quick filter chips
+	Thumb          tcell.Style // scrollbar thumb, on a Muted track
+	Selected       tcell.Style // selected cell
+	FindColumn     tcell.Style // column searched while finding
+	InsertRow      tcell.Style // row being inserted
+	InsertBorder   tcell.Style // borders of the row being inserted
+	New            tcell.Style // rows inserted since loading
+	Modified       tcell.Style // cells updated since loading
+	Deleted        tcell.Style // rows deleted in a dry run
+	Delete         tcell.Style // row about to be deleted, and the status bar meanwhile
+	StatusBar      tcell.Style
+	Palette        tcell.Style // command palette
+
+	// Text colors in the status bar, picker and detail view
+	Error   tcell.Color
+	Info    tcell.Color
+	Value   tcell.Color // cell values and referenced rows
+	Pending tcell.Color // edits still being typed or invalid
+	Missing tcell.Color // references to rows that don't exist
+	Match   tcell.Color // matched letters in the picker
+	Field   tcell.Color // column names in the detail view
+	Preview tcell.Color // referenced rows in the detail view
+
+	// Colors of tview's own widgets: lists, forms, modals
+	Widgets tview.Theme
+}
+
+// theme is the theme in use, set from the settings when the editor starts
+var theme = darkTheme()
+
+// themes are the built-in themes by name
+var themes = map[string]func() *Theme{
+	"dark":          darkTheme,
+	"light":         lightTheme,
+	"high-contrast": highContrastTheme,
+}
+
+func darkTheme() *Theme {
+	widgets := tview.Styles
+	widgets.ContrastBackgroundColor = tcell.ColorBlack
+	return &Theme{
+		Border:         tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		Header:         tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray),
+		ReadOnlyHeader: tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkGray),
+		ReadOnly:       tcell.StyleDefault.Foreground(tcell.ColorDarkGray),
+		SortMarker:     tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true),
+		Title:          tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		Muted:          tcell.StyleDefault.Foreground(tcell.ColorGray),
+		Filter:         tcell.StyleDefault.Foreground(tcell.ColorYellow),
+		Chip:           tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow),
+		Thumb:          tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		Selected:       tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		FindColumn:     tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkBlue),
+		InsertRow:      tcell.StyleDefault.Background(tcell.ColorRoyalBlue),
+		InsertBorder:   tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		New:            tcell.StyleDefault.Background(tcell.ColorDarkGreen),
+		Modified:       tcell.StyleDefault.Background(tcell.ColorDarkGreen),
+		Deleted:        tcell.StyleDefault.Background(tcell.ColorDarkRed),
+		Delete:         tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorRed),
+		StatusBar:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		Palette:        tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		Error:          tcell.ColorRed,
+		Info:           tcell.ColorBlue,
+		Value:          tcell.ColorDarkGreen,
+		Pending:        tcell.ColorYellow,
+		Missing:        tcell.ColorBlueViolet,
+		Match:          tcell.ColorDarkGreen,
+		Field:          tcell.ColorYellow,
+		Preview:        tcell.ColorTeal,
+		Widgets:        widgets,
+	}
+}
+
+// lightTheme suits terminals with a light background, drawing text in black over
+// pale highlights
+func lightTheme() *Theme {
+	return &Theme{
+		Border:         tcell.StyleDefault.Foreground(tcell.ColorGray),
+		Header:         tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightSteelBlue),
+		ReadOnlyHeader: tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		ReadOnly:       tcell.StyleDefault.Foreground(tcell.ColorGray),
+		SortMarker:     tcell.StyleDefault.Foreground(tcell.ColorMaroon).Bold(true),
+		Title:          tcell.StyleDefault.Foreground(tcell.ColorBlack),
+		Muted:          tcell.StyleDefault.Foreground(tcell.ColorGray),
+		Filter:         tcell.StyleDefault.Foreground(tcell.ColorDarkGoldenrod),
+		Chip:           tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorKhaki),
+		Thumb:          tcell.StyleDefault.Foreground(tcell.ColorBlack),
+		Selected:       tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		FindColumn:     tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightSkyBlue),
+		InsertRow:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorPaleTurquoise),
+		InsertBorder:   tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorPaleTurquoise),
+		New:            tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorPaleGreen),
+		Modified:       tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorPaleGreen),
+		Deleted:        tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorMistyRose),
+		Delete:         tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorRed),
+		StatusBar:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		Palette:        tcell.StyleDefault.Foreground(tcell.ColorBlack),
+		Error:          tcell.ColorRed,
+		Info:           tcell.ColorBlue,
+		Value:          tcell.ColorDarkGreen,
+		Pending:        tcell.ColorDarkOrange,
+		Missing:        tcell.ColorPurple,
+		Match:          tcell.ColorDarkGreen,
+		Field:          tcell.ColorNavy,
+		Preview:        tcell.ColorTeal,
+		Widgets: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorLightGray,
+			MoreContrastBackgroundColor: tcell.ColorLightSteelBlue,
+			BorderColor:                 tcell.ColorGray,
+			TitleColor:                  tcell.ColorBlack,
+			GraphicsColor:               tcell.ColorGray,
+			PrimaryTextColor:            tcell.ColorBlack,
+			SecondaryTextColor:          tcell.ColorNavy,
+			TertiaryTextColor:           tcell.ColorDarkGreen,
+			InverseTextColor:            tcell.ColorWhite,
+			ContrastSecondaryTextColor:  tcell.ColorNavy,
+		},
+	}
+}
+
+// highContrastTheme uses the 16 basic colors only, bright and bold
+func highContrastTheme() *Theme {
+	return &Theme{
+		Border:         tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		Header:         tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite),
+		ReadOnlyHeader: tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorSilver),
+		ReadOnly:       tcell.StyleDefault.Foreground(tcell.ColorSilver),
+		SortMarker:     tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true),
+		Title:          tcell.StyleDefault.Foreground(tcell.ColorWhite).Bold(true),
+		Muted:          tcell.StyleDefault.Foreground(tcell.ColorSilver),
+		Filter:         tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true),
+		Chip:           tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow),
+		Thumb:          tcell.StyleDefault.Foreground(tcell.ColorWhite),
+		Selected:       tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorAqua).Bold(true),
+		FindColumn:     tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		InsertRow:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorFuchsia),
+		InsertBorder:   tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorFuchsia),
+		New:            tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLime),
+		Modified:       tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLime).Bold(true),
+		Deleted:        tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorMaroon),
+		Delete:         tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorRed).Bold(true),
+		StatusBar:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite),
+		Palette:        tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		Error:          tcell.ColorRed,
+		Info:           tcell.ColorBlue,
+		Value:          tcell.ColorGreen,
+		Pending:        tcell.ColorPurple,
+		Missing:        tcell.ColorPurple,
+		Match:          tcell.ColorLime,
+		Field:          tcell.ColorYellow,
+		Preview:        tcell.ColorAqua,
+		Widgets: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorBlack,
+			ContrastBackgroundColor:     tcell.ColorBlack,
+			MoreContrastBackgroundColor: tcell.ColorBlue,
+			BorderColor:                 tcell.ColorWhite,
+			TitleColor:                  tcell.ColorWhite,
+			GraphicsColor:               tcell.ColorWhite,
+			PrimaryTextColor:            tcell.ColorWhite,
+			SecondaryTextColor:          tcell.ColorYellow,
+			TertiaryTextColor:           tcell.ColorLime,
+			InverseTextColor:            tcell.ColorBlack,
+			ContrastSecondaryTextColor:  tcell.ColorYellow,
+		},
+	}
+}
+
+// monochromeTheme tells states apart with attributes alone, for NO_COLOR and
+// terminals without colors
+func monochromeTheme() *Theme {
+	plain := tcell.StyleDefault
+	return &Theme{
+		Border:         plain,
+		Header:         plain.Reverse(true),
+		ReadOnlyHeader: plain.Reverse(true).Dim(true),
+		ReadOnly:       plain.Dim(true),
+		SortMarker:     plain.Bold(true),
+		Title:          plain.Bold(true),
+		Muted:          plain.Dim(true),
+		Filter:         plain.Italic(true),
+		Chip:           plain.Reverse(true),
+		Thumb:          plain.Bold(true),
+		Selected:       plain.Reverse(true),
+		FindColumn:     plain.Underline(true),
+		InsertRow:      plain.Underline(true),
+		InsertBorder:   plain,
+		New:            plain.Bold(true),
+		Modified:       plain.Bold(true).Italic(true),
+		Deleted:        plain.StrikeThrough(true).Dim(true),
+		Delete:         plain.Reverse(true).Bold(true),
+		StatusBar:      plain,
+		Palette:        plain,
+		Error:          tcell.ColorDefault,
+		Info:           tcell.ColorDefault,
+		Value:          tcell.ColorDefault,
+		Pending:        tcell.ColorDefault,
+		Missing:        tcell.ColorDefault,
+		Match:          tcell.ColorDefault,
+		Field:          tcell.ColorDefault,
+		Preview:        tcell.ColorDefault,
+		Widgets: tview.Theme{
+			PrimitiveBackgroundColor:    tcell.ColorDefault,
+			ContrastBackgroundColor:     tcell.ColorDefault,
+			MoreContrastBackgroundColor: tcell.ColorDefault,
+			BorderColor:                 tcell.ColorDefault,
+			TitleColor:                  tcell.ColorDefault,
+			GraphicsColor:               tcell.ColorDefault,
+			PrimaryTextColor:            tcell.ColorDefault,
+			SecondaryTextColor:          tcell.ColorDefault,
+			TertiaryTextColor:           tcell.ColorDefault,
+			InverseTextColor:            tcell.ColorDefault,
+			ContrastSecondaryTextColor:  tcell.ColorDefault,
+		},
+	}
+}
+
+// LoadTheme returns the theme named in settings, dark if none, with the colors the
+// settings override. NO_COLOR or a terminal without colors selects the monochrome
+// theme, and fewer than 256 colors the closest basic colors.
+func LoadTheme(settings *Settings, colors int) (*Theme, error) {
+	if os.Getenv("NO_COLOR") != "" || colors < 8 {
+		return monochromeTheme(), nil
+	}
+	name := "dark"
+	if settings != nil && settings.Theme != "" {
+		name = settings.Theme
+	}
+	newTheme, ok := themes[name]
+	if !ok {
+		names := make([]string, 0, len(themes))
+		for name := range themes {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	t := newTheme()
+	if settings != nil {
+		for element, spec := range settings.ThemeColors {
+			if err := t.set(element, spec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if colors < 256 {
+		t.fit(colors)
+	}
+	return t, nil
+}
+
+// terminalColors returns how many colors the terminal shows, going by COLORTERM and
+// the terminfo entry for TERM, and 256 if unknown
+func terminalColors() int {
+	if colorTerm := os.Getenv("COLORTERM"); colorTerm == "truecolor" || colorTerm == "24bit" {
+		return 1 << 24
+	}
+	ti, err := terminfo.LookupTerminfo(os.Getenv("TERM"))
+	if err != nil {
+		return 256
+	}
+	return ti.Colors
+}
+
+// styles and colors return the theme's elements by their name in the settings
+func (t *Theme) styles() map[string]*tcell.Style {
+	return map[string]*tcell.Style{
+		"border": &t.Border, "header": &t.Header, "read_only_header": &t.ReadOnlyHeader,
+		"read_only": &t.ReadOnly, "sort_marker": &t.SortMarker, "title": &t.Title,
+		"muted": &t.Muted, "filter": &t.Filter, "chip": &t.Chip, "thumb": &t.Thumb,
+		"selected": &t.Selected, "find_column": &t.FindColumn, "insert_row": &t.InsertRow,
+		"insert_border": &t.InsertBorder, "new": &t.New, "modified": &t.Modified,
+		"deleted": &t.Deleted, "delete": &t.Delete, "status_bar": &t.StatusBar, "palette": &t.Palette,
+	}
+}
+
+func (t *Theme) colors() map[string]*tcell.Color {
+	return map[string]*tcell.Color{
+		"error": &t.Error, "info": &t.Info, "value": &t.Value, "pending": &t.Pending,
+		"missing": &t.Missing, "match": &t.Match, "field": &t.Field, "preview": &t.Preview,
+	}
+}
+
+// set overrides the element's style with spec, as in "bold black on palegreen", or
+// its color with a color name or #rrggbb
+func (t *Theme) set(element, spec string) error {
+	if color, ok := t.colors()[element]; ok {
+		c, err := parseColor(spec)
+		if err != nil {
+			return fmt.Errorf("theme color %s: %w", element, err)
+		}
+		*color = c
+		return nil
+	}
+	style, ok := t.styles()[element]
+	if !ok {
+		return fmt.Errorf("unknown theme element %q", element)
+	}
+	s, err := parseStyle(spec)
+	if err != nil {
+		return fmt.Errorf("theme color %s: %w", element, err)
+	}
+	*style = s
+	return nil
+}
+
+// parseStyle reads a style of attributes and a foreground color, followed by "on"
+// and a background color, each optional
+func parseStyle(spec string) (tcell.Style, error) {
+	style := tcell.StyleDefault
+	background := false
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		switch word {
+		case "on":
+			background = true
+		case "bold":
+			style = style.Bold(true)
+		case "dim":
+			style = style.Dim(true)
+		case "italic":
+			style = style.Italic(true)
+		case "underline":
+			style = style.Underline(true)
+		case "reverse":
+			style = style.Reverse(true)
+		case "strikethrough":
+			style = style.StrikeThrough(true)
+		default:
+			c, err := parseColor(word)
+			if err != nil {
+				return style, err
+			}
+			if background {
+				style = style.Background(c)
+			} else {
+				style = style.Foreground(c)
+			}
+		}
+	}
+	return style, nil
+}
+
+// parseColor reads a color name or #rrggbb
+func parseColor(name string) (tcell.Color, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	c := tcell.GetColor(name)
+	if c == tcell.ColorDefault {
+		return c, fmt.Errorf("unknown color %q", name)
+	}
+	return c, nil
+}
+
+// basicColors replaces the 256-color shades of the built-in themes on terminals
+// with fewer colors, keeping states that nearest colors would merge apart
+var basicColors = map[tcell.Color]tcell.Color{
+	tcell.ColorDarkSlateGray:  tcell.ColorTeal,
+	tcell.ColorDarkGray:       tcell.ColorGray,
+	tcell.ColorLightGray:      tcell.ColorSilver,
+	tcell.ColorRoyalBlue:      tcell.ColorPurple,
+	tcell.ColorDarkBlue:       tcell.ColorNavy,
+	tcell.ColorDarkGreen:      tcell.ColorGreen,
+	tcell.ColorDarkRed:        tcell.ColorMaroon,
+	tcell.ColorBlueViolet:     tcell.ColorPurple,
+	tcell.ColorLightSteelBlue: tcell.ColorAqua,
+	tcell.ColorLightSkyBlue:   tcell.ColorAqua,
+	tcell.ColorPaleTurquoise:  tcell.ColorAqua,
+	tcell.ColorPaleGreen:      tcell.ColorLime,
+	tcell.ColorMistyRose:      tcell.ColorFuchsia,
+	tcell.ColorKhaki:          tcell.ColorYellow,
+	tcell.ColorDarkGoldenrod:  tcell.ColorOlive,
+	tcell.ColorDarkOrange:     tcell.ColorOlive,
+}
+
+// fit replaces the theme's colors by the closest of the terminal's first colors
+func (t *Theme) fit(colors int) {
+	palette := make([]tcell.Color, colors)
+	for i := range palette {
+		palette[i] = tcell.PaletteColor(i)
+	}
+	fitColor := func(c tcell.Color) tcell.Color {
+		if c == tcell.ColorDefault || slices.Contains(palette, c) {
+			return c
+		}
+		if basic, ok := basicColors[c]; ok && slices.Contains(palette, basic) {
+			return basic
+		}
+		return tcell.FindColor(c, palette)
+	}
+	for _, style := range t.styles() {
+		fg, bg, _ := style.Decompose()
+		*style = style.Foreground(fitColor(fg)).Background(fitColor(bg))
+	}
+	for _, color := range t.colors() {
+		*color = fitColor(*color)
+	}
+	w := &t.Widgets
+	for _, color := range []*tcell.Color{&w.PrimitiveBackgroundColor, &w.ContrastBackgroundColor,
+		&w.MoreContrastBackgroundColor, &w.BorderColor, &w.TitleColor, &w.GraphicsColor,
+		&w.PrimaryTextColor, &w.SecondaryTextColor, &w.TertiaryTextColor, &w.InverseTextColor,
+		&w.ContrastSecondaryTextColor} {
+		*color = fitColor(*color)
+	}
+}
+
+// over returns style with the colors and attributes state sets
+func over(style, state tcell.Style) tcell.Style {
+	fg, bg, attrs := state.Decompose()
+	if fg != tcell.ColorDefault {
+		style = style.Foreground(fg)
+	}
+	if bg != tcell.ColorDefault {
+		style = style.Background(bg)
+	}
+	_, _, base := style.Decompose()
+	return style.Attributes(base | attrs)
+}
+
+// styleTag returns the tview tag drawing text in style's foreground color and attributes
+func styleTag(style tcell.Style) string {
+	fg, _, attrs := style.Decompose()
+	flags := ""
+	for _, a := range []struct {
+		mask tcell.AttrMask
+		flag string
+	}{
+		{tcell.AttrBold, "b"}, {tcell.AttrDim, "d"}, {tcell.AttrItalic, "i"},
+		{tcell.AttrUnderline, "u"}, {tcell.AttrReverse, "r"}, {tcell.AttrStrikeThrough, "s"},
+	} {
+		if attrs&a.mask != 0 {
+			flags += a.flag
+		}
+	}
+	if flags == "" {
+		flags = "-"
+	}
+	return strings.TrimSuffix(colorTag(fg), "]") + "::" + flags + "]"
+}
+
+// colorTag returns the tview tag drawing text in c, or in the default color
+func colorTag(c tcell.Color) string {
+	if c == tcell.ColorDefault {
+		return "[-]"
+	}
+	return "[" + c.String() + "]"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestLoadTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	// No theme in the settings is the dark theme
+	dark, err := LoadTheme(&Settings{}, 256)
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	if _, bg, _ := dark.Modified.Decompose(); bg != tcell.ColorDarkGreen {
+		t.Errorf("Expected modified cells on dark green, got %v", bg)
+	}
+
+	// Overrides replace an element's style or color
+	light, err := LoadTheme(&Settings{Theme: "light", ThemeColors: map[string]string{
+		"modified": "bold black on #ccffcc",
+		"error":    "maroon",
+	}}, 1<<24)
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	fg, bg, attrs := light.Modified.Decompose()
+	if fg != tcell.ColorBlack || bg != tcell.NewHexColor(0xccffcc) || attrs&tcell.AttrBold == 0 {
+		t.Errorf("Expected bold black on #ccffcc, got %v on %v", fg, bg)
+	}
+	if light.Error != tcell.ColorMaroon {
+		t.Errorf("Expected errors in maroon, got %v", light.Error)
+	}
+
+	for _, settings := range []*Settings{
+		{Theme: "solarized"},
+		{ThemeColors: map[string]string{"modified": "black on mauve"}},
+		{ThemeColors: map[string]string{"cell": "red"}},
+	} {
+		if _, err := LoadTheme(settings, 256); err == nil {
+			t.Errorf("Expected an error loading %+v", settings)
+		}
+	}
+
+	// With 16 colors the insert row and selected cell still differ
+	basic, err := LoadTheme(&Settings{}, 16)
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	_, insert, _ := basic.InsertRow.Decompose()
+	_, selected, _ := basic.Selected.Decompose()
+	if insert == selected || insert >= tcell.PaletteColor(16) {
+		t.Errorf("Expected distinct basic colors, got %v and %v", insert, selected)
+	}
+
+	// NO_COLOR tells states apart by attributes alone
+	t.Setenv("NO_COLOR", "1")
+	mono, err := LoadTheme(&Settings{Theme: "light"}, 256)
+	if err != nil {
+		t.Fatalf("LoadTheme failed: %v", err)
+	}
+	fg, bg, attrs = mono.Modified.Decompose()
+	if fg != tcell.ColorDefault || bg != tcell.ColorDefault || attrs == 0 {
+		t.Errorf("Expected modified cells without colors but with attributes")
+	}
+	if tag := colorTag(mono.Error); tag != "[-]" {
+		t.Errorf("Expected errors in the default color, got %q", tag)
+	}
+}
+
+func TestOver(t *testing.T) {
+	base := tcell.StyleDefault.Foreground(tcell.ColorWhite).Italic(true)
+	fg, bg, attrs := over(base, tcell.StyleDefault.Background(tcell.ColorRed).Bold(true)).Decompose()
+	if fg != tcell.ColorWhite || bg != tcell.ColorRed || attrs != tcell.AttrItalic|tcell.AttrBold {
+		t.Errorf("Expected white bold italic on red, got %v on %v with %v", fg, bg, attrs)
+	}
+	if tag := styleTag(tcell.StyleDefault.Foreground(tcell.ColorGray).Bold(true)); tag != "[gray::b]" && tag != "[grey::b]" {
+		t.Errorf("Expected a bold gray tag, got %q", tag)
+	}
+}
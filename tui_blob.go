@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const pageHexDump = "hexdump"
+
+// blobMagic maps well-known file signatures to a short type name
+var blobMagic = []struct {
+	prefix []byte
+	name   string
+}{
+	{[]byte("\x89PNG\r\n\x1a\n"), "PNG"},
+	{[]byte("\xff\xd8\xff"), "JPEG"},
+	{[]byte("GIF87a"), "GIF"},
+	{[]byte("GIF89a"), "GIF"},
+	{[]byte("%PDF-"), "PDF"},
+	{[]byte("PK\x03\x04"), "ZIP"},
+	{[]byte("\x1f\x8b"), "GZIP"},
+	{[]byte("BZh"), "BZIP2"},
+	{[]byte("\xfd7zXZ\x00"), "XZ"},
+	{[]byte("(\xb5/\xfd"), "ZSTD"},
+	{[]byte("SQLite format 3\x00"), "SQLite"},
+	{[]byte("\x7fELF"), "ELF"},
+	{[]byte("OggS"), "OGG"},
+	{[]byte("ID3"), "MP3"},
+	{[]byte("BM"), "BMP"},
+}
+
+// isBinaryData reports whether b should be treated as binary rather than text.
+// Drivers return []byte for both text and blob columns, so decide by content.
+func isBinaryData(b []byte) bool {
+	if !utf8.Valid(b) {
+		return true
+	}
+	for _, c := range b {
+		if c < 0x20 && c != '\n' && c != '\r' && c != '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+// blobType returns the detected file type of b, or "" if unknown
+func blobType(b []byte) string {
+	for _, m := range blobMagic {
+		if bytes.HasPrefix(b, m.prefix) {
+			return m.name
+		}
+	}
+	// RIFF containers carry the format at offset 8
+	if len(b) >= 12 && bytes.HasPrefix(b, []byte("RIFF")) {
+		return strings.TrimSpace(string(b[8:12]))
+	}
+	return ""
+}
+
+// formatByteSize renders a byte count using binary units
+func formatByteSize(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// blobSummary renders binary data as its size plus type, or the leading bytes in hex
+func blobSummary(b []byte) string {
+	if kind := blobType(b); kind != "" {
+		return fmt.Sprintf("‹%s %s›", kind, formatByteSize(len(b)))
+	}
+	head := b[:min(len(b), 8)]
+	suffix := ""
+	if len(b) > len(head) {
+		suffix = "…"
+	}
+	return fmt.Sprintf("‹%s 0x%s%s›", formatByteSize(len(b)), hex.EncodeToString(head), suffix)
+}
+
+// selectedBlob returns the binary value of the selected cell, if any
+func (e *Editor) selectedBlob(row, col int) ([]byte, bool) {
+	if row < 0 || row >= e.table.GetDataLength() || col < 0 || col >= len(e.table.GetHeaders()) {
+		return nil, false
+	}
+	b, ok := e.table.GetCell(row, col).([]byte)
+	return b, ok
+}
+
+// showHexDump opens a scrollable hex dump of the selected cell
+func (e *Editor) showHexDump(row, col int) {
+	b, ok := e.selectedBlob(row, col)
+	if !ok {
+		e.SetStatusMessage("Cell does not contain binary data")
+		return
+	}
+
+	title := fmt.Sprintf(" %s · %s ", e.table.GetHeaders()[col].Name, formatByteSize(len(b)))
+	if kind := blobType(b); kind != "" {
+		title = fmt.Sprintf(" %s · %s %s ", e.table.GetHeaders()[col].Name, kind, formatByteSize(len(b)))
+	}
+	view := tview.NewTextView().
+		SetDynamicColors(false).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(hex.Dump(b))
+	view.SetBorder(true).SetTitle(title)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter || event.Rune() == 'q' {
+			e.pages.RemovePage(pageHexDump)
+			e.app.SetFocus(e.table)
+			return nil
+		}
+		return event
+	})
+
+	// 78 columns fit one hex.Dump line plus the border
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 1, 0, false).
+			AddItem(view, 0, 1, true).
+			AddItem(nil, 1, 0, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+	e.pages.AddPage(pageHexDump, modal, true, true)
+	e.app.SetFocus(view)
+	e.SetStatusMessage("Esc to close · save <file> to export")
+}
+
+// saveCellToFile writes the selected cell's raw value to path
+func (e *Editor) saveCellToFile(path string) {
+	row, col := e.table.GetSelection()
+	if row < 0 || row >= e.table.GetDataLength() || col < 0 || col >= len(e.table.GetHeaders()) {
+		e.SetStatusError("No cell selected")
+		return
+	}
+	path = expandHome(path)
+
+	var data []byte
+	switch v := e.table.GetCell(row, col).(type) {
+	case nil:
+		e.SetStatusError("Cell is null")
+		return
+	case []byte:
+		data = v
+	default:
+		text, _ := formatCellValue(v, tcell.StyleDefault)
+		data = []byte(text)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	e.SetStatusMessage(fmt.Sprintf("Saved %s to %s", formatByteSize(len(data)), path))
+}
+
+// loadFileIntoCell replaces the selected cell with the contents of path using a parameterized UPDATE
+func (e *Editor) loadFileIntoCell(path string) {
+	if e.relation == nil {
+		e.SetStatusError("No database connection available")
+		return
+	}
+	if len(e.insertRow) > 0 || e.editing {
+		e.SetStatusError("Finish editing before loading a file")
+		return
+	}
+	if e.dryRun {
+		e.SetStatusError("Loading files is not supported in dry-run mode")
+		return
+	}
+	row, col := e.table.GetSelection()
+	if row < 0 || row >= len(e.buffer) || col < 0 || col >= len(e.table.GetHeaders()) {
+		e.SetStatusError("No cell selected")
+		return
+	}
+	ptr := (row + e.pointer) % len(e.buffer)
+	if e.buffer[ptr].data == nil {
+		e.SetStatusError("No cell selected")
+		return
+	}
+	colName := e.table.GetHeaders()[col].Name
+	if colIdx, ok := e.relation.ColumnIndex[colName]; !ok || !e.relation.IsColumnEditable(colIdx) {
+		e.SetStatusError("Column is not editable")
+		return
+	}
+
+	path = expandHome(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+
+	// Records in display order so rowIdx matches the selected row
+	recordsData := make([][]any, len(e.buffer))
+	for i := range e.buffer {
+		recordsData[i] = e.buffer[(e.pointer+i)%len(e.buffer)].data
+	}
+	updated, err := e.relation.UpdateDBBytes(recordsData, row, colName, data)
+	if err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.buffer[ptr] = Row{state: RowStateNormal, data: updated[:min(len(updated), len(e.buffer[ptr].data))]}
+	e.renderData()
+	e.table.Select(row, col)
+	e.SetStatusMessage(fmt.Sprintf("Loaded %s from %s into %s", formatByteSize(len(data)), filepath.Base(path), colName))
+}
+
+// expandHome expands a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
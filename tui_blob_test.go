@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestBlobSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		binary   bool
+		expected string
+	}{
+		{
+			name:     "utf8 text is not binary",
+			input:    []byte("hello\nworld"),
+			binary:   false,
+			expected: "‹11 B 0x68656c6c6f0a776f…›",
+		},
+		{
+			name:     "png signature",
+			input:    append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 2040)...),
+			binary:   true,
+			expected: "‹PNG 2.0 KiB›",
+		},
+		{
+			name:     "unknown bytes show hex prefix",
+			input:    []byte{0x00, 0x01, 0xff},
+			binary:   true,
+			expected: "‹3 B 0x0001ff›",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinaryData(tt.input); got != tt.binary {
+				t.Errorf("isBinaryData() = %v, want %v", got, tt.binary)
+			}
+			if got := blobSummary(tt.input); got != tt.expected {
+				t.Errorf("blobSummary() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
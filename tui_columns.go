@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+const pageColumns = "columns"
+
+// chooserItem is a column in the column chooser, checked when shown
+type chooserItem struct {
+	col   dblib.DisplayColumn
+	shown bool
+}
+
+// columnChooser lists every column of the relation to show, hide and reorder them.
+// Changes apply when it's closed with Enter.
+type columnChooser struct {
+	items   []chooserItem
+	search  string
+	matches []int // indexes into items of the columns matching search, in list order
+	list    *tview.List
+	input   *tview.InputField
+
+	dragging int // item being dragged with the mouse, or -1
+}
+
+// chooserItems lists the shown columns in display order followed by the hidden ones
+func (e *Editor) chooserItems() []chooserItem {
+	headers := e.table.GetHeaders()
+	items := make([]chooserItem, 0, len(headers)+len(e.hiddenCols))
+	for _, h := range headers {
+		items = append(items, chooserItem{col: h, shown: true})
+	}
+	for _, h := range e.hiddenCols {
+		items = append(items, chooserItem{col: h})
+	}
+	return items
+}
+
+// filterChooser returns the indexes of the items whose names fuzzy match search
+func filterChooser(items []chooserItem, search string) []int {
+	matches := make([]int, 0, len(items))
+	for i, item := range items {
+		if ok, _ := fuzzyMatch(search, item.col.Name); ok {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// chooserLabel renders an item as a checkbox line, highlighting the matched letters
+func chooserLabel(item chooserItem, search string) string {
+	box := "[ ]"
+	if item.shown {
+		box = "[x]"
+	}
+	_, positions := fuzzyMatch(search, item.col.Name)
+	if search == "" {
+		positions = nil
+	}
+	label := tview.Escape(box) + " " + formatTableNameWithColor(item.col.Name, positions)
+	if item.col.IsKey {
+		label += " " + styleTag(theme.Muted) + "key[-::-]"
+	}
+	return label
+}
+
+// refresh redraws the list for the current search, keeping the highlighted item
+func (c *columnChooser) refresh(current int) {
+	c.matches = filterChooser(c.items, c.search)
+	c.list.Clear()
+	selected := 0
+	for i, idx := range c.matches {
+		c.list.AddItem(chooserLabel(c.items[idx], c.search), "", 0, nil)
+		if idx == current {
+			selected = i
+		}
+	}
+	c.list.SetCurrentItem(selected)
+}
+
+// current returns the index into items of the highlighted column, or -1
+func (c *columnChooser) current() int {
+	i := c.list.GetCurrentItem()
+	if i < 0 || i >= len(c.matches) {
+		return -1
+	}
+	return c.matches[i]
+}
+
+// toggle shows or hides the highlighted column
+func (c *columnChooser) toggle() {
+	idx := c.current()
+	if idx < 0 {
+		return
+	}
+	c.items[idx].shown = !c.items[idx].shown
+	c.refresh(idx)
+}
+
+// move swaps the highlighted column with the one listed direction steps away
+func (c *columnChooser) move(direction int) {
+	i := c.list.GetCurrentItem()
+	j := i + direction
+	if i < 0 || i >= len(c.matches) || j < 0 || j >= len(c.matches) {
+		return
+	}
+	a, b := c.matches[i], c.matches[j]
+	c.items[a], c.items[b] = c.items[b], c.items[a]
+	c.refresh(b)
+}
+
+// showColumnChooser opens the column chooser over the table
+func (e *Editor) showColumnChooser() {
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+
+	c := &columnChooser{items: e.chooserItems(), dragging: -1}
+	c.list = tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	c.input = tview.NewInputField().SetLabel("Filter: ").SetFieldStyle(theme.Palette)
+	c.input.SetChangedFunc(func(text string) {
+		c.search = text
+		c.refresh(c.current())
+	})
+	c.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			e.closeColumnChooser()
+		case event.Key() == tcell.KeyEnter:
+			if err := e.applyColumns(c.items); err != nil {
+				e.SetStatusError(err.Error())
+				return nil
+			}
+			e.closeColumnChooser()
+		case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+			c.toggle()
+		case event.Key() == tcell.KeyUp && event.Modifiers()&tcell.ModAlt != 0:
+			c.move(-1)
+		case event.Key() == tcell.KeyDown && event.Modifiers()&tcell.ModAlt != 0:
+			c.move(1)
+		case event.Key() == tcell.KeyUp, event.Key() == tcell.KeyDown,
+			event.Key() == tcell.KeyPgUp, event.Key() == tcell.KeyPgDn:
+			c.list.InputHandler()(event, nil)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	// Click a column to show or hide it, drag it to reorder
+	c.list.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		_, y := event.Position()
+		_, top, _, _ := c.list.GetInnerRect()
+		offset, _ := c.list.GetOffset()
+		i := y - top + offset
+		switch action {
+		case tview.MouseLeftDown:
+			if i >= 0 && i < len(c.matches) {
+				c.dragging = i
+				c.list.SetCurrentItem(i)
+			}
+			return tview.MouseConsumed, nil
+		case tview.MouseMove:
+			if event.Buttons()&tcell.ButtonPrimary == 0 {
+				c.dragging = -1 // released outside the list
+			}
+			if c.dragging >= 0 && i >= 0 && i < len(c.matches) && i != c.dragging {
+				c.list.SetCurrentItem(c.dragging)
+				c.move(i - c.dragging)
+				c.dragging = i
+			}
+			return tview.MouseConsumed, nil
+		case tview.MouseLeftUp:
+			c.dragging = -1
+			return tview.MouseConsumed, nil
+		case tview.MouseLeftClick, tview.MouseLeftDoubleClick:
+			if i >= 0 && i < len(c.matches) {
+				c.list.SetCurrentItem(i)
+				c.toggle()
+			}
+			e.app.SetFocus(c.input)
+			return tview.MouseConsumed, nil
+		}
+		return action, event
+	})
+	c.refresh(max(0, e.table.selectedCol))
+
+	width := len("Filter: ") + 16
+	for _, item := range c.items {
+		width = max(width, len(item.col.Name)+len("[x]  key"))
+	}
+	height := min(len(c.items), 20) + 3
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(c.input, 1, 0, true).
+		AddItem(c.list, 0, 1, false)
+	panel.SetBorder(true).SetTitle(" Columns ")
+
+	// Center the panel over the table
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(panel, height, 0, true).
+			AddItem(nil, 0, 1, false), width+4, 0, true).
+		AddItem(nil, 0, 1, false)
+	e.pages.AddPage(pageColumns, modal, true, true)
+	e.app.SetFocus(c.input)
+	e.SetStatusMessage("Space to show/hide · alt+↑/↓ or drag to reorder · type to filter · Enter to apply · Esc to cancel")
+}
+
+// closeColumnChooser removes the column chooser and returns focus to the table
+func (e *Editor) closeColumnChooser() {
+	e.pages.RemovePage(pageColumns)
+	e.app.SetFocus(e.table)
+}
+
+// applyColumns shows the checked columns in the chooser's order and hides the rest,
+// reloading the rows so hidden columns are no longer fetched
+func (e *Editor) applyColumns(items []chooserItem) error {
+	var shown, hidden []dblib.DisplayColumn
+	for _, item := range items {
+		if item.shown {
+			shown = append(shown, item.col)
+		} else {
+			hidden = append(hidden, item.col)
+		}
+	}
+	if len(shown) == 0 {
+		return fmt.Errorf("show at least one column")
+	}
+
+	// Columns stay pinned while they lead the table
+	headers := e.table.GetHeaders()
+	var wasPinned []string
+	for _, h := range headers[:e.table.GetPinned()] {
+		wasPinned = append(wasPinned, h.Name)
+	}
+	pinned := 0
+	for pinned < len(shown) && slices.Contains(wasPinned, shown[pinned].Name) {
+		pinned++
+	}
+
+	// Keep the selected column selected if it's still shown
+	loc := e.currentLocation()
+	col := max(0, slices.IndexFunc(shown, func(h dblib.DisplayColumn) bool { return h.Name == loc.column }))
+
+	e.table.SetHeaders(shown).SetPinned(pinned)
+	e.hiddenCols = hidden
+	if err := e.loadFromRowId(loc.topKey, true, col); err != nil {
+		return err
+	}
+	e.table.Select(loc.row, col)
+	e.ensureColumnVisible(col)
+	e.layoutChanged()
+	switch len(hidden) {
+	case 0:
+		e.SetStatusMessage("Showing all columns")
+	case 1:
+		e.SetStatusMessage("1 column hidden · alt+c to show it")
+	default:
+		e.SetStatusMessage(fmt.Sprintf("%d columns hidden · alt+c to show them", len(hidden)))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+func TestApplyColumns(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.statusBar = nil
+
+	// Hiding name stops fetching it
+	items := e.chooserItems()
+	items[1].shown = false
+	if err := e.applyColumns(items); err != nil {
+		t.Fatalf("applyColumns failed: %v", err)
+	}
+	if headers := e.table.GetHeaders(); len(headers) != 1 || headers[0].Name != "id" {
+		t.Fatalf("Expected only id shown, got %+v", headers)
+	}
+	if names, _ := e.queryColumns(); strings.Join(names, ",") != "id" {
+		t.Errorf("Expected only id selected, got %v", names)
+	}
+	if e.buffer[0].data[1] != nil {
+		t.Errorf("Expected name not to be fetched, got %+v", e.buffer[0].data)
+	}
+
+	// The key is still fetched when hidden, and rows keep their keys when reordered
+	items = e.chooserItems()
+	items[0].shown, items[1].shown = false, true
+	items[0], items[1] = items[1], items[0]
+	if err := e.applyColumns(items); err != nil {
+		t.Fatalf("applyColumns failed: %v", err)
+	}
+	if names, _ := e.queryColumns(); strings.Join(names, ",") != "id,name" {
+		t.Errorf("Expected the hidden key to be selected, got %v", names)
+	}
+	if got := e.table.GetCell(1, 0); got != "Bob" {
+		t.Errorf("Expected name shown, got %v", got)
+	}
+	if keys := e.extractKeys(e.buffer[1].data); len(keys) != 1 || keys[0] != int64(2) {
+		t.Errorf("Expected Bob's key, got %v", keys)
+	}
+
+	items = e.chooserItems()
+	for i := range items {
+		items[i].shown = false
+	}
+	if err := e.applyColumns(items); err == nil {
+		t.Error("Expected hiding every column to be refused")
+	}
+}
+
+func TestColumnChooser(t *testing.T) {
+	c := &columnChooser{
+		items: []chooserItem{
+			{col: dblib.DisplayColumn{Name: "id"}, shown: true},
+			{col: dblib.DisplayColumn{Name: "name"}, shown: true},
+			{col: dblib.DisplayColumn{Name: "email"}},
+		},
+		list: tview.NewList(),
+	}
+	c.search = "em"
+	c.refresh(0)
+	if len(c.matches) != 1 || c.matches[0] != 2 {
+		t.Fatalf("Expected email to match, got %v", c.matches)
+	}
+	c.toggle()
+	if !c.items[2].shown {
+		t.Error("Expected email to be shown")
+	}
+
+	c.search = ""
+	c.refresh(2)
+	c.move(-3) // no-op beyond the top
+	c.move(-1)
+	if c.items[1].col.Name != "email" || c.current() != 1 {
+		t.Errorf("Expected email moved up and highlighted, got %+v at %d", c.items, c.current())
+	}
+}
+
+func TestHiddenColumnJournal(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.statusBar = nil
+	e.dryRun = false
+
+	items := e.chooserItems()
+	items[1].shown = false
+	if err := e.applyColumns(items); err != nil {
+		t.Fatalf("applyColumns failed: %v", err)
+	}
+	e.stopRowsTimer()
+
+	// The journal records the hidden name as stored, not as the unfetched nil
+	e.updateCell(0, 0, "10")
+	path, err := getJournalPath()
+	if err != nil {
+		t.Fatalf("getJournalPath failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	entries, err := readJournal(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("readJournal failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Op != "UPDATE" {
+		t.Fatalf("Expected one UPDATE entry, got %+v", entries)
+	}
+	if before := entries[0].Before; before["name"] != "Alice" || before["id"] != float64(1) {
+		t.Errorf("Expected the stored row before the update, got %+v", before)
+	}
+	if after := entries[0].After; after["name"] != "Alice" || after["id"] != float64(10) {
+		t.Errorf("Expected the stored row after the update, got %+v", after)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"ted/internal/dblib"
@@ -45,11 +46,17 @@ func (e *Editor) executeSQL(query string) {
 		return
 	}
 
+	if e.dryRun {
+		e.dryRunSQL(query)
+		return
+	}
+
 	result, err := e.relation.DB.Exec(query)
 	if err != nil {
 		e.SetStatusError(err.Error())
 		return
 	}
+	e.journalSQL(query)
 
 	// Try to get last insert id
 	lastInsertId, lastIdErr := result.LastInsertId()
@@ -94,7 +101,86 @@ func (e *Editor) executeCommand(command string) {
 	case "quit", "q":
 		e.app.Stop()
 	case "help", "h":
-		e.SetStatusMessage("Commands: quit, refresh, help")
+		e.SetStatusMessage("Commands: quit, refresh, help, follow, back, forward, references, columns, detail, split, unsplit, close, fit, wrap, reset, hex, save, load, script, unfilter")
+	case "follow":
+		row, col := e.table.GetSelection()
+		e.followReference(row, col)
+	case "references", "refs":
+		row, col := e.table.GetSelection()
+		e.showIncomingReferences(row, col, e.openReferencingRows)
+	case "columns", "cols":
+		e.showColumnChooser()
+	case "detail":
+		e.showDetail()
+	case "split":
+		if e.split == nil {
+			e.toggleSplit()
+		}
+	case "unsplit":
+		e.closeSplit()
+	case "close":
+		e.closeTab()
+	case "reset":
+		e.resetLayout()
+	case "fit":
+		switch {
+		case len(args) == 0:
+			_, col := e.table.GetSelection()
+			e.fitColumns(col)
+		case args[0] == "all":
+			e.fitColumns(-1)
+		default:
+			e.SetStatusMessage("Usage: fit [all]")
+		}
+	case "wrap":
+		switch {
+		case len(args) == 0:
+			_, col := e.table.GetSelection()
+			e.toggleWrap(col, false)
+		case args[0] == "all":
+			e.toggleWrap(-1, true)
+		default:
+			e.SetStatusMessage("Usage: wrap [all]")
+		}
+	case "back":
+		e.navigateBack()
+	case "forward":
+		e.navigateForward()
+	case "hex":
+		row, col := e.table.GetSelection()
+		e.showHexDump(row, col)
+	case "save":
+		if len(args) > 0 {
+			e.saveCellToFile(strings.TrimSpace(strings.TrimPrefix(command, cmd)))
+		} else {
+			e.SetStatusMessage("Usage: save <file>")
+		}
+	case "load":
+		if len(args) > 0 {
+			e.loadFileIntoCell(strings.TrimSpace(strings.TrimPrefix(command, cmd)))
+		} else {
+			e.SetStatusMessage("Usage: load <file>")
+		}
+	case "script":
+		if len(args) > 0 {
+			e.saveScriptCommand(strings.TrimSpace(strings.TrimPrefix(command, cmd)))
+		} else {
+			e.showScript()
+		}
+	case "unfilter":
+		switch {
+		case len(args) == 0:
+			e.removeQuickFilter(-1)
+		case args[0] == "all":
+			e.clearFilters()
+		default:
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				e.SetStatusMessage("Usage: unfilter [n|all]")
+				return
+			}
+			e.removeQuickFilter(n - 1)
+		}
 	case "log":
 		if len(args) > 0 {
 			e.SetStatusLog(strings.Join(args, " "))
@@ -114,7 +200,14 @@ func (e *Editor) executeCommand(command string) {
 
 // enterDeleteMode enters delete mode for the current row
 func (e *Editor) enterDeleteMode(row, col int) {
-	if row < 0 || row >= len(e.buffer) || e.buffer[row].data == nil {
+	if row < 0 || row >= len(e.buffer) {
+		return
+	}
+	idx := (row + e.pointer) % len(e.buffer)
+	if e.buffer[idx].data == nil {
+		return
+	}
+	if !e.canWriteRows() {
 		return
 	}
 
@@ -124,7 +217,7 @@ func (e *Editor) enterDeleteMode(row, col int) {
 	for i := range e.buffer {
 		recordsData[i] = e.buffer[i].data
 	}
-	preview := e.relation.BuildDeletePreview(recordsData, row)
+	preview := e.relation.BuildDeletePreview(recordsData, idx)
 	if preview == "" {
 		e.SetStatusError("Cannot delete row without key values")
 		return
@@ -134,19 +227,70 @@ func (e *Editor) enterDeleteMode(row, col int) {
 	e.setPaletteMode(PaletteModeDelete, false)
 	e.commandPalette.SetPlaceholder(preview)
 	e.commandPalette.SetPlaceholderStyle(e.commandPalette.GetPlaceholderStyle())
-	e.SetStatusMessage("Enter to confirm deletion · Esc to cancel")
+	status := "Enter to confirm deletion · Esc to cancel"
+	depth := e.config.CascadeDepth
+	if depth <= 0 {
+		depth = dblib.DefaultCascadeDepth
+	}
+	effects, err := e.relation.DeleteImpact(e.buffer[idx].data, depth)
+	if err != nil {
+		status = colorTag(theme.Error) + "Could not check references: " + err.Error() + "[-] · " + status
+	} else if impact := formatDeleteImpact(effects, depth); impact != "" {
+		status = impact + " · " + status
+	}
+	e.SetStatusMessage(status)
 
 	// Store the row being deleted in table selection
 	e.table.Select(row, col)
 }
 
+// formatDeleteImpact summarizes what a delete does to referencing rows, in red if
+// a foreign key blocks it. Rows of foreign keys SQLite doesn't enforce are left as
+// they are.
+func formatDeleteImpact(effects []dblib.DeleteEffect, depth int) string {
+	rows := func(n int64) string {
+		if n == 1 {
+			return "1 row"
+		}
+		return fmt.Sprintf("%d rows", n)
+	}
+
+	var parts, blocked []string
+	for _, eff := range effects {
+		switch {
+		case eff.Unenforced:
+			parts = append(parts, fmt.Sprintf("leaves %s in %s (%s not enforced: foreign_keys is off)", rows(eff.Count), eff.Table, eff.Action))
+		case eff.Blocks():
+			blocked = append(blocked, fmt.Sprintf("%s from %s (%s)", eff.Action, eff.Table, rows(eff.Count)))
+		case eff.Action == "CASCADE":
+			part := fmt.Sprintf("deletes %s in %s", rows(eff.Count), eff.Table)
+			if eff.More {
+				part += fmt.Sprintf(" (and more beyond depth %d)", depth)
+			}
+			parts = append(parts, part)
+		case eff.Action == "SET NULL":
+			parts = append(parts, fmt.Sprintf("sets %s NULL in %s", rows(eff.Count), eff.Table))
+		case eff.Action == "SET DEFAULT":
+			parts = append(parts, fmt.Sprintf("resets %s to DEFAULT in %s", rows(eff.Count), eff.Table))
+		}
+	}
+	if len(blocked) > 0 {
+		return colorTag(theme.Error) + "Blocked by " + strings.Join(blocked, ", ") + "[-]"
+	}
+	return strings.Join(parts, ", ")
+}
+
 // executeDelete executes the DELETE statement for the current row
 func (e *Editor) executeDelete() error {
 	row, col := e.table.GetSelection()
-	if row < 0 || row >= len(e.buffer) || e.buffer[row].data == nil {
+	if row < 0 || row >= len(e.buffer) || e.buffer[(row+e.pointer)%len(e.buffer)].data == nil {
 		e.SetStatusError("Invalid row for deletion")
 		return fmt.Errorf("invalid row")
 	}
+	if e.dryRun {
+		e.dryRunDelete(row)
+		return nil
+	}
 
 	// Execute the delete
 	// Convert records to [][]any for DeleteDBRecord
@@ -154,7 +298,7 @@ func (e *Editor) executeDelete() error {
 	for i := range e.buffer {
 		recordsData[i] = e.buffer[i].data
 	}
-	err := e.relation.DeleteDBRecord(recordsData, row)
+	err := e.relation.DeleteDBRecord(recordsData, (row+e.pointer)%len(e.buffer))
 	if err != nil {
 		e.SetStatusErrorWithSentry(err)
 		return err
@@ -166,25 +310,24 @@ func (e *Editor) executeDelete() error {
 	return nil
 }
 
-func (e *Editor) executeFind(findValue string) {
+// executeFind selects the next row matching findValue, or the previous one when backward
+func (e *Editor) executeFind(findValue string, backward bool) {
 	if e.relation == nil {
 		e.SetStatusError("No database connection available")
 		return
 	}
 
 	row, col := e.table.GetSelection()
-	if row < 0 || row >= len(e.buffer) || e.buffer[row].data == nil {
+	if row < 0 || row >= len(e.buffer) || col < 0 || col >= len(e.table.GetHeaders()) {
 		e.SetStatusError("Invalid current row")
 		return
 	}
-
-	// Get current row's key values
-	currentKeys := make([]any, len(e.relation.Key))
-	for i, keyIdx := range e.relation.Key {
-		if keyIdx < len(e.buffer[row].data) {
-			currentKeys[i] = e.buffer[row].data[keyIdx]
-		}
+	data := e.buffer[(row+e.pointer)%len(e.buffer)].data
+	if data == nil {
+		e.SetStatusError("Invalid current row")
+		return
 	}
+	currentKeys := e.extractKeys(data)
 
 	// Find the column index in relation.Columns
 	findCol := -1
@@ -193,15 +336,22 @@ func (e *Editor) executeFind(findValue string) {
 		findCol = colIdx
 	}
 
-	if findCol == -1 {
+	if findCol == -1 && !e.findOpts.AllColumns {
 		e.SetStatusError("Column not found in relation")
 		return
 	}
 
-	// Use FindNextRow to search for the next matching row
-	foundKeys, foundBelow, err := e.relation.FindNextRow(findCol, findValue, nil, nil, currentKeys)
+	// Sort values place the current row in the sorted order
+	var sortVals []any
+	if cursor := e.rowCursor(data); len(cursor) > len(currentKeys) {
+		sortVals = cursor[:len(cursor)-len(currentKeys)]
+	}
+
+	opts := e.findOpts
+	opts.Backward = backward
+	foundKeys, foundBelow, err := e.relation.FindRow(findCol, findValue, opts, e.sortCols, sortVals, currentKeys)
 	if err != nil {
-		e.SetStatusErrorWithSentry(err)
+		e.SetStatusError(err.Error())
 		return
 	}
 
@@ -210,54 +360,79 @@ func (e *Editor) executeFind(findValue string) {
 		return
 	}
 
-	// Check if the found row is within the current record window
-	foundInWindow := false
-	var foundRow int
+	if foundRow := e.displayRowOf(foundKeys); foundRow >= 0 {
+		// Row is in the current window, just select it
+		e.table.Select(foundRow, col)
+	} else {
+		// Load rows so the match sits at the bottom when it is below the window,
+		// or at the top when it is above
+		if err := e.loadFromRowId(foundKeys, !foundBelow, col); err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		if foundRow := e.displayRowOf(foundKeys); foundRow >= 0 {
+			e.table.Select(foundRow, col)
+		}
+	}
 
+	message := "Match found"
+	if position, total, err := e.relation.CountMatches(findCol, findValue, opts, e.sortCols, foundKeys); err == nil {
+		message = fmt.Sprintf("Match %d of %d", position, total)
+	}
+	if foundBelow == backward {
+		message += " · wrapped"
+	}
+	e.SetStatusMessage(message)
+}
+
+// displayRowOf returns the table row showing the row with keys, or -1 when it isn't loaded
+func (e *Editor) displayRowOf(keys []any) int {
 	for i := 0; i < len(e.buffer); i++ {
-		if e.buffer[i].data == nil {
+		r := e.buffer[(e.pointer+i)%len(e.buffer)]
+		if r.data == nil {
 			break
 		}
-		// Compare key values
-		match := true
-		for j, keyIdx := range e.relation.Key {
-			if keyIdx >= len(e.buffer[i].data) || keyIdx >= len(foundKeys) {
-				match = false
-				break
-			}
-			if e.buffer[i].data[keyIdx] != foundKeys[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			foundInWindow = true
-			foundRow = i
-			break
+		if keysEqual(e.extractKeys(r.data), keys) {
+			return i
 		}
 	}
+	return -1
+}
 
-	if foundInWindow {
-		// Row is in the current window, just select it
-		e.table.Select(foundRow, col)
-		e.SetStatusMessage("Match found")
-	} else {
-		// Row is outside the current window, need to load from that row
-		if foundBelow {
-			// Found row is after current window, load from bottom (reverse order)
-			if err := e.loadFromRowId(foundKeys, false, col); err != nil {
-				e.SetStatusErrorWithSentry(err)
-				return
-			}
-		} else {
-			// Found row wrapped around to before current window, load from top
-			if err := e.loadFromRowId(foundKeys, true, col); err != nil {
-				e.SetStatusErrorWithSentry(err)
-				return
-			}
-		}
-		e.SetStatusMessage("Match found")
+// findLabel is the find palette label, naming the options that differ from exact
+// matching in the selected column
+func findLabel(opts dblib.FindOptions) string {
+	var parts []string
+	if opts.Mode != dblib.FindExact {
+		parts = append(parts, opts.Mode.String())
+	}
+	if opts.IgnoreCase {
+		parts = append(parts, "ignore case")
+	}
+	if opts.AllColumns {
+		parts = append(parts, "all columns")
+	}
+	if len(parts) == 0 {
+		return PaletteModeFind.Glyph()
+	}
+	return PaletteModeFind.Glyph() + "[" + strings.Join(parts, ", ") + "] "
+}
+
+// toggleFindOption changes a find option from the find palette: m cycles the match
+// mode, c toggles ignoring case and a toggles searching all text columns
+func (e *Editor) toggleFindOption(option rune) bool {
+	switch option {
+	case 'm':
+		e.findOpts.Mode = (e.findOpts.Mode + 1) % (dblib.FindRegex + 1)
+	case 'c':
+		e.findOpts.IgnoreCase = !e.findOpts.IgnoreCase
+	case 'a':
+		e.findOpts.AllColumns = !e.findOpts.AllColumns
+	default:
+		return false
 	}
+	e.commandPalette.SetLabel(findLabel(e.findOpts))
+	return true
 }
 
 // validateAndCleanSQL validates SQL input, removes trailing semicolons,
@@ -325,37 +500,5 @@ func (e *Editor) selectTableFromPicker(tableName string, isRelation bool) {
 		return
 	}
 
-	// Update the relation
-	e.relation = relation
-
-	// Build table headers in database schema order
-	headers := make([]dblib.DisplayColumn, 0, len(e.relation.Columns))
-	for i, col := range e.relation.Columns {
-		isKey := false
-		for _, keyIdx := range e.relation.Key {
-			if keyIdx == i {
-				isKey = true
-				break
-			}
-		}
-		editable := e.relation.IsColumnEditable(i)
-		headers = append(headers, dblib.DisplayColumn{Name: col.Name, Width: DefaultColumnWidth, IsKey: isKey, Editable: editable})
-	}
-	e.table.SetHeaders(headers).SetTableName(displayName).SetVimMode(e.vimMode)
-
-	// Reload data from the beginning
-	e.pointer = 0
-	e.loadFromRowId(nil, true, 0)
-	e.renderData()
-	e.table.Select(0, 0)
-
-	// Update title
-	if e.relation.IsCustomSQL {
-		e.app.SetTitle(fmt.Sprintf("ted %s/[SQL Query] %s", e.config.Database, databaseIcons[e.relation.DBType]))
-	} else {
-		e.app.SetTitle(fmt.Sprintf("ted %s/%s %s", e.config.Database, displayName, databaseIcons[e.relation.DBType]))
-	}
-
-	// Set focus back to table
-	e.app.SetFocus(e.table)
+	e.openTab(relation, displayName)
 }
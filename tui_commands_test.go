@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
 )
 
 func TestValidateAndCleanSQL(t *testing.T) {
@@ -71,3 +73,117 @@ func TestValidateAndCleanSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatDeleteImpact(t *testing.T) {
+	tests := []struct {
+		name     string
+		effects  []dblib.DeleteEffect
+		expected string
+	}{
+		{
+			name:     "no references",
+			expected: "",
+		},
+		{
+			name: "cascade and set null",
+			effects: []dblib.DeleteEffect{
+				{Table: "orders", Action: "CASCADE", Count: 2, Depth: 1},
+				{Table: "items", Action: "CASCADE", Count: 1, Depth: 2, More: true},
+				{Table: "notes", Action: "SET NULL", Count: 3, Depth: 1},
+			},
+			expected: "deletes 2 rows in orders, deletes 1 row in items (and more beyond depth 2), sets 3 rows NULL in notes",
+		},
+		{
+			name: "blocked",
+			effects: []dblib.DeleteEffect{
+				{Table: "orders", Action: "CASCADE", Count: 2, Depth: 1},
+				{Table: "invoices", Action: "RESTRICT", Count: 1, Depth: 2},
+			},
+			expected: "[red]Blocked by RESTRICT from invoices (1 row)[-]",
+		},
+		{
+			name: "not enforced",
+			effects: []dblib.DeleteEffect{
+				{Table: "orders", Action: "CASCADE", Count: 2, Depth: 1, Unenforced: true},
+				{Table: "invoices", Action: "RESTRICT", Count: 1, Depth: 1, Unenforced: true},
+			},
+			expected: "leaves 2 rows in orders (CASCADE not enforced: foreign_keys is off), leaves 1 row in invoices (RESTRICT not enforced: foreign_keys is off)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDeleteImpact(tt.effects, 2); got != tt.expected {
+				t.Errorf("formatDeleteImpact() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteFind(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.dryRun = false
+	e.statusBar = nil
+
+	e.findOpts = dblib.FindOptions{Mode: dblib.FindSubstring, IgnoreCase: true}
+	e.table.Select(0, 1)
+	e.executeFind("O", false)
+	if row, _ := e.table.GetSelection(); row != 1 {
+		t.Errorf("Expected Bob's row to be selected, got row %d", row)
+	}
+
+	// Shift+Enter searches upward from Bob
+	e.findOpts.Mode = dblib.FindPrefix
+	e.executeFind("al", true)
+	if row, _ := e.table.GetSelection(); row != 0 {
+		t.Errorf("Expected Alice's row to be selected, got row %d", row)
+	}
+}
+
+func TestExecuteDeleteAfterScrolling(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.dryRun = false
+	e.statusBar = nil
+	for id := 3; id <= 10; id++ {
+		if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (?, 'user')`, id); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+
+	// Scrolled down a row, the first row on screen is Bob
+	if _, err := e.nextRows(1); err != nil {
+		t.Fatalf("nextRows failed: %v", err)
+	}
+	e.stopRowsTimer() // as the rows timer does once scrolling stops, releasing the database
+	e.table.Select(0, 0)
+	if err := e.executeDelete(); err != nil {
+		t.Fatalf("executeDelete failed: %v", err)
+	}
+	var names []string
+	rows, err := db.Query(`SELECT name FROM users WHERE id <= 2 ORDER BY id`)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		names = append(names, name)
+	}
+	if len(names) != 1 || names[0] != "Alice" {
+		t.Errorf("Expected Bob deleted and Alice kept, got %v", names)
+	}
+}
+
+func TestFindLabel(t *testing.T) {
+	if got := findLabel(dblib.FindOptions{}); got != PaletteModeFind.Glyph() {
+		t.Errorf("findLabel for exact matching = %q", got)
+	}
+	got := findLabel(dblib.FindOptions{Mode: dblib.FindRegex, IgnoreCase: true, AllColumns: true})
+	if want := PaletteModeFind.Glyph() + "[regex, ignore case, all columns] "; got != want {
+		t.Errorf("findLabel = %q, want %q", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,8 @@ import (
 
 const (
 	DefaultColumnWidth   = 8
+	MaxFitColumnWidth    = 40 // widest a column is made to fit its content
+	MaxRowLines          = 6  // tallest a row with wrapped text is drawn
 	RowsTimerInterval    = 100 * time.Millisecond
 	RefreshTimerInterval = 300 * time.Millisecond
 	pagePicker           = "picker"
@@ -35,6 +38,7 @@ type Row struct {
 	state    RowState
 	data     []any
 	modified []int // indices of columns that were modified in the last refresh
+	pending  bool  // inserted during a dry run, not in the database yet
 }
 
 // BottomBorderRow is a sentinel value used to mark the end of data
@@ -44,12 +48,25 @@ var BottomBorderRow = Row{}
 // lookup key is unique: it's always selected
 // if a multicolumn reference is selected, all columns in the reference are selected
 type Editor struct {
-	app      *tview.Application
-	pages    *tview.Pages
-	table    *TableView
-	relation *dblib.Relation
-	config   *Config
-	vimMode  bool
+	app     *tview.Application
+	pages   *tview.Pages
+	config  *Config
+	vimMode bool
+
+	// runs slow work such as counting rows, then the function it returns on the UI
+	// goroutine; nil for a goroutine and QueueUpdateDraw, set to run in place in tests
+	background func(work func() func())
+
+	// The table worked on, the focused one in a split, embedded so its fields read
+	// as the editor's. withPane swaps in the other pane of a split to load it.
+	*pane
+
+	// master/detail split below the table, nil when not split
+	split *splitView
+
+	// Open tabs; the current one's panes are kept in pane and split while shown
+	tabs     []*tab
+	tabIndex int
 
 	// Database connection (stored separately to support table switching when relation is nil)
 	db     *sql.DB
@@ -67,7 +84,31 @@ type Editor struct {
 	lastGPress          time.Time // For detecting 'gg' in vim mode
 
 	// selection
-	editing   bool
+	editing bool
+
+	// record detail view while it's open, laid out again when the table resizes
+	detail *detailView
+
+	// find palette options, kept between searches
+	findOpts dblib.FindOptions
+
+	// find and replace in the replace palette, planned on the first Enter and run on the second
+	replaceFind string
+	replaceCol  int
+	replacePlan *dblib.ReplacePlan
+
+	// dry run: writes are shown as pending and collected into a script instead of executed
+	dryRun         bool
+	dryRunScript   []string
+	pendingRows    map[string]Row // keyed by pendingKey of the loaded row
+	pendingInserts []pendingInsert
+}
+
+// pane is a table shown in the editor with the relation behind it, the rows loaded
+// and the queries and timers loading them
+type pane struct {
+	table     *TableView
+	relation  *dblib.Relation
 	insertRow []any // insert mode row data (moved from TableView)
 
 	// data, records is a circular buffer
@@ -77,9 +118,35 @@ type Editor struct {
 	pointer    int        // pointer to the current record
 	buffer     []Row      // circular buffer of rows
 
+	// columns taken out of the table with the column chooser, in chooser order
+	hiddenCols []dblib.DisplayColumn
+
+	// fit the columns to the first rows rendered, set when a relation opens without a saved layout
+	fitPending bool
+
+	// the columns, sort or filters changed since the layout was last saved
+	layoutDirty bool
+
+	// interactive sort, rows are paged by (sort columns..., key...)
+	sortCols []dblib.SortColumn
+
+	// row count and key sample behind the position indicator and scrollbar, reset on each load
+	rowCounted    bool
+	rowCount      int64
+	rowCountExact bool
+	keySample     *dblib.KeySample
+	positionKey   string // top row cursor the position was last worked out for
+	positionBusy  bool   // the count or position is being worked out in the background
+	countGen      int    // bumped as the count resets, so counts begun before are dropped
+	keepRowCount  bool   // set while the scrollbar jumps or the table resizes, which leave the rows as they are
+
 	// change tracking for refresh
 	previousRows []Row // snapshot of rows from last refresh
 
+	// navigation history for following foreign keys
+	backStack    []navLocation
+	forwardStack []navLocation
+
 	// timer for auto-closing rows
 	rowsTimer      *time.Timer
 	rowsTimerReset chan struct{}
@@ -100,6 +167,9 @@ const (
 	PaletteModeUpdate
 	PaletteModeInsert
 	PaletteModeDelete
+	PaletteModeFilter
+	PaletteModeReplace
+	PaletteModeGoto
 )
 
 func (m PaletteMode) Glyph() string {
@@ -118,6 +188,12 @@ func (m PaletteMode) Glyph() string {
 		return "` "
 	case PaletteModeDelete:
 		return "✗ "
+	case PaletteModeFilter:
+		return "σ "
+	case PaletteModeReplace:
+		return "⇄ "
+	case PaletteModeGoto:
+		return "# "
 	default:
 		return "> "
 	}
@@ -145,8 +221,127 @@ func mouseActionString(action tview.MouseAction) string {
 	}
 }
 
+// newTable creates a table view whose mouse handlers work on the editor's focused
+// pane, and which focuses its own pane when clicked or scrolled in a split
+func (e *Editor) newTable(height int, headers []dblib.DisplayColumn) *TableView {
+	var tv *TableView
+	tv = NewTableView(height, &TableViewConfig{
+		Headers: headers,
+		DoubleClickFunc: func(row, col int) {
+			e.enterEditMode(row, col)
+		},
+		SingleClickFunc: func(row, col int) {
+			if e.editing {
+				e.exitEditMode()
+			}
+		},
+		HeaderClickFunc: func(col int, add bool) {
+			e.toggleSort(col, add)
+		},
+		ChipClickFunc: func(chip int) {
+			e.removeQuickFilter(chip)
+		},
+		TabClickFunc: func(tab int) {
+			e.switchTab(tab)
+		},
+		ScrollbarFunc: func(fraction float64) {
+			e.scrollToFraction(fraction)
+		},
+		ColumnResizeFunc: func(col int) {
+			e.layoutChanged()
+		},
+		ResizeFunc: func(width, rows int) {
+			// Reported while drawing, so reload and lay out again after the draw
+			go func() {
+				e.app.QueueUpdateDraw(func() {
+					e.withPane(e.paneOf(tv), func() { e.resize(rows) })
+					e.layoutDetail()
+				})
+			}()
+		},
+		TableNameClickFunc: func() {
+			e.pages.ShowPage(pagePicker)
+			e.app.SetFocus(e.tablePicker)
+			e.app.SetAfterDrawFunc(func(screen tcell.Screen) {
+				screen.SetCursorStyle(tcell.CursorStyleBlinkingBar)
+			})
+		},
+		MouseScrollFunc: func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+			// Record mouse event in breadcrumbs
+			if breadcrumbs != nil && event != nil {
+				actionStr := mouseActionString(action)
+				breadcrumbs.RecordMouse(actionStr)
+			}
+
+			// Scrolling a table of a split focuses it
+			if e.table != tv {
+				e.app.SetFocus(tv)
+				if e.table != tv {
+					return tview.MouseConsumed, nil
+				}
+			}
+
+			switch action {
+			case tview.MouseScrollUp:
+				go func() {
+					reachedEnd, err := e.prevRows(1)
+					if err != nil {
+						return
+					}
+					e.app.QueueUpdateDraw(func() {
+						if !reachedEnd {
+							e.table.Select(e.table.selectedRow+1, e.table.selectedCol)
+						}
+					})
+				}()
+				return tview.MouseConsumed, nil
+			case tview.MouseScrollDown:
+				go func() {
+					reachedEnd, err := e.nextRows(1)
+					if err != nil {
+						return
+					}
+					e.app.QueueUpdateDraw(func() {
+						if !reachedEnd {
+							e.table.Select(e.table.selectedRow-1, e.table.selectedCol)
+						}
+					})
+				}()
+				return tview.MouseConsumed, nil
+			case tview.MouseScrollLeft:
+				e.table.viewport.ScrollLeft()
+				return tview.MouseConsumed, nil
+			case tview.MouseScrollRight:
+				e.table.viewport.ScrollRight()
+				return tview.MouseConsumed, nil
+			}
+			return action, event
+		},
+	})
+
+	tv.SetFocusFunc(func() {
+		e.focusPane(e.paneOf(tv))
+	})
+	tv.SetSelectionChangeFunc(func(row, col int) {
+		// A pane loaded in the background doesn't take over the status bar
+		if e.split == nil || tv.HasFocus() {
+			e.updateStatusWithCellContent()
+		}
+		// Auto-scroll viewport to show the selected column
+		e.ensureColumnVisible(col)
+		// The detail pane of a split follows the master's selected row
+		if e.split != nil && tv == e.split.master.table {
+			e.loadDetail()
+		}
+	})
+	return tv
+}
+
 func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
-	tview.Styles.ContrastBackgroundColor = tcell.ColorBlack
+	if config.Theme != nil {
+		theme = config.Theme
+	}
+	tview.Styles = theme.Widgets
 
 	db, dbType, err := config.connect()
 	if err != nil {
@@ -155,7 +350,7 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 	}
 	defer db.Close()
 
-	// Get terminal height for optimal data loading
+	// Size the first load to the terminal, the table resizes to its rect once drawn
 	terminalHeight := getTerminalHeight()
 	tableDataHeight := terminalHeight - chromeHeight // 3 lines for picker bar, status bar, command palette
 
@@ -178,20 +373,7 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 		}
 
 		displayName = sqlStatement
-
-		// Build headers in database schema order
-		headers = make([]dblib.DisplayColumn, 0, len(relation.Columns))
-		for i, col := range relation.Columns {
-			isKey := false
-			for _, keyIdx := range relation.Key {
-				if keyIdx == i {
-					isKey = true
-					break
-				}
-			}
-			editable := relation.IsColumnEditable(i)
-			headers = append(headers, dblib.DisplayColumn{Name: col.Name, Width: DefaultColumnWidth, IsKey: isKey, Editable: editable})
-		}
+		headers = buildDisplayHeaders(relation)
 	} else if tablename != "" {
 		// Load table/view if tablename is provided
 		var err error
@@ -207,26 +389,25 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 		}
 
 		displayName = tablename
-
-		// Build headers in database schema order
-		headers = make([]dblib.DisplayColumn, 0, len(relation.Columns))
-		for i, col := range relation.Columns {
-			isKey := false
-			for _, keyIdx := range relation.Key {
-				if keyIdx == i {
-					isKey = true
-					break
-				}
-			}
-			editable := relation.IsColumnEditable(i)
-			headers = append(headers, dblib.DisplayColumn{Name: col.Name, Width: DefaultColumnWidth, IsKey: isKey, Editable: editable})
-		}
+		headers = buildDisplayHeaders(relation)
 	} else {
 		// No table or SQL specified - create empty state
 		headers = []dblib.DisplayColumn{}
 		displayName = ""
 	}
 
+	if config.Goto != "" && relation == nil {
+		return fmt.Errorf("--goto needs a table or --sql")
+	}
+	if config.Where != "" {
+		if relation == nil {
+			return fmt.Errorf("--where needs a table or --sql to filter")
+		}
+		if err := relation.SetFilter(config.Where); err != nil {
+			return err
+		}
+	}
+
 	// Get available tables for the picker
 	tables, err := config.GetTables()
 	if err != nil {
@@ -238,18 +419,22 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 		dbname, databaseIcons[dbType])).EnableMouse(true)
 
 	editor := &Editor{
-		app:         app,
-		pages:       tview.NewPages(),
-		table:       nil, // Will be initialized after we have access to all editor fields
-		relation:    relation,
+		app:   app,
+		pages: tview.NewPages(),
+		pane: &pane{
+			table:    nil, // Will be initialized after we have access to all editor fields
+			relation: relation,
+			pointer:  0,
+			buffer:   nil, // Will be initialized after table creation
+		},
 		config:      config,
 		vimMode:     config.VimMode,
 		db:          db,
 		dbType:      dbType,
 		tablePicker: nil, // Will be initialized after editor is created
 		paletteMode: PaletteModeDefault,
-		pointer:     0,
-		buffer:      nil, // Will be initialized after table creation
+		dryRun:      config.DryRun,
+		pendingRows: make(map[string]Row),
 	}
 
 	// Create selector with callback now that editor exists
@@ -261,73 +446,14 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 		editor.setCursorStyle(0)         // Reset to default cursor style
 	})
 
-	editor.table = NewTableView(tableDataHeight, &TableViewConfig{
-		Headers: headers,
-		DoubleClickFunc: func(row, col int) {
-			editor.enterEditMode(row, col)
-		},
-		SingleClickFunc: func(row, col int) {
-			if editor.editing {
-				editor.exitEditMode()
-			}
-		},
-		TableNameClickFunc: func() {
-			editor.pages.ShowPage(pagePicker)
-			editor.app.SetFocus(editor.tablePicker)
-			editor.app.SetAfterDrawFunc(func(screen tcell.Screen) {
-				screen.SetCursorStyle(tcell.CursorStyleBlinkingBar)
-			})
-		},
-		MouseScrollFunc: func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
-			// Record mouse event in breadcrumbs
-			if breadcrumbs != nil && event != nil {
-				actionStr := mouseActionString(action)
-				breadcrumbs.RecordMouse(actionStr)
-			}
-
-			switch action {
-			case tview.MouseScrollUp:
-				go func() {
-					reachedEnd, err := editor.prevRows(1)
-					if err != nil {
-						return
-					}
-					editor.app.QueueUpdateDraw(func() {
-						if !reachedEnd {
-							editor.table.Select(editor.table.selectedRow+1, editor.table.selectedCol)
-						}
-					})
-				}()
-				return tview.MouseConsumed, nil
-			case tview.MouseScrollDown:
-				go func() {
-					reachedEnd, err := editor.nextRows(1)
-					if err != nil {
-						return
-					}
-					editor.app.QueueUpdateDraw(func() {
-						if !reachedEnd {
-							editor.table.Select(editor.table.selectedRow-1, editor.table.selectedCol)
-						}
-					})
-				}()
-				return tview.MouseConsumed, nil
-			case tview.MouseScrollLeft:
-				editor.table.viewport.ScrollLeft()
-				return tview.MouseConsumed, nil
-			case tview.MouseScrollRight:
-				editor.table.viewport.ScrollRight()
-				return tview.MouseConsumed, nil
-			}
-			return action, event
-		},
-	})
+	editor.table = editor.newTable(tableDataHeight, headers)
 
 	editor.table.SetTableName(displayName).SetVimMode(editor.vimMode)
 
-	// Only load data if we have a relation
-	if displayName != "" {
-		if err := editor.loadFromRowId(nil, true, 0); err != nil {
+	// Only load data if we have a relation, with the layout it was last shown with
+	if relation != nil {
+		editor.setRelation(relation, displayName)
+		if err := editor.restoreLayout(); err != nil {
 			CaptureError(err)
 			return err
 		}
@@ -335,66 +461,15 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 	editor.setupKeyBindings()
 	editor.setupStatusBar()
 	editor.setupCommandPalette()
+	if config.Goto != "" {
+		if err := editor.gotoKey(config.Goto); err != nil {
+			return fmt.Errorf("--goto: %w", err)
+		}
+	}
 
 	// Setup layout without the selector (it will be overlaid when visible)
-	editor.layout = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(editor.table, 0, 1, true).
-		AddItem(editor.statusBar, 1, 0, false).
-		AddItem(editor.commandPalette, 1, 0, false)
-
-	// TODO: unclear if this even works
-	// // Setup resize handler
-	// editor.pages.SetChangedFunc(func() {
-	// 	// Don't handle resize when in edit mode to avoid deadlock
-	// 	if editor.editing {
-	// 		return
-	// 	}
-
-	// 	// Get the new terminal height
-	// 	newHeight := getTerminalHeight()
-	// 	newDataHeight := newHeight - chromeHeight // 3 lines for picker bar, status bar, command palette
-
-	// 	// Only resize if the height has changed significantly
-	// 	if newDataHeight != editor.table.rowsHeight && newDataHeight > 0 {
-	// 		if newDataHeight > len(editor.buffer) && editor.buffer[len(editor.buffer)-1].data == nil {
-	// 			// the table is smaller than the new data height, no need to fetch more rows
-	// 			return
-	// 		}
-	// 		// Create new records buffer with the new size
-	// 		newRecords := make([]Row, newDataHeight)
-
-	// 		// Copy existing data to the new buffer
-	// 		copyCount := min(len(editor.buffer), newDataHeight)
-	// 		for i := 0; i < copyCount; i++ {
-	// 			ptr := (i + editor.pointer) % len(editor.buffer)
-	// 			newRecords[i] = editor.buffer[ptr]
-	// 		}
-
-	// 		// If the new buffer is larger, fetch more rows to fill it
-	// 		if newDataHeight > len(editor.buffer) && editor.buffer[len(editor.buffer)-1].data != nil {
-	// 			// We need to fetch more rows
-	// 			oldLen := len(editor.buffer)
-	// 			editor.buffer = newRecords
-	// 			editor.pointer = 0
-	// 			// Fetch additional rows
-	// 			go func() {
-	// 				editor.app.QueueUpdateDraw(func() { editor.nextRows(newDataHeight - oldLen) })
-	// 			}()
-	// 		} else {
-	// 			editor.buffer = newRecords
-	// 			editor.pointer = 0
-	// 		}
-
-	// 		editor.renderData()
-	// 		go editor.app.Draw()
-	// 	}
-	// })
-
-	editor.table.SetSelectionChangeFunc(func(row, col int) {
-		editor.updateStatusWithCellContent()
-		// Auto-scroll viewport to show the selected column
-		editor.ensureColumnVisible(col)
-	})
+	editor.layout = tview.NewFlex().SetDirection(tview.FlexRow)
+	editor.layoutTables()
 
 	// Add main table page
 	editor.pages.AddPage(pageTable, editor.layout, true, true)
@@ -414,10 +489,18 @@ func runEditor(config *Config, dbname, tablename, sqlStatement string) error {
 		})
 	}
 
+	if editor.dryRun {
+		editor.SetStatusMessage("Dry run · edits, inserts and deletes are collected into a script")
+	}
+
 	if err := editor.app.SetRoot(editor.pages, true).Run(); err != nil {
 		CaptureError(err)
 		return err
 	}
+	editor.saveLayouts()
+	if editor.dryRun {
+		return promptSaveScript(os.Stdin, os.Stdout, editor.dryRunScript, time.Now())
+	}
 	return nil
 }
 
@@ -427,8 +510,7 @@ func (e *Editor) setupStatusBar() {
 		SetRegions(true).
 		SetWrap(false)
 
-	e.statusBar.SetBackgroundColor(tcell.ColorLightGray)
-	e.statusBar.SetTextColor(tcell.ColorBlack)
+	e.setStatusStyle(theme.StatusBar)
 	e.statusBar.SetText("Ready")
 }
 
@@ -436,10 +518,10 @@ func (e *Editor) setupCommandPalette() {
 	inputField := tview.NewInputField()
 	e.commandPalette = inputField.
 		SetLabel("").
-		SetFieldBackgroundColor(tcell.ColorBlack).
-		SetFieldTextColor(tcell.ColorWhite)
+		SetFieldStyle(theme.Palette)
 
-	e.commandPalette.SetBackgroundColor(tcell.ColorBlack)
+	_, bg, _ := theme.Palette.Decompose()
+	e.commandPalette.SetBackgroundColor(bg)
 
 	// Default palette mode shows keybinding help
 	e.setPaletteMode(PaletteModeDefault, false)
@@ -480,6 +562,15 @@ func (e *Editor) setupCommandPalette() {
 			// Ctrl+Q: quit application
 			e.app.Stop()
 			return nil
+		case key == tcell.KeyRune && mod&tcell.ModAlt != 0 && e.paletteMode == PaletteModeFind:
+			// Alt+R: replace matches, Alt+M/C/A: find mode, ignore case, all columns
+			if rune == 'r' {
+				e.enterReplaceMode()
+				return nil
+			}
+			if e.toggleFindOption(rune) {
+				return nil
+			}
 		}
 
 		switch event.Key() {
@@ -494,9 +585,25 @@ func (e *Editor) setupCommandPalette() {
 					e.executeSQL(command)
 				}
 			case PaletteModeFind:
-				e.executeFind(command)
+				// Shift+Enter finds the previous match
+				e.executeFind(command, mod&tcell.ModShift != 0)
 			case PaletteModeDelete:
 				e.executeDelete()
+			case PaletteModeFilter:
+				e.applyFilter(command)
+			case PaletteModeGoto:
+				if err := e.gotoKey(command); err != nil {
+					e.SetStatusError(err.Error())
+					return nil
+				}
+			case PaletteModeReplace:
+				// The first Enter previews, Enter again with no new text runs it
+				if e.replacePlan != nil && command == "" {
+					e.executeReplace()
+				} else {
+					e.previewReplace(command)
+				}
+				return nil
 			}
 
 			// For Find mode, keep the palette open with text selected
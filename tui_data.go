@@ -37,9 +37,65 @@ func (e *Editor) renderData() {
 		if len(e.insertRow) > 0 && e.table.rowsHeight == rowCount {
 			ptr = (ptr + 1) % len(e.buffer)
 		}
-		normalizedRows[i] = e.buffer[ptr] // Reference to Row, not a copy
+		normalizedRows[i] = e.applyPending(e.buffer[ptr]) // Reference to Row, not a copy
+	}
+	if e.dryRun {
+		normalizedRows = e.withPendingInserts(normalizedRows)
 	}
 	e.table.SetDataReferences(normalizedRows)
+	if e.fitPending {
+		e.fitPending = false
+		for col := range e.table.GetHeaders() {
+			e.table.FitColumn(col)
+		}
+	}
+	e.updatePosition()
+}
+
+// queryColumns returns the columns to select: the shown ones and the key, in relation
+// order, with where each goes in a row. Hidden columns other than the key aren't fetched.
+func (e *Editor) queryColumns() (names []string, indexes []int) {
+	hidden := make(map[string]bool, len(e.hiddenCols))
+	for _, col := range e.hiddenCols {
+		hidden[col.Name] = true
+	}
+	for i, col := range e.relation.Columns {
+		if hidden[col.Name] && !slices.Contains(e.relation.Key, i) {
+			continue
+		}
+		names = append(names, col.Name)
+		indexes = append(indexes, i)
+	}
+	return names, indexes
+}
+
+// loadedIndex returns where the named column's value is in loaded rows, or -1 when
+// the column isn't fetched
+func (e *Editor) loadedIndex(name string) int {
+	idx, ok := e.relation.ColumnIndex[name]
+	if !ok {
+		return -1
+	}
+	for _, col := range e.hiddenCols {
+		if col.Name == name && !slices.Contains(e.relation.Key, idx) {
+			return -1
+		}
+	}
+	return idx
+}
+
+// scanRow scans the current row of a query for queryColumns into a row with a value
+// per relation column, leaving columns that weren't fetched nil
+func (e *Editor) scanRow(rows *sql.Rows, indexes []int) ([]any, error) {
+	row := make([]any, len(e.relation.Columns))
+	scanTargets := make([]any, len(indexes))
+	for i, idx := range indexes {
+		scanTargets[i] = &row[idx]
+	}
+	if err := rows.Scan(scanTargets...); err != nil {
+		return nil, err
+	}
+	return row, nil
 }
 
 // extractKeys returns a copy of the key values from a row
@@ -56,6 +112,15 @@ func (e *Editor) extractKeys(row []any) []any {
 	return keys
 }
 
+// selectedKeys returns the key values of the selected row, or nil if no row is selected
+func (e *Editor) selectedKeys() []any {
+	row, _ := e.table.GetSelection()
+	if row < 0 || row >= len(e.buffer) {
+		return nil
+	}
+	return e.extractKeys(e.buffer[(row+e.pointer)%len(e.buffer)].data)
+}
+
 // keysEqual compares two key slices for equality
 func keysEqual(k1, k2 []any) bool {
 	if len(k1) != len(k2) {
@@ -69,7 +134,8 @@ func keysEqual(k1, k2 []any) bool {
 	return true
 }
 
-// getViewportBoundaryKeys returns the key values of the first and last visible rows
+// getViewportBoundaryKeys returns the cursors of the first and last visible rows:
+// their key values, preceded by their sort values when sorted
 func (e *Editor) getViewportBoundaryKeys() (firstKeys, lastKeys []any) {
 	if len(e.buffer) == 0 {
 		return nil, nil
@@ -78,7 +144,7 @@ func (e *Editor) getViewportBoundaryKeys() (firstKeys, lastKeys []any) {
 	// Get first visible row
 	firstRow := e.buffer[e.pointer]
 	if firstRow.data != nil {
-		firstKeys = e.extractKeys(firstRow.data)
+		firstKeys = e.rowCursor(firstRow.data)
 	}
 
 	// Get last visible row (accounting for nil sentinel at end)
@@ -92,7 +158,7 @@ func (e *Editor) getViewportBoundaryKeys() (firstKeys, lastKeys []any) {
 	}
 
 	if lastRow.data != nil {
-		lastKeys = e.extractKeys(lastRow.data)
+		lastKeys = e.rowCursor(lastRow.data)
 	}
 
 	return firstKeys, lastKeys
@@ -407,11 +473,8 @@ func (e *Editor) refresh() error {
 		return nil // Nothing to refresh
 	}
 
-	// Extract key values from the row using the key column indices
-	id := make([]any, len(e.relation.Key))
-	for i, keyIdx := range e.relation.Key {
-		id[i] = e.buffer[e.pointer].data[keyIdx]
-	}
+	// Anchor at the first row's position, which holds even if the row itself is gone
+	id := e.rowCursor(e.buffer[e.pointer].data)
 
 	// Close existing query before refresh
 	e.queryMu.Lock()
@@ -425,33 +488,23 @@ func (e *Editor) refresh() error {
 	e.stopRefreshTimer()
 
 	// Prepare query
-	headers := e.table.GetHeaders()
-	selectCols := make([]string, len(headers))
-	for i, col := range headers {
-		selectCols[i] = col.Name
-	}
-
-	colCount := len(headers)
-	if colCount == 0 {
+	selectCols, selectIdx := e.queryColumns()
+	if len(e.table.GetHeaders()) == 0 {
 		return nil
 	}
 
 	// Query from current position (fromTop=true)
-	rows, err := e.relation.QueryRows(selectCols, nil, id, true, true)
+	rows, err := e.relation.QueryRows(selectCols, e.sortCols, id, true, true)
 	if err != nil {
 		return err
 	}
 
 	// Scan all rows into local currentRows
 	e.pointer = 0
-	scanTargets := make([]any, colCount)
 	var currentRows []Row
 	for rows.Next() && len(currentRows) < e.table.rowsHeight {
-		row := make([]any, colCount)
-		for j := 0; j < colCount; j++ {
-			scanTargets[j] = &row[j]
-		}
-		if err := rows.Scan(scanTargets...); err != nil {
+		row, err := e.scanRow(rows, selectIdx)
+		if err != nil {
 			rows.Close()
 			return err
 		}
@@ -488,6 +541,54 @@ func (e *Editor) refresh() error {
 	return nil
 }
 
+// resize fits the buffer to rows data rows after the table is drawn at a new height.
+// Rows are loaded again from the first one shown, or from the end when it was in
+// view and the table grew, and the selected row stays selected. A cell being edited
+// holds off resizing until the next draw after it closes.
+func (e *Editor) resize(rows int) {
+	if rows <= 0 || rows == e.table.rowsHeight || e.editing {
+		return
+	}
+	e.table.rowsHeight = rows
+	if e.relation == nil || len(e.buffer) == 0 {
+		return
+	}
+
+	row, col := e.table.GetSelection()
+	keys := e.selectedKeys()
+	first := e.buffer[e.pointer].data
+	if row >= rows {
+		// The selected row would be cut off, it becomes the last one shown
+		first = e.buffer[(e.pointer+row-rows+1)%len(e.buffer)].data
+	}
+
+	// The rows shown change but not the rows there are
+	e.keepRowCount = true
+	defer func() { e.keepRowCount = false }()
+	var err error
+	switch {
+	case first == nil:
+		err = e.loadFromRowId(nil, true, col)
+	case e.isAtBottom() && rows > len(e.buffer):
+		err = e.loadFromRowId(nil, false, col)
+	default:
+		err = e.loadFromRowId(e.rowCursor(first), true, col)
+	}
+	if err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+
+	row = min(row, rows-1)
+	for i := range e.buffer {
+		if data := e.buffer[(i+e.pointer)%len(e.buffer)].data; data != nil && keysEqual(e.extractKeys(data), keys) {
+			row = i
+			break
+		}
+	}
+	e.table.Select(row, col)
+}
+
 // id can be nil, in which case load from the top or bottom
 func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 	if e.relation == nil || e.relation.DB == nil {
@@ -495,18 +596,13 @@ func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 	}
 	// reset buffer to nil
 	e.buffer = nil
+	e.resetRowCount()
 
 	// Stop refresh timer when starting a new query
 	e.stopRefreshTimer()
 
-	headers := e.table.GetHeaders()
-	selectCols := make([]string, len(headers))
-	for i, col := range headers {
-		selectCols[i] = col.Name
-	}
-
-	colCount := len(headers)
-	if colCount == 0 {
+	selectCols, selectIdx := e.queryColumns()
+	if len(e.table.GetHeaders()) == 0 {
 		return nil
 	}
 
@@ -514,7 +610,7 @@ func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 	var err error
 	if fromTop {
 		// Load from top: use QueryRows with inclusive true, scrollDown true
-		rows, err = e.relation.QueryRows(selectCols, nil, id, true, true)
+		rows, err = e.relation.QueryRows(selectCols, e.sortCols, id, true, true)
 		if err != nil {
 			return err
 		}
@@ -526,16 +622,12 @@ func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 
 		// Scan rows into e.records starting from pointer
 		e.pointer = 0
-		scanTargets := make([]any, colCount)
 
 		// Scan all rows from database into local currentRows
 		var currentRows []Row
 		for rows.Next() && len(currentRows) < e.table.rowsHeight {
-			row := make([]any, colCount)
-			for j := 0; j < colCount; j++ {
-				scanTargets[j] = &row[j]
-			}
-			if err := rows.Scan(scanTargets...); err != nil {
+			row, err := e.scanRow(rows, selectIdx)
+			if err != nil {
 				return err
 			}
 			currentRows = append(currentRows, Row{
@@ -557,7 +649,7 @@ func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 		e.previousRows = currentRows
 	} else {
 		// Load from bottom: use QueryRows with inclusive true, scrollDown false
-		rows, err = e.relation.QueryRows(selectCols, nil, id, true, false)
+		rows, err = e.relation.QueryRows(selectCols, e.sortCols, id, true, false)
 		if err != nil {
 			return err
 		}
@@ -569,16 +661,12 @@ func (e *Editor) loadFromRowId(id []any, fromTop bool, focusColumn int) error {
 
 		// Scan rows into e.records in reverse, starting from end of buffer
 		e.pointer = 0
-		scanTargets := make([]any, colCount)
 
 		// Scan all rows from database into local currentRows
 		var currentRows []Row
 		for rows.Next() {
-			row := make([]any, colCount)
-			for j := 0; j < colCount; j++ {
-				scanTargets[j] = &row[j]
-			}
-			if err := rows.Scan(scanTargets...); err != nil {
+			row, err := e.scanRow(rows, selectIdx)
+			if err != nil {
 				return err
 			}
 			currentRows = append(currentRows, Row{
@@ -644,22 +732,13 @@ func (e *Editor) nextRows(i int) (bool, error) {
 		// Stop refresh timer when starting a new query
 		e.stopRefreshTimer()
 
-		params := make([]any, len(e.relation.Key))
 		lastRecordIdx := (e.pointer - 1 + len(e.buffer)) % len(e.buffer)
 		if e.buffer[lastRecordIdx].data == nil {
 			return false, nil // Can't query from nil record
 		}
-		for i, keyIdx := range e.relation.Key {
-			if keyIdx < len(e.buffer[lastRecordIdx].data) {
-				params[i] = e.buffer[lastRecordIdx].data[keyIdx]
-			}
-		}
-		headers := e.table.GetHeaders()
-		selectCols := make([]string, len(headers))
-		for i, col := range headers {
-			selectCols[i] = col.Name
-		}
-		newQuery, err := e.relation.QueryRows(selectCols, nil, params, false, true)
+		params := e.rowCursor(e.buffer[lastRecordIdx].data)
+		selectCols, _ := e.queryColumns()
+		newQuery, err := e.relation.QueryRows(selectCols, e.sortCols, params, false, true)
 		if err != nil {
 			return false, err
 		}
@@ -676,10 +755,10 @@ func (e *Editor) nextRows(i int) (bool, error) {
 		}
 	}
 
-	colCount := len(e.table.GetHeaders())
-	if colCount == 0 {
+	if len(e.table.GetHeaders()) == 0 {
 		return false, nil
 	}
+	_, selectIdx := e.queryColumns()
 
 	// Get a local reference to the query to avoid holding the lock during scanning
 	e.queryMu.Lock()
@@ -690,15 +769,10 @@ func (e *Editor) nextRows(i int) (bool, error) {
 		return false, fmt.Errorf("query is nil")
 	}
 
-	scanTargets := make([]any, colCount)
-
 	rowsFetched := 0
 	for ; rowsFetched < i && query.Next(); rowsFetched++ {
-		row := make([]any, colCount)
-		for j := 0; j < colCount; j++ {
-			scanTargets[j] = &row[j]
-		}
-		if err := query.Scan(scanTargets...); err != nil {
+		row, err := e.scanRow(query, selectIdx)
+		if err != nil {
 			return false, err
 		}
 		bufferIdx := (e.pointer + rowsFetched) % len(e.buffer)
@@ -759,18 +833,9 @@ func (e *Editor) prevRows(i int) (bool, error) {
 		if len(e.buffer) == 0 || e.buffer[e.pointer].data == nil {
 			return false, nil // Can't query from nil or empty records
 		}
-		params := make([]any, len(e.relation.Key))
-		for i, keyIdx := range e.relation.Key {
-			if keyIdx < len(e.buffer[e.pointer].data) {
-				params[i] = e.buffer[e.pointer].data[keyIdx]
-			}
-		}
-		headers := e.table.GetHeaders()
-		selectCols := make([]string, len(headers))
-		for i, col := range headers {
-			selectCols[i] = col.Name
-		}
-		newQuery, err := e.relation.QueryRows(selectCols, nil, params, false, false)
+		params := e.rowCursor(e.buffer[e.pointer].data)
+		selectCols, _ := e.queryColumns()
+		newQuery, err := e.relation.QueryRows(selectCols, e.sortCols, params, false, false)
 		if err != nil {
 			return false, err
 		}
@@ -789,10 +854,10 @@ func (e *Editor) prevRows(i int) (bool, error) {
 		}
 	}
 
-	colCount := len(e.table.GetHeaders())
-	if colCount == 0 {
+	if len(e.table.GetHeaders()) == 0 {
 		return false, nil
 	}
+	_, selectIdx := e.queryColumns()
 
 	// Get a local reference to the query to avoid holding the lock during scanning
 	e.queryMu.Lock()
@@ -803,15 +868,10 @@ func (e *Editor) prevRows(i int) (bool, error) {
 		return false, fmt.Errorf("query is nil")
 	}
 
-	scanTargets := make([]any, colCount)
-
 	rowsFetched := 0
 	for ; rowsFetched < i && query.Next(); rowsFetched++ {
-		row := make([]any, colCount)
-		for j := 0; j < colCount; j++ {
-			scanTargets[j] = &row[j]
-		}
-		if err := query.Scan(scanTargets...); err != nil {
+		row, err := e.scanRow(query, selectIdx)
+		if err != nil {
 			return false, err
 		}
 		e.pointer = e.lastRowIdx() // Move pointer backwards in the circular buffer
@@ -846,13 +906,11 @@ func (e *Editor) startRowsTimer() {
 	e.rowsTimerReset = make(chan struct{})
 	e.rowsTimer = time.NewTimer(RowsTimerInterval)
 
-	// Timer goroutine to handle resets
+	// Timer goroutine to handle resets. It holds on to the pane it was started for,
+	// as withPane may have swapped in another by the time the timer fires.
+	p, app := e.pane, e.app
+	resetChan, timer := e.rowsTimerReset, e.rowsTimer
 	go func() {
-		resetChan := e.rowsTimerReset
-		timer := e.rowsTimer
-		if timer == nil {
-			return
-		}
 		for {
 			select {
 			case _, ok := <-resetChan:
@@ -868,7 +926,11 @@ func (e *Editor) startRowsTimer() {
 				}
 				timer.Reset(RowsTimerInterval)
 			case <-timer.C:
-				e.stopRowsTimer()
+				app.QueueUpdate(func() {
+					if p.rowsTimer == timer {
+						e.withPane(p, e.stopRowsTimer)
+					}
+				})
 				return
 			}
 		}
@@ -910,14 +972,10 @@ func (e *Editor) startRefreshTimer() {
 	e.refreshTimerStop = make(chan struct{})
 	e.refreshTimer = time.NewTimer(RefreshTimerInterval)
 
-	// Refresh timer goroutine
+	// Refresh timer goroutine, refreshing the pane it was started for
+	p, app := e.pane, e.app
+	stopChan, timer := e.refreshTimerStop, e.refreshTimer
 	go func() {
-		stopChan := e.refreshTimerStop
-		timer := e.refreshTimer
-		app := e.app
-		if stopChan == nil || timer == nil {
-			return
-		}
 		for {
 			select {
 			case _, ok := <-stopChan:
@@ -926,16 +984,11 @@ func (e *Editor) startRefreshTimer() {
 					return
 				}
 			case <-timer.C:
-				if app != nil && e.relation != nil && e.relation.DB != nil {
-					// Calculate data height before queueing the update
-					terminalHeight := getTerminalHeight()
-					dataHeight := terminalHeight - 3 // 3 lines for picker bar, status bar, command palette
-					app.QueueUpdateDraw(func() {
-						// Update table rowsHeight before loading rows from database
-						e.table.UpdateRowsHeightFromRect(dataHeight)
-						e.refresh()
-					})
-				}
+				app.QueueUpdateDraw(func() {
+					if p.refreshTimer == timer && p.relation != nil && p.relation.DB != nil {
+						e.withPane(p, func() { e.refresh() })
+					}
+				})
 				timer.Reset(RefreshTimerInterval)
 			}
 		}
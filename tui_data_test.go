@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestResize(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	for id := 3; id <= 20; id++ {
+		if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (?, ?)`, id, fmt.Sprintf("user%d", id)); err != nil {
+			t.Fatalf("Failed to insert: %v", err)
+		}
+	}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Failed to init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 30)
+
+	// The editor acts on the size once the draw is done, here right after it
+	var rows int
+	e.table.SetResizeFunc(func(width, n int) { rows = n })
+	draw := func(height int) {
+		t.Helper()
+		e.table.SetRect(0, 0, 40, height)
+		e.table.Draw(screen)
+		e.resize(rows)
+		e.layoutDetail()
+	}
+	selected := func() any {
+		keys := e.selectedKeys()
+		if keys == nil {
+			return nil
+		}
+		return keys[0]
+	}
+
+	// Drawn at the size it was loaded for, nothing changes
+	draw(9)
+	if rows != 5 || len(e.buffer) != 5 {
+		t.Fatalf("Expected 5 rows fitting and loaded, got %d and %d", rows, len(e.buffer))
+	}
+	e.table.Select(3, 1)
+
+	// Growing fetches rows below, keeping the first row and the selection
+	draw(14)
+	if len(e.buffer) != 10 || e.buffer[e.pointer].data[0] != int64(1) {
+		t.Errorf("Expected 10 rows from id 1, got %d", len(e.buffer))
+	}
+	if row, col := e.table.GetSelection(); row != 3 || col != 1 || selected() != int64(4) {
+		t.Errorf("Expected id 4 still selected at row 3, got %v at %d, %d", selected(), row, col)
+	}
+
+	// Shrinking past the selected row drops rows above it
+	draw(6)
+	if len(e.buffer) != 2 {
+		t.Errorf("Expected 2 rows, got %d", len(e.buffer))
+	}
+	if row, _ := e.table.GetSelection(); row != 1 || selected() != int64(4) {
+		t.Errorf("Expected id 4 selected on the last row, got %v at %d", selected(), row)
+	}
+
+	// A cell being edited holds off resizing until it closes
+	e.editing = true
+	draw(9)
+	if len(e.buffer) != 2 {
+		t.Errorf("Expected no resize while editing, got %d rows", len(e.buffer))
+	}
+	e.editing = false
+	e.resize(e.table.fittingRows(e.table.drawnHeight))
+	if len(e.buffer) != 5 || selected() != int64(4) {
+		t.Errorf("Expected 5 rows with id 4 selected after editing, got %d and %v", len(e.buffer), selected())
+	}
+
+	// With the end in view, growing fetches rows above
+	if err := e.loadFromRowId(nil, false, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	e.table.Select(3, 0)
+	draw(14)
+	if len(e.buffer) != 10 || e.buffer[e.pointer].data[0] != int64(12) || !e.isAtBottom() {
+		t.Errorf("Expected ids 12 to 20 and the end, got %d rows", len(e.buffer))
+	}
+	if selected() != int64(20) {
+		t.Errorf("Expected id 20 still selected, got %v", selected())
+	}
+
+	// An open detail view follows the table's size and selected row
+	e.pages = tview.NewPages()
+	e.table.Select(0, 0)
+	e.showDetail()
+	if e.detail == nil || e.detail.width != 40 {
+		t.Fatalf("Expected the detail view open at the minimum width")
+	}
+	screen.SetSize(100, 30)
+	e.table.SetRect(0, 0, 100, 14)
+	e.table.Draw(screen)
+	e.layoutDetail()
+	if e.detail.width != 92 {
+		t.Errorf("Expected the detail view widened to 92, got %d", e.detail.width)
+	}
+	e.closeDetail()
+	if e.detail != nil {
+		t.Errorf("Expected the detail view gone once closed")
+	}
+}
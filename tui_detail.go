@@ -0,0 +1,445 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+const pageDetail = "detail"
+
+// detailField is a column of the row shown in the detail view
+type detailField struct {
+	name  string
+	typ   string
+	col   int // display column, -1 when hidden
+	index int // position of the value in each row
+	key   bool
+}
+
+// detailView shows the selected row transposed, a line per column, so wide rows
+// can be read top to bottom
+type detailView struct {
+	fields []detailField
+	row    int   // table row shown
+	lines  []int // field shown on each line of list
+	width  int   // width of the panel, values wrap to fit
+
+	list   *tview.Table
+	edit   *tview.TextArea
+	panel  *tview.Flex
+	modal  *tview.Flex // centers column and the panel in it over the table
+	column *tview.Flex
+
+	// foreign key previews of the row shown, by reference, and the referenced
+	// relations, loaded once per view
+	previews map[int]string
+	refRels  map[string]*dblib.Relation
+}
+
+// wrapLines splits text at newlines and wraps each line at width display cells
+func wrapLines(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ReplaceAll(strings.TrimSuffix(line, "\r"), "\t", "    ")
+		for runewidth.StringWidth(line) > width {
+			cut, w := 0, 0
+			for i, ch := range line {
+				if w += runewidth.RuneWidth(ch); w > width {
+					cut = i
+					break
+				}
+			}
+			if cut == 0 { // a rune wider than the line
+				_, cut = utf8.DecodeRuneInString(line)
+			}
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// detailFields lists the shown columns in display order followed by the hidden ones
+func (e *Editor) detailFields() []detailField {
+	var fields []detailField
+	add := func(h dblib.DisplayColumn, col int) {
+		field := detailField{name: h.Name, col: col, index: h.Index, key: h.IsKey}
+		if h.Index < len(e.relation.Columns) {
+			field.typ = strings.ToLower(e.relation.Columns[h.Index].Type)
+		}
+		fields = append(fields, field)
+	}
+	for i, h := range e.table.GetHeaders() {
+		add(h, i)
+	}
+	for _, h := range e.hiddenCols {
+		add(h, -1)
+	}
+	return fields
+}
+
+// referencePreview summarizes the row that the foreign key column at index points
+// at, e.g. "customers: name = Acme, city = Oslo", or "" if it isn't a foreign key
+func (e *Editor) referencePreview(d *detailView, data []any, index int) string {
+	if index >= len(e.relation.Columns) {
+		return ""
+	}
+	refIdx := e.relation.Columns[index].Reference
+	if refIdx < 0 || refIdx >= len(e.relation.References) {
+		return ""
+	}
+	if preview, ok := d.previews[refIdx]; ok {
+		return preview
+	}
+	ref := e.relation.References[refIdx]
+	preview := func() string {
+		foreignKey := make(map[string]any, len(ref.Columns))
+		for localCol, foreignCol := range ref.Columns {
+			localIdx := e.loadedIndex(localCol)
+			if localIdx < 0 || localIdx >= len(data) || data[localIdx] == nil {
+				return ""
+			}
+			foreignKey[foreignCol] = data[localIdx]
+		}
+
+		foreignRel, ok := d.refRels[ref.Table]
+		if !ok {
+			var err error
+			if foreignRel, err = dblib.NewRelation(e.db, e.dbType, ref.Table); err != nil {
+				foreignRel = nil
+			}
+			d.refRels[ref.Table] = foreignRel
+		}
+		if foreignRel == nil {
+			return ""
+		}
+
+		// The first few columns other than the referenced ones describe the row
+		var names []string
+		for _, col := range foreignRel.Columns {
+			referenced := false
+			for _, foreignCol := range ref.Columns {
+				referenced = referenced || foreignCol == col.Name
+			}
+			if !referenced && len(names) < 3 {
+				names = append(names, col.Name)
+			}
+		}
+		if len(names) == 0 {
+			return ""
+		}
+		found, err := dblib.GetForeignRow(e.db, foreignRel, foreignKey, names)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ref.Table + ": no matching row"
+		}
+		if err != nil {
+			return ""
+		}
+		values := make([]any, len(names))
+		for i, name := range names {
+			values[i] = found[name]
+		}
+		return ref.Table + ": " + keyLabel(names, values)
+	}()
+	d.previews[refIdx] = preview
+	return preview
+}
+
+// renderDetail fills the detail view with the fields of the row it shows, keeping
+// the selected field
+func (e *Editor) renderDetail(d *detailView) {
+	current := d.current()
+	if d.row < 0 || d.row >= len(e.table.data) {
+		return
+	}
+	data := e.table.data[d.row].data
+
+	nameWidth, typeWidth := 0, 0
+	for _, f := range d.fields {
+		nameWidth = max(nameWidth, runewidth.StringWidth(f.name)+2)
+		typeWidth = max(typeWidth, runewidth.StringWidth(f.typ))
+	}
+	valueWidth := max(20, d.width-nameWidth-typeWidth-6)
+
+	d.list.Clear()
+	d.lines = d.lines[:0]
+	addLine := func(field int, name, typ *tview.TableCell, value *tview.TableCell) {
+		line := len(d.lines)
+		d.list.SetCell(line, 0, name)
+		d.list.SetCell(line, 1, typ)
+		d.list.SetCell(line, 2, value.SetExpansion(1))
+		d.lines = append(d.lines, field)
+	}
+	for i, f := range d.fields {
+		name := f.name
+		if f.key {
+			name = "✦ " + name
+		}
+		nameCell := tview.NewTableCell(tview.Escape(name)).SetTextColor(theme.Field).SetAttributes(tcell.AttrBold)
+		typeCell := tview.NewTableCell(tview.Escape(f.typ)).SetStyle(theme.Muted)
+
+		if e.loadedIndex(f.name) < 0 || f.index >= len(data) {
+			addLine(i, nameCell, typeCell, tview.NewTableCell("hidden · alt+c to show").SetStyle(theme.Muted))
+			continue
+		}
+		text, style := formatCellValue(data[f.index], tcell.StyleDefault)
+		for j, line := range wrapLines(text, valueWidth) {
+			if j > 0 {
+				nameCell, typeCell = tview.NewTableCell(""), tview.NewTableCell("")
+			}
+			addLine(i, nameCell, typeCell, tview.NewTableCell(tview.Escape(line)).SetStyle(style))
+		}
+		if preview := e.referencePreview(d, data, f.index); preview != "" {
+			preview = runewidth.Truncate("→ "+preview, valueWidth, "…")
+			addLine(i, tview.NewTableCell(""), tview.NewTableCell(""), tview.NewTableCell(tview.Escape(preview)).SetTextColor(theme.Preview))
+		}
+	}
+
+	title := e.table.tableName
+	if keys := e.extractKeys(data); keys != nil {
+		title += " · " + keyLabel(e.relation.KeyNames(), keys)
+	}
+	d.panel.SetTitle(" " + tview.Escape(title) + " ")
+	d.selectField(max(0, current))
+}
+
+// current returns the index of the field on the selected line, or -1
+func (d *detailView) current() int {
+	line, _ := d.list.GetSelection()
+	if line < 0 || line >= len(d.lines) {
+		return -1
+	}
+	return d.lines[line]
+}
+
+// selectField selects the first line of field i
+func (d *detailView) selectField(i int) {
+	if line := slices.Index(d.lines, i); line >= 0 {
+		d.list.Select(line, 0)
+	}
+}
+
+// showDetail opens the detail view of the selected row
+func (e *Editor) showDetail() {
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+	row, col := e.table.GetSelection()
+	if row < 0 || row >= len(e.table.data) || e.table.data[row].data == nil {
+		return
+	}
+
+	width, _ := e.detailSize()
+	d := e.newDetailView(row, width)
+	d.selectField(col)
+
+	// Center the panel over the table
+	d.column = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(d.panel, 0, 0, true).
+		AddItem(nil, 0, 1, false)
+	d.modal = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(d.column, 0, 0, true).
+		AddItem(nil, 0, 1, false)
+	e.detail = d
+	e.layoutDetail()
+	e.pages.AddPage(pageDetail, d.modal, true, true)
+	e.app.SetFocus(d.list)
+	e.SetStatusMessage("Enter to edit · alt+↑/↓ previous/next row · Esc to close")
+}
+
+// detailSize returns the size of the detail view, a little smaller than the table
+func (e *Editor) detailSize() (width, height int) {
+	_, _, width, height = e.table.GetInnerRect()
+	if width <= 0 {
+		width, height = 80, 24
+	}
+	return max(40, width-8), max(10, height-2)
+}
+
+// layoutDetail sizes the open detail view to the table, wrapping its values again
+// if the width changed, and shows the table's selected row, which a resize may
+// have moved
+func (e *Editor) layoutDetail() {
+	d := e.detail
+	if d == nil {
+		return
+	}
+	width, height := e.detailSize()
+	row, _ := e.table.GetSelection()
+	if width != d.width || row != d.row {
+		if row != d.row {
+			clear(d.previews)
+		}
+		d.width, d.row = width, row
+		e.renderDetail(d)
+	}
+	d.modal.ResizeItem(d.column, d.width, 0)
+	d.column.ResizeItem(d.panel, height, 0)
+}
+
+// newDetailView builds the detail view of table row row, width cells wide
+func (e *Editor) newDetailView(row, width int) *detailView {
+	d := &detailView{
+		fields:   e.detailFields(),
+		row:      row,
+		width:    width,
+		previews: make(map[int]string),
+		refRels:  make(map[string]*dblib.Relation),
+	}
+	d.list = tview.NewTable().SetSelectable(true, false)
+	d.edit = tview.NewTextArea().SetWrap(true)
+	d.edit.SetBorder(true)
+	d.panel = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.list, 0, 1, true).
+		AddItem(d.edit, 0, 0, false)
+	d.panel.SetBorder(true)
+
+	d.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			e.closeDetail()
+		case event.Key() == tcell.KeyEnter:
+			e.editDetailField(d)
+		case event.Key() == tcell.KeyDown && event.Modifiers()&tcell.ModAlt != 0:
+			e.stepDetailRow(d, 1)
+		case event.Key() == tcell.KeyUp && event.Modifiers()&tcell.ModAlt != 0:
+			e.stepDetailRow(d, -1)
+		default:
+			return event
+		}
+		return nil
+	})
+	e.renderDetail(d)
+	return d
+}
+
+// closeDetail removes the detail view and returns focus to the table
+func (e *Editor) closeDetail() {
+	e.pages.RemovePage(pageDetail)
+	e.detail = nil
+	e.app.SetFocus(e.table)
+}
+
+// stepDetailRow shows the next row, or with direction -1 the previous one, moving
+// the table's selection and scrolling it along
+func (e *Editor) stepDetailRow(d *detailView, direction int) {
+	row := d.row + direction
+	switch {
+	case row >= 0 && row < len(e.table.data) && e.table.data[row].data != nil:
+	case row == len(e.buffer) && direction > 0:
+		// The next row is below the table, scroll it into view
+		reachedEnd, err := e.nextRows(1)
+		if err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		if reachedEnd {
+			// The end of the rows scrolled into view, shifting the row shown up
+			d.row--
+			_, col := e.table.GetSelection()
+			e.table.Select(d.row, col)
+			e.renderDetail(d)
+			e.SetStatusMessage("Last row")
+			return
+		}
+		row = d.row
+	case row < 0:
+		reachedEnd, err := e.prevRows(1)
+		if err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		if reachedEnd {
+			e.SetStatusMessage("First row")
+			return
+		}
+		row = d.row
+	default:
+		e.SetStatusMessage("Last row")
+		return
+	}
+
+	_, col := e.table.GetSelection()
+	e.table.Select(row, col)
+	d.row = row
+	clear(d.previews)
+	e.renderDetail(d)
+}
+
+// editDetailField edits the selected field in a text area below the fields. Enter
+// saves through the same path as editing a cell, Alt+Enter adds a line to text.
+func (e *Editor) editDetailField(d *detailView) {
+	i := d.current()
+	if i < 0 {
+		return
+	}
+	f := d.fields[i]
+	if f.col < 0 {
+		e.SetStatusError(fmt.Sprintf("Show %s to edit it", f.name))
+		return
+	}
+	if !e.relation.IsColumnEditable(f.index) {
+		e.SetStatusMessage("Column is not editable")
+		return
+	}
+	value := e.table.data[d.row].data[f.index]
+	if b, ok := value.([]byte); ok && isBinaryData(b) {
+		e.SetStatusError("Binary values can't be edited as text, use the load command")
+		return
+	}
+	text := ""
+	if value != nil {
+		text, _ = formatCellValue(value, tcell.StyleDefault)
+	}
+
+	d.edit.SetText(text, true).SetTitle(" " + tview.Escape(f.name) + " ")
+	d.edit.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			e.closeDetailEdit(d)
+			return nil
+		case tcell.KeyEnter:
+			if event.Modifiers()&tcell.ModAlt != 0 && e.isMultilineColumnType(f.col) {
+				d.edit.SetText(d.edit.GetText()+"\n", true)
+				return nil
+			}
+			e.closeDetailEdit(d)
+			e.saveDetailField(d, f, d.edit.GetText())
+			return nil
+		}
+		return event
+	})
+	d.panel.ResizeItem(d.edit, min(10, len(wrapLines(text, d.width))+2), 0)
+	e.app.SetFocus(d.edit)
+	e.SetStatusMessage("Enter to save · Alt+Enter for a new line · Esc to cancel")
+}
+
+// closeDetailEdit hides the text area and returns focus to the fields
+func (e *Editor) closeDetailEdit(d *detailView) {
+	d.panel.ResizeItem(d.edit, 0, 0)
+	e.app.SetFocus(d.list)
+	e.SetStatusMessage("Enter to edit · alt+↑/↓ previous/next row · Esc to close")
+}
+
+// saveDetailField writes text to the field like an edit in the table, then shows
+// the row again, wherever the edit moved it
+func (e *Editor) saveDetailField(d *detailView, f detailField, text string) {
+	e.table.Select(d.row, f.col)
+	e.updateCell(d.row, f.col, text)
+	d.row, _ = e.table.GetSelection()
+	clear(d.previews)
+	e.renderDetail(d)
+}
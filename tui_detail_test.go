@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestWrapLines(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"line one\nline two", 10, []string{"line one", "line two"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"東京都庁", 5, []string{"東京", "都庁"}},
+		{"", 5, []string{""}},
+	}
+	for _, tt := range tests {
+		if got := wrapLines(tt.text, tt.width); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapLines(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestDetailView(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.statusBar = nil
+
+	d := e.newDetailView(0, 60)
+	value := func(line int) string { return d.list.GetCell(line, 2).Text }
+	if len(d.lines) != 2 || value(0) != "1" || value(1) != "Alice" {
+		t.Fatalf("Expected id and name of Alice, got %d lines: %q %q", len(d.lines), value(0), value(1))
+	}
+
+	// Stepping moves the table's selection along and stops at the ends
+	e.stepDetailRow(d, 1)
+	e.stepDetailRow(d, 1)
+	if row, _ := e.table.GetSelection(); d.row != 1 || row != 1 || value(1) != "Bob" {
+		t.Errorf("Expected Bob on row 1, got %q on row %d (table row %d)", value(1), d.row, row)
+	}
+	e.stepDetailRow(d, -1)
+	if d.row != 0 || value(1) != "Alice" {
+		t.Errorf("Expected Alice on row 0, got %q on row %d", value(1), d.row)
+	}
+
+	// Edits go through the same path as editing a cell
+	d.selectField(1)
+	e.saveDetailField(d, d.fields[d.current()], "Alicia")
+	if value(1) != "Alicia" || len(e.dryRunScript) != 1 {
+		t.Errorf("Expected a pending update to Alicia, got %q and script %v", value(1), e.dryRunScript)
+	}
+}
+
+func TestDetailReferencePreview(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	if _, err := db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), note TEXT);
+		INSERT INTO orders VALUES (10, 2, 'first line
+second line');`); err != nil {
+		t.Fatalf("Failed to create orders: %v", err)
+	}
+	orders, err := dblib.NewRelation(db, dblib.SQLite, "orders")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.setRelation(orders, "orders")
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+
+	d := e.newDetailView(0, 60)
+	var values []string
+	for line := range d.lines {
+		values = append(values, d.list.GetCell(line, 2).Text)
+	}
+	want := []string{"10", "2", "→ users: name = Bob", "first line", "second line"}
+	if strings.Join(values, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %q, got %q", want, values)
+	}
+	if d.lines[2] != 1 || d.lines[4] != 2 {
+		t.Errorf("Expected the preview and the note's second line to belong to their fields, got %v", d.lines)
+	}
+}
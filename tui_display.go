@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
@@ -21,17 +22,49 @@ func (e *Editor) moveColumn(col, direction int) {
 		return
 	}
 
-	// Swap headers
+	// Swap headers; rows stay in relation order, headers say where their values are
 	headers[col], headers[newIdx] = headers[newIdx], headers[col]
 	e.table.SetHeaders(headers)
 
-	// Swap buffer data
-	for i := range e.buffer {
-		e.buffer[i].data[col], e.buffer[i].data[newIdx] = e.buffer[i].data[newIdx], e.buffer[i].data[col]
-	}
-
 	row, _ := e.table.GetSelection()
 	e.table.Select(row, col+direction)
+	e.layoutChanged()
+}
+
+// togglePin pins the column by moving it to the end of the pinned columns at the
+// left, or unpins a pinned column by moving it just after them
+func (e *Editor) togglePin(col int) {
+	headers := e.table.GetHeaders()
+	if col < 0 || col >= len(headers) {
+		return
+	}
+	pinned := e.table.GetPinned()
+	name := headers[col].Name
+	if col < pinned {
+		for ; col < pinned-1; col++ {
+			e.moveColumn(col, 1)
+		}
+		e.table.SetPinned(pinned - 1)
+		e.SetStatusMessage(fmt.Sprintf("Unpinned %s", name))
+	} else {
+		for ; col > pinned; col-- {
+			e.moveColumn(col, -1)
+		}
+		e.table.SetPinned(pinned + 1)
+		e.SetStatusMessage(fmt.Sprintf("Pinned %s", name))
+	}
+	e.renderData()
+	e.ensureColumnVisible(col)
+	e.layoutChanged()
+}
+
+// pinKeyColumns moves the key columns to the front of headers, keeping their order,
+// and returns how many there are
+func pinKeyColumns(headers []dblib.DisplayColumn) int {
+	keys := slices.DeleteFunc(slices.Clone(headers), func(h dblib.DisplayColumn) bool { return !h.IsKey })
+	others := slices.DeleteFunc(slices.Clone(headers), func(h dblib.DisplayColumn) bool { return h.IsKey })
+	copy(headers, append(keys, others...))
+	return len(keys)
 }
 
 func (e *Editor) adjustColumnWidth(col, delta int) {
@@ -44,6 +77,53 @@ func (e *Editor) adjustColumnWidth(col, delta int) {
 
 	// Update the table column width (this updates the internal headers)
 	e.table.SetColumnWidth(col, newWidth)
+	e.layoutChanged()
+}
+
+// fitColumns fits the widths of every column, or only col when it's not -1, to
+// their headers and the rows loaded
+func (e *Editor) fitColumns(col int) {
+	headers := e.table.GetHeaders()
+	if col >= len(headers) {
+		return
+	}
+	if col >= 0 {
+		e.table.FitColumn(col)
+		e.SetStatusMessage(fmt.Sprintf("Fitted %s", headers[col].Name))
+	} else {
+		for i := range headers {
+			e.table.FitColumn(i)
+		}
+		e.SetStatusMessage("Fitted all columns")
+	}
+	e.layoutChanged()
+}
+
+// toggleWrap wraps the text of col over several lines per row, or unwraps it. With
+// all, it toggles wrapping in every column instead.
+func (e *Editor) toggleWrap(col int, all bool) {
+	headers := e.table.GetHeaders()
+	if all {
+		e.table.SetWrapAll(!e.table.GetWrapAll())
+		if e.table.GetWrapAll() {
+			e.SetStatusMessage("Wrapping all columns")
+		} else {
+			e.SetStatusMessage("Wrapping only marked columns")
+		}
+		e.layoutChanged()
+		return
+	}
+	if col < 0 || col >= len(headers) {
+		return
+	}
+	wrap := !headers[col].Wrap
+	e.table.SetColumnWrap(col, wrap)
+	if wrap {
+		e.SetStatusMessage(fmt.Sprintf("Wrapping %s", headers[col].Name))
+	} else {
+		e.SetStatusMessage(fmt.Sprintf("Not wrapping %s", headers[col].Name))
+	}
+	e.layoutChanged()
 }
 
 func formatCellValue(value any, cellStyle tcell.Style) (string, tcell.Style) {
@@ -51,11 +131,14 @@ func formatCellValue(value any, cellStyle tcell.Style) (string, tcell.Style) {
 		return "", cellStyle
 	}
 	if value == nil {
-		return dblib.NullDisplay, cellStyle.Italic(true).Foreground(tcell.ColorGray)
+		return dblib.NullDisplay, over(cellStyle.Italic(true), theme.Muted)
 	}
 
 	switch v := value.(type) {
 	case []byte:
+		if isBinaryData(v) {
+			return blobSummary(v), over(cellStyle, theme.Muted)
+		}
 		return string(v), cellStyle
 	case string:
 		if v == "" {
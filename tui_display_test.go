@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestTogglePin(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.statusBar = nil
+
+	e.togglePin(1)
+	if headers := e.table.GetHeaders(); e.table.GetPinned() != 1 || headers[0].Name != "name" {
+		t.Fatalf("Expected name pinned first, got %d pinned of %+v", e.table.GetPinned(), headers)
+	}
+	if got := e.table.GetCell(0, 0); got != "Alice" {
+		t.Errorf("Expected the pinned column to show the name, got %v", got)
+	}
+
+	e.togglePin(0)
+	if headers := e.table.GetHeaders(); e.table.GetPinned() != 0 || headers[0].Name != "name" {
+		t.Errorf("Expected name unpinned in place, got %d pinned of %+v", e.table.GetPinned(), headers)
+	}
+}
+
+func TestPinKeyColumns(t *testing.T) {
+	headers := []dblib.DisplayColumn{{Name: "note"}, {Name: "tenant", IsKey: true}, {Name: "name"}, {Name: "id", IsKey: true}}
+	if n := pinKeyColumns(headers); n != 2 {
+		t.Errorf("Expected 2 key columns, got %d", n)
+	}
+	var names []string
+	for _, h := range headers {
+		names = append(names, h.Name)
+	}
+	if got := strings.Join(names, ","); got != "tenant,id,note,name" {
+		t.Errorf("Expected key columns first, got %s", got)
+	}
+}
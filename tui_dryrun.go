@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+const pageScript = "script"
+
+// pendingInsert is a row inserted during a dry run, shown at the end of its relation
+type pendingInsert struct {
+	relation string
+	data     []any
+}
+
+// pendingKey identifies a loaded row of the current relation across reloads
+func (e *Editor) pendingKey(data []any) string {
+	return e.table.tableName + "\x00" + fmt.Sprintf("%#v", e.extractKeys(data))
+}
+
+// applyPending returns row as it looks with the dry-run changes made to it
+func (e *Editor) applyPending(row Row) Row {
+	if !e.dryRun || row.data == nil || len(e.pendingRows) == 0 {
+		return row
+	}
+	if pending, ok := e.pendingRows[e.pendingKey(row.data)]; ok {
+		return pending
+	}
+	return row
+}
+
+// withPendingInserts places this relation's pending inserts ahead of the bottom
+// border, so they only show once the end of the relation is on screen
+func (e *Editor) withPendingInserts(rows []Row) []Row {
+	var inserts []Row
+	for _, p := range e.pendingInserts {
+		if p.relation == e.table.tableName {
+			inserts = append(inserts, Row{state: RowStateNew, data: p.data, pending: true})
+		}
+	}
+	if len(inserts) == 0 {
+		return rows
+	}
+	for i, r := range rows {
+		if r.data == nil && r.state != RowStateInsert {
+			// Use free screen rows when the relation is short, like the insert row
+			merged := append(append(rows[:i:i], inserts...), rows[i:]...)
+			return merged[:max(len(rows), min(len(merged), e.table.rowsHeight))]
+		}
+	}
+	return rows
+}
+
+// isPendingInsert reports whether the displayed row is a dry-run insert, which
+// has no database key yet and so can't be updated or deleted
+func (e *Editor) isPendingInsert(row int) bool {
+	return row >= 0 && row < len(e.table.data) && e.table.data[row].pending
+}
+
+// displayedRecords returns the rows on screen, with pending changes applied, in display order
+func (e *Editor) displayedRecords() [][]any {
+	records := make([][]any, len(e.table.data))
+	for i, r := range e.table.data {
+		records[i] = r.data
+	}
+	return records
+}
+
+// addToScript appends statement to the dry-run script
+func (e *Editor) addToScript(statement string) {
+	e.dryRunScript = append(e.dryRunScript, statement)
+	count := len(e.dryRunScript)
+	noun := "statements"
+	if count == 1 {
+		noun = "statement"
+	}
+	e.SetStatusMessage(fmt.Sprintf("Dry run · %d pending %s · script to view · script <file> to save", count, noun))
+}
+
+// dryRunUpdate records an UPDATE of the selected cell instead of running it
+func (e *Editor) dryRunUpdate(row, col int, newValue string) {
+	if e.isPendingInsert(row) {
+		e.SetStatusError("Pending inserts can't be edited until the script runs")
+		return
+	}
+	records := e.displayedRecords()
+	colName := e.table.GetHeaders()[col].Name
+	preview := e.relation.BuildUpdatePreview(records, row, colName, newValue)
+	if preview == "" {
+		e.SetStatusError("Cannot update row without key values")
+		return
+	}
+
+	var value any = newValue
+	if newValue == dblib.NullGlyph {
+		value = nil
+	}
+	e.setPending(e.buffer[(row+e.pointer)%len(e.buffer)], e.table.dataIndex(col), value)
+	e.addToScript(preview)
+	e.renderData()
+}
+
+// setPending shows value at index col of a loaded row as a pending change
+func (e *Editor) setPending(row Row, col int, value any) {
+	key := e.pendingKey(row.data)
+	pending := e.applyPending(row)
+	data := make([]any, len(pending.data))
+	copy(data, pending.data)
+	data[col] = value
+	var modified []int
+	if _, ok := e.pendingRows[key]; ok {
+		modified = pending.modified
+	}
+	if !slices.Contains(modified, col) {
+		modified = append(modified[:len(modified):len(modified)], col)
+	}
+	e.pendingRows[key] = Row{state: RowStateNormal, data: data, modified: modified}
+}
+
+// dryRunReplace records a find and replace's UPDATEs instead of running them. Loaded
+// rows show their new values; rows scrolled to later show their stored values.
+func (e *Editor) dryRunReplace(plan *dblib.ReplacePlan, col int) {
+	idx := e.table.dataIndex(col)
+	after := make(map[string]string, len(plan.Changes))
+	for _, change := range plan.Changes {
+		after[fmt.Sprintf("%#v", change.Keys)] = change.After
+	}
+	for _, row := range e.buffer {
+		if row.data == nil {
+			continue
+		}
+		if value, ok := after[fmt.Sprintf("%#v", e.extractKeys(row.data))]; ok {
+			e.setPending(row, idx, value)
+		}
+	}
+	for _, statement := range plan.Statements() {
+		e.addToScript(statement)
+	}
+	e.renderData()
+}
+
+// dryRunDelete records a DELETE of the selected row instead of running it
+func (e *Editor) dryRunDelete(row int) {
+	if e.isPendingInsert(row) {
+		e.SetStatusError("Pending inserts can't be deleted until the script runs")
+		return
+	}
+	preview := e.relation.BuildDeletePreview(e.displayedRecords(), row)
+	if preview == "" {
+		e.SetStatusError("Cannot delete row without key values")
+		return
+	}
+
+	ptr := (row + e.pointer) % len(e.buffer)
+	pending := e.applyPending(e.buffer[ptr])
+	e.pendingRows[e.pendingKey(e.buffer[ptr].data)] = Row{state: RowStateDeleted, data: pending.data}
+	e.addToScript(preview)
+	e.renderData()
+}
+
+// dryRunInsert records an INSERT of the insert row instead of running it
+func (e *Editor) dryRunInsert() {
+	headers := e.table.GetHeaders()
+	preview := e.relation.BuildInsertPreview(e.insertRow, headers)
+	if preview == "" {
+		e.SetStatusError("Set at least one value before inserting")
+		return
+	}
+
+	// Unset cells show what the database will fill in
+	data := make([]any, len(e.insertRow))
+	for i, v := range e.insertRow {
+		if v == dblib.EmptyCellValue {
+			if placeholder := insertPlaceholder(e.relation.Columns[i]); placeholder != "" {
+				v = placeholder
+			} else {
+				v = nil
+			}
+		}
+		data[i] = v
+	}
+	e.pendingInserts = append(e.pendingInserts, pendingInsert{relation: e.table.tableName, data: data})
+	e.ClearInsertRow()
+	e.addToScript(preview)
+	e.renderData()
+}
+
+// dryRunSQL records a SQL mode statement instead of running it
+func (e *Editor) dryRunSQL(query string) {
+	e.addToScript(strings.TrimRight(strings.TrimSpace(query), "; \t\n\r"))
+}
+
+// writeScript writes statements as a SQL script, one statement per line
+func writeScript(w io.Writer, statements []string) error {
+	for _, statement := range statements {
+		if _, err := fmt.Fprintf(w, "%s;\n", strings.TrimRight(statement, "; \t\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveScript writes the dry-run script to path
+func saveScript(path string, statements []string) error {
+	f, err := os.OpenFile(expandHome(path), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeScript(f, statements); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// saveScriptCommand handles the script <file> command
+func (e *Editor) saveScriptCommand(path string) {
+	if len(e.dryRunScript) == 0 {
+		e.SetStatusMessage("No pending statements")
+		return
+	}
+	if err := saveScript(path, e.dryRunScript); err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	e.SetStatusMessage(fmt.Sprintf("Saved %d statements to %s", len(e.dryRunScript), path))
+}
+
+// showScript opens a scrollable view of the pending statements
+func (e *Editor) showScript() {
+	if !e.dryRun {
+		e.SetStatusMessage("Not in dry-run mode (start ted with --dry-run)")
+		return
+	}
+	if len(e.dryRunScript) == 0 {
+		e.SetStatusMessage("No pending statements")
+		return
+	}
+
+	var b strings.Builder
+	writeScript(&b, e.dryRunScript)
+	view := tview.NewTextView().
+		SetDynamicColors(false).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(b.String())
+	view.SetBorder(true).SetTitle(fmt.Sprintf(" Dry-run script · %d statements ", len(e.dryRunScript)))
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter || event.Rune() == 'q' {
+			e.pages.RemovePage(pageScript)
+			e.app.SetFocus(e.table)
+			return nil
+		}
+		return event
+	})
+
+	modal := tview.NewFlex().
+		AddItem(nil, 2, 0, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 1, 0, false).
+			AddItem(view, 0, 1, true).
+			AddItem(nil, 1, 0, false), 0, 1, true).
+		AddItem(nil, 2, 0, false)
+	e.pages.AddPage(pageScript, modal, true, true)
+	e.app.SetFocus(view)
+	e.SetStatusMessage("Esc to close · script <file> to save")
+}
+
+// promptSaveScript asks on the terminal where to save pending statements after the editor exits
+func promptSaveScript(in io.Reader, out io.Writer, statements []string, now time.Time) error {
+	if len(statements) == 0 {
+		return nil
+	}
+	defaultPath := fmt.Sprintf("ted-dry-run-%s.sql", now.Format("20060102-150405"))
+	fmt.Fprintf(out, "Dry run left %d pending statements.\n", len(statements))
+	fmt.Fprintf(out, "Save script to [%s] (n to discard): ", defaultPath)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	answer = strings.TrimSpace(answer)
+	switch strings.ToLower(answer) {
+	case "n", "no":
+		fmt.Fprintln(out, "Script discarded.")
+		return nil
+	case "":
+		answer = defaultPath
+	}
+	if err := saveScript(answer, statements); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved %d statements to %s\n", len(statements), answer)
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+func newDryRunEditor(t *testing.T) (*Editor, *sql.DB) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // layouts are saved as columns change
+	t.Setenv("XDG_STATE_HOME", t.TempDir())  // writes are journaled
+	// A file, as an open scroll query holds its connection and :memory: is per connection
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob');`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	relation, err := dblib.NewRelation(db, dblib.SQLite, "users")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+
+	e := &Editor{
+		app:         tview.NewApplication(),
+		pane:        &pane{},
+		config:      &Config{Database: ":memory:"},
+		db:          db,
+		dbType:      dblib.SQLite,
+		dryRun:      true,
+		pendingRows: make(map[string]Row),
+		background:  func(work func() func()) { work()() }, // counts rows in place
+	}
+	// Timers started by loading rows are stopped before the database closes
+	t.Cleanup(func() {
+		e.stopRowsTimer()
+		e.stopRefreshTimer()
+	})
+	e.table = NewTableView(5, &TableViewConfig{})
+	e.setRelation(relation, "users")
+	e.buffer = []Row{
+		{data: []any{int64(1), "Alice"}},
+		{data: []any{int64(2), "Bob"}},
+		BottomBorderRow,
+	}
+	e.renderData()
+	return e, db
+}
+
+func TestDryRunCollectsScript(t *testing.T) {
+	e, db := newDryRunEditor(t)
+
+	e.dryRunUpdate(0, 1, "Alicia")
+	e.dryRunUpdate(0, 0, "10")
+	e.dryRunDelete(1)
+	e.insertRow = []any{dblib.EmptyCellValue, "Carol"}
+	e.dryRunInsert()
+
+	want := []string{
+		"UPDATE users SET name = 'Alicia' WHERE id = 1 RETURNING id, name",
+		"UPDATE users SET id = 10 WHERE id = 1 RETURNING id, name",
+		"DELETE FROM users WHERE id = 2",
+		"INSERT INTO users (name) VALUES ('Carol') RETURNING id, name",
+	}
+	if len(e.dryRunScript) != len(want) {
+		t.Fatalf("Expected %d statements, got %v", len(want), e.dryRunScript)
+	}
+	for i := range want {
+		if e.dryRunScript[i] != want[i] {
+			t.Errorf("Statement %d = %q, want %q", i, e.dryRunScript[i], want[i])
+		}
+	}
+
+	// Nothing reaches the database
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM users WHERE (id = 1 AND name = 'Alice') OR id = 2`).Scan(&count); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected the table to be unchanged, %d original rows remain", count)
+	}
+
+	// Pending changes are shown in the grid
+	rows := e.table.data
+	if rows[0].data[0] != "10" || rows[0].data[1] != "Alicia" || len(rows[0].modified) != 2 {
+		t.Errorf("Expected updated row, got %+v", rows[0])
+	}
+	if rows[1].state != RowStateDeleted {
+		t.Errorf("Expected deleted row, got state %v", rows[1].state)
+	}
+	if rows[2].state != RowStateNew || rows[2].data[1] != "Carol" || !e.isPendingInsert(2) {
+		t.Errorf("Expected pending insert, got %+v", rows[2])
+	}
+	if rows[3].data != nil {
+		t.Errorf("Expected bottom border after pending insert, got %+v", rows[3])
+	}
+
+	// A copy of the pending insert's values is still one, a loaded row with them isn't
+	rows[2].data = slices.Clone(rows[2].data)
+	if !e.isPendingInsert(2) {
+		t.Errorf("Expected the copied row still a pending insert")
+	}
+	if e.isPendingInsert(0) {
+		t.Errorf("Expected a loaded row not to be a pending insert")
+	}
+}
+
+func TestPromptSaveScript(t *testing.T) {
+	statements := []string{"DELETE FROM users WHERE id = 2", "UPDATE users SET name = 'x' WHERE id = 1;"}
+	path := filepath.Join(t.TempDir(), "out.sql")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var out strings.Builder
+	if err := promptSaveScript(strings.NewReader(path+"\n"), &out, statements, now); err != nil {
+		t.Fatalf("promptSaveScript failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "ted-dry-run-20250102-030405.sql") {
+		t.Errorf("Expected default file name in prompt, got %q", out.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read script: %v", err)
+	}
+	want := "DELETE FROM users WHERE id = 2;\nUPDATE users SET name = 'x' WHERE id = 1;\n"
+	if string(data) != want {
+		t.Errorf("Script = %q, want %q", data, want)
+	}
+
+	out.Reset()
+	if err := promptSaveScript(strings.NewReader("n\n"), &out, statements, now); err != nil {
+		t.Fatalf("promptSaveScript failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "discarded") {
+		t.Errorf("Expected script to be discarded, got %q", out.String())
+	}
+}
@@ -11,8 +11,8 @@ import (
 )
 
 func (e *Editor) enterEditMode(row, col int) {
-	// Check if column is editable (for views)
-	if e.relation != nil && e.relation.IsView {
+	// Check if column is editable (views, generated columns)
+	if e.relation != nil {
 		colIdx, ok := e.relation.ColumnIndex[e.table.GetHeaders()[col].Name]
 		if !ok || !e.relation.IsColumnEditable(colIdx) {
 			e.SetStatusMessage("Column is not editable")
@@ -22,10 +22,15 @@ func (e *Editor) enterEditMode(row, col int) {
 
 	var currentValue any
 	if len(e.insertRow) > 0 {
-		currentValue = e.insertRow[col]
+		currentValue = e.insertRow[e.table.dataIndex(col)]
 	} else {
 		currentValue = e.table.GetCell(row, col)
 	}
+	// Binary values can't be edited as text; show them instead
+	if b, ok := currentValue.([]byte); ok && isBinaryData(b) {
+		e.showHexDump(row, col)
+		return
+	}
 	currentText := ""
 	if currentValue != nil {
 		currentText, _ = formatCellValue(currentValue, tcell.StyleDefault)
@@ -72,14 +77,22 @@ func (e *Editor) enterEditModeWithInitialValue(row, col int, initialText string)
 		if (rune == 's' || rune == 19) && mod&tcell.ModCtrl != 0 {
 			if len(e.insertRow) > 0 {
 				// Save current cell first
-				newText := textArea.GetText()
-				e.insertRow[col] = newText
+				e.setInsertValue(col, textArea.GetText())
 				e.exitEditMode()
 				e.executeInsert()
 				return nil
 			}
 		}
 
+		// Alt+E: save an empty string in insert mode
+		if key == tcell.KeyRune && rune == 'e' && mod&tcell.ModAlt != 0 && len(e.insertRow) > 0 {
+			if e.setInsertEmpty(col) {
+				e.exitEditMode()
+				e.renderData()
+			}
+			return nil
+		}
+
 		switch key {
 		case tcell.KeyEnter:
 			// Check if Alt/Option is pressed with Enter
@@ -151,8 +164,8 @@ func (e *Editor) enterEditModeWithInitialValue(row, col int, initialText string)
 // selectAll=true: select all text (for vim 'i' mode)
 // selectAll=false: cursor at end (for vim 'a' mode)
 func (e *Editor) enterEditModeWithSelection(row, col int, selectAll bool) {
-	// Check if column is editable (for views)
-	if e.relation != nil && e.relation.IsView {
+	// Check if column is editable (views, generated columns)
+	if e.relation != nil {
 		colIdx, ok := e.relation.ColumnIndex[e.table.GetHeaders()[col].Name]
 		if !ok || !e.relation.IsColumnEditable(colIdx) {
 			e.SetStatusMessage("Column is not editable")
@@ -162,7 +175,7 @@ func (e *Editor) enterEditModeWithSelection(row, col int, selectAll bool) {
 
 	var currentValue any
 	if len(e.insertRow) > 0 {
-		currentValue = e.insertRow[col]
+		currentValue = e.insertRow[e.table.dataIndex(col)]
 	} else {
 		currentValue = e.table.GetCell(row, col)
 	}
@@ -210,8 +223,7 @@ func (e *Editor) enterEditModeWithSelection(row, col int, selectAll bool) {
 		if (rune == 's' || rune == 19) && mod&tcell.ModCtrl != 0 {
 			if len(e.insertRow) > 0 {
 				// Save current cell first
-				newText := textArea.GetText()
-				e.insertRow[col] = newText
+				e.setInsertValue(col, textArea.GetText())
 				e.exitEditMode()
 				e.executeInsert()
 				return nil
@@ -305,8 +317,9 @@ func (e *Editor) createCellEditOverlay(textArea *tview.TextArea, row, col int,
 	// Calculate the position where the cell content appears on screen
 	// Layout structure: picker bar (row 0) + table at row 1
 	// TableView structure: top border (row 0) + header (row 1) + separator (row 2) + data rows (3+)
-	tableRow := row + 3       // Convert data row to table display row
-	screenRow := tableRow + 1 // Offset by 1 for picker bar
+	// Rows above may take several lines when text wraps
+	tableRow := max(e.table.RowLine(row), 0) + 3 // Convert data row to table display row
+	screenRow := tableRow + 1                    // Offset by 1 for picker bar
 
 	// Calculate horizontal position: left border + previous columns + cell padding
 	leftOffset := 1 // Left table border "│"
@@ -316,8 +329,10 @@ func (e *Editor) createCellEditOverlay(textArea *tview.TextArea, row, col int,
 	leftOffset += 1 // Cell padding (space after "│ ")
 	leftOffset -= 1 // Move overlay one position to the left
 
-	// Account for viewport horizontal scrolling
-	leftOffset -= e.table.viewport.GetScrollX()
+	// Account for viewport horizontal scrolling, which leaves pinned columns in place
+	if leftOffset >= e.table.viewport.frozen {
+		leftOffset -= e.table.viewport.GetScrollX()
+	}
 
 	// Calculate vertical position relative to screen (accounting for picker bar offset)
 	topOffset := screenRow
@@ -381,6 +396,8 @@ func (e *Editor) exitEditMode() {
 		e.setCursorStyle(0)         // Reset to default cursor style
 		e.app.SetFocus(e.table)
 		e.editing = false
+		// Catch up with a resize held off while editing
+		e.resize(e.table.fittingRows(e.table.drawnHeight))
 		// Return palette to default mode after editing
 		if e.insertRow == nil {
 			e.setPaletteMode(PaletteModeDefault, false)
@@ -418,7 +435,7 @@ func (e *Editor) updateEditPreview(newText string) {
 		e.setPaletteMode(PaletteModeInsert, false)
 		newRecordRow := make([]any, len(e.insertRow))
 		copy(newRecordRow, e.insertRow)
-		newRecordRow[col] = newText
+		newRecordRow[col] = e.insertValue(col, newText)
 		preview = e.relation.BuildInsertPreview(newRecordRow, e.table.GetHeaders())
 	} else {
 		// Show UPDATE preview and set palette mode to Update
@@ -444,18 +461,19 @@ func (e *Editor) updateCell(row, col int, newValue string) {
 	isNewRecordRow := len(e.insertRow) > 0
 
 	if isNewRecordRow {
-		// Update the insert mode row with the new value
-		if newValue == dblib.NullGlyph {
-			e.insertRow[col] = nil
-		} else {
-			e.insertRow[col] = newValue
-		}
+		e.setInsertValue(col, newValue)
 		e.exitEditMode()
 		return
 	}
 
 	// Basic bounds check against current data
-	if row < 0 || row >= len(e.buffer) || col < 0 || col >= len(e.buffer[row].data) {
+	if row < 0 || row >= len(e.buffer) || col < 0 || col >= len(e.table.GetHeaders()) {
+		e.exitEditMode()
+		return
+	}
+
+	if e.dryRun {
+		e.dryRunUpdate(row, col, newValue)
 		e.exitEditMode()
 		return
 	}
@@ -466,20 +484,20 @@ func (e *Editor) updateCell(row, col int, newValue string) {
 	copy(oldRow, e.buffer[ptr].data)
 
 	// Delegate DB work to database.go
-	// Convert records to [][]any for UpdateDBValue
+	// Convert records to [][]any for UpdateDBValue, which looks the row up in the buffer
 	recordsData := make([][]any, len(e.buffer))
 	for i := range e.buffer {
 		recordsData[i] = e.buffer[i].data
 	}
-	updated, err := e.relation.UpdateDBValue(recordsData, row, e.table.GetHeaders()[col].Name, newValue)
+	updated, err := e.relation.UpdateDBValue(recordsData, ptr, e.table.GetHeaders()[col].Name, newValue)
 	if err != nil {
 		e.exitEditMode()
+		e.SetStatusError(err.Error())
 		return
 	}
 
 	// Check if key or sort column values changed
-	// Note: sortCol is currently nil in most cases, but we handle it for future support
-	if e.hasKeyOrSortChanged(oldRow, updated, nil) {
+	if e.hasKeyOrSortChanged(oldRow, updated, e.sortCols) {
 		// Extract updated key values
 		updatedKeys := e.extractKeys(updated)
 		if updatedKeys == nil {
@@ -490,10 +508,11 @@ func (e *Editor) updateCell(row, col int, newValue string) {
 			return
 		}
 
-		// First, scan current buffer to see if the updated row is still visible
+		// First, scan current buffer to see if the updated row is still visible.
+		// A changed sort value can move the row, so only its position in the database counts.
 		foundInBuffer := false
 		foundRow := -1
-		for i := 0; i < len(e.buffer); i++ {
+		for i := 0; i < len(e.buffer) && !e.hasSortChanged(oldRow, updated, e.sortCols); i++ {
 			bufIdx := (e.pointer + i) % len(e.buffer)
 			if e.buffer[bufIdx].data == nil {
 				break // Hit nil sentinel, stop scanning
@@ -535,7 +554,7 @@ func (e *Editor) updateCell(row, col int, newValue string) {
 		}
 
 		// Query database to compare row positions
-		isAbove, isBelow, err := dblib.CompareRowPosition(e.db, e.relation, nil, updatedKeys, firstKeys, lastKeys)
+		isAbove, isBelow, err := dblib.CompareRowPosition(e.db, e.relation, e.sortCols, updatedKeys, firstKeys, lastKeys)
 		if err != nil {
 			// On error, fall back to loading from bottom
 			e.SetStatusErrorWithSentry(err)
@@ -570,6 +589,21 @@ func (e *Editor) updateCell(row, col int, newValue string) {
 			}
 			e.exitEditMode()
 			return
+		} else if len(e.sortCols) > 0 {
+			// Row moved within the viewport, reload it in place
+			if err := e.loadFromRowId(firstKeys, true, col); err != nil {
+				e.SetStatusErrorWithSentry(err)
+				e.exitEditMode()
+				return
+			}
+			for i := 0; i < len(e.buffer); i++ {
+				if e.buffer[i].data != nil && keysEqual(e.extractKeys(e.buffer[i].data), updatedKeys) {
+					e.table.Select(i, col)
+					break
+				}
+			}
+			e.exitEditMode()
+			return
 		} else {
 			// Both false or both true - row might be in viewport after all
 			// Load from bottom as fallback
@@ -600,6 +634,10 @@ func (e *Editor) executeInsert() error {
 	if len(e.insertRow) == 0 {
 		return fmt.Errorf("no new record to insert")
 	}
+	if e.dryRun {
+		e.dryRunInsert()
+		return nil
+	}
 
 	// Execute the insert
 	insertedRow, err := e.relation.InsertDBRecord(e.insertRow)
@@ -637,11 +675,28 @@ func (e *Editor) executeInsert() error {
 	} else {
 		e.table.Select(len(e.buffer)-1, e.table.selectedCol)
 	}
+
+	// Rows inserted through a view, query or filter only show up if they match it
+	if e.relation.IsView || e.relation.IsCustomSQL || e.relation.HasFilter() {
+		visible := false
+		for _, r := range e.buffer {
+			if r.data != nil && keysEqual(e.extractKeys(r.data), keyVals) {
+				visible = true
+				break
+			}
+		}
+		if !visible && e.relation.HasFilter() {
+			e.SetStatusMessage("Record inserted but does not match the current filter")
+		} else if !visible {
+			table, _ := e.relation.WritableBaseTable()
+			e.SetStatusMessage(fmt.Sprintf("Record inserted into %s but does not match this view", table))
+		}
+	}
 	return nil
 }
 
 // hasKeyOrSortChanged checks if any key columns or sort column values changed
-func (e *Editor) hasKeyOrSortChanged(oldRow, newRow []any, sortCol *dblib.SortColumn) bool {
+func (e *Editor) hasKeyOrSortChanged(oldRow, newRow []any, sortCols []dblib.SortColumn) bool {
 	if oldRow == nil || newRow == nil {
 		return false
 	}
@@ -655,8 +710,13 @@ func (e *Editor) hasKeyOrSortChanged(oldRow, newRow []any, sortCol *dblib.SortCo
 		}
 	}
 
-	// Check if sort column value changed (if sorting is applied)
-	if sortCol != nil {
+	// Check if sort column values changed (if sorting is applied)
+	return e.hasSortChanged(oldRow, newRow, sortCols)
+}
+
+// hasSortChanged checks if any sort column values changed
+func (e *Editor) hasSortChanged(oldRow, newRow []any, sortCols []dblib.SortColumn) bool {
+	for _, sortCol := range sortCols {
 		sortIdx, ok := e.relation.ColumnIndex[sortCol.Name]
 		if ok && sortIdx < len(oldRow) && sortIdx < len(newRow) {
 			if oldRow[sortIdx] != newRow[sortIdx] {
@@ -664,7 +724,6 @@ func (e *Editor) hasKeyOrSortChanged(oldRow, newRow []any, sortCol *dblib.SortCo
 			}
 		}
 	}
-
 	return false
 }
 
@@ -689,15 +748,69 @@ func (e *Editor) isMultilineColumnType(col int) bool {
 		attrType == "json" // json but not jsonb
 }
 
+// canWriteRows reports whether rows can be inserted into or deleted from the
+// current relation, explaining why not in the status bar
+func (e *Editor) canWriteRows() bool {
+	if e.relation == nil {
+		return false
+	}
+	if _, err := e.relation.WritableBaseTable(); err != nil {
+		e.SetStatusError(err.Error())
+		return false
+	}
+	return true
+}
+
 func (e *Editor) SetupInsertRow() {
 	if e.relation == nil {
 		return
 	}
-	headers := e.table.GetHeaders()
-	e.insertRow = make([]any, len(headers))
+	// A value per relation column, hidden ones included and left unset
+	e.insertRow = make([]any, len(e.relation.Columns))
 	for i := range e.insertRow {
 		e.insertRow[i] = dblib.EmptyCellValue
 	}
+	// Rows added in the detail pane of a split reference the master's row
+	for _, link := range e.relation.Link {
+		if i, ok := e.relation.ColumnIndex[link.Column]; ok {
+			e.insertRow[i] = link.Value
+		}
+	}
+}
+
+// insertValue converts edited text into an insert row value. NullGlyph is an
+// explicit NULL; an empty value leaves the column unset (DEFAULT) unless it was
+// already set on a text column, where it is kept as an empty string. Alt+E sets
+// an empty string directly.
+func (e *Editor) insertValue(col int, text string) any {
+	switch {
+	case text == dblib.NullGlyph:
+		return nil
+	case text == "" && (e.insertRow[e.table.dataIndex(col)] == dblib.EmptyCellValue || !e.isMultilineColumnType(col)):
+		return dblib.EmptyCellValue
+	default:
+		return text
+	}
+}
+
+// setInsertValue stores edited text in the insert row
+func (e *Editor) setInsertValue(col int, text string) {
+	idx := e.table.dataIndex(col)
+	if idx < 0 || idx >= len(e.insertRow) {
+		return
+	}
+	e.insertRow[idx] = e.insertValue(col, text)
+}
+
+// setInsertEmpty sets a text column of the insert row to an empty string, which an
+// empty edit leaves unset instead. It reports whether the column takes text.
+func (e *Editor) setInsertEmpty(col int) bool {
+	idx := e.table.dataIndex(col)
+	if idx < 0 || idx >= len(e.insertRow) || !e.isMultilineColumnType(col) {
+		return false
+	}
+	e.insertRow[idx] = ""
+	return true
 }
 
 func (e *Editor) ClearInsertRow() {
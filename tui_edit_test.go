@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestInsertEmptyText(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	e.dryRun = false
+	if _, err := db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL DEFAULT 'untitled', n INTEGER)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	notes, err := dblib.NewRelation(db, dblib.SQLite, "notes")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.setRelation(notes, "notes")
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	e.stopRowsTimer()
+
+	// An empty edit leaves body to its default, Alt+E makes it empty text
+	e.SetupInsertRow()
+	body := e.headerIndex("body")
+	e.setInsertValue(body, "")
+	if e.insertRow[body] != dblib.EmptyCellValue {
+		t.Fatalf("Expected an empty edit to leave body unset, got %q", e.insertRow[body])
+	}
+	if e.setInsertEmpty(e.headerIndex("n")) {
+		t.Error("Expected empty text to be refused for an integer column")
+	}
+	if !e.setInsertEmpty(body) {
+		t.Fatal("Expected empty text to be set for body")
+	}
+	if err := e.executeInsert(); err != nil {
+		t.Fatalf("executeInsert failed: %v", err)
+	}
+
+	var got string
+	if err := db.QueryRow(`SELECT body FROM notes`).Scan(&got); err != nil {
+		t.Fatalf("Failed to read the inserted row: %v", err)
+	}
+	if got != "" {
+		t.Errorf("Expected an empty body, got %q", got)
+	}
+}
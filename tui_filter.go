@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+
+	"ted/internal/dblib"
+)
+
+// quickFilterLabelWidth caps how much of a value a quick filter chip shows
+const quickFilterLabelWidth = 24
+
+// enterFilterMode opens the palette to edit the current relation's row filter
+func (e *Editor) enterFilterMode() {
+	if e.relation == nil {
+		e.SetStatusError("No relation to filter")
+		return
+	}
+	e.setPaletteMode(PaletteModeFilter, true)
+	e.commandPalette.SetText(e.relation.Filter)
+}
+
+// quickFilterLabel describes a quick filter for its header chip
+func quickFilterLabel(qf dblib.QuickFilter) string {
+	switch {
+	case qf.Value == nil && qf.Exclude:
+		return qf.Column + " IS NOT NULL"
+	case qf.Value == nil:
+		return qf.Column + " IS NULL"
+	}
+	text, _ := formatCellValue(qf.Value, tcell.StyleDefault)
+	if runes := []rune(text); len(runes) > quickFilterLabelWidth {
+		text = string(runes[:quickFilterLabelWidth-1]) + "…"
+	}
+	if qf.Exclude {
+		return qf.Column + " ≠ " + text
+	}
+	return qf.Column + " = " + text
+}
+
+// showFilter shows the relation's filter and quick filters in the table header
+func (e *Editor) showFilter() {
+	var chips []string
+	for _, qf := range e.relation.QuickFilters {
+		chips = append(chips, quickFilterLabel(qf))
+	}
+	e.table.SetFilter(e.relation.Filter).SetChips(chips)
+}
+
+// canFilter reports whether the rows can be reloaded with a different filter
+func (e *Editor) canFilter() bool {
+	if e.relation == nil {
+		e.SetStatusError("No relation to filter")
+		return false
+	}
+	if e.editing || len(e.insertRow) > 0 || e.paletteMode == PaletteModeDelete {
+		e.SetStatusError("Finish editing before filtering")
+		return false
+	}
+	return true
+}
+
+// reloadFiltered loads the rows matching the changed filter from the top. If the
+// database rejects the filter, undo reverts it and the view at loc is restored.
+func (e *Editor) reloadFiltered(loc navLocation, undo func()) bool {
+	col := max(0, e.headerIndex(loc.column))
+	if err := e.loadFromRowId(nil, true, col); err != nil {
+		undo()
+		if reloadErr := e.restoreLocation(loc); reloadErr != nil {
+			e.SetStatusErrorWithSentry(reloadErr)
+			return false
+		}
+		e.SetStatusError(fmt.Sprintf("invalid filter: %v", err))
+		return false
+	}
+	e.showFilter()
+	e.table.Select(0, col)
+	e.layoutChanged()
+	return true
+}
+
+// applyFilter restricts the rows shown to those matching the SQL predicate, or shows
+// every row when it is empty, starting from the top. An invalid predicate leaves the
+// current rows in place.
+func (e *Editor) applyFilter(filter string) {
+	if !e.canFilter() {
+		return
+	}
+
+	previous := e.relation.Filter
+	if err := e.relation.SetFilter(filter); err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	if !e.reloadFiltered(e.currentLocation(), func() { e.relation.Filter = previous }) {
+		return
+	}
+
+	if e.relation.Filter == "" {
+		e.SetStatusMessage("Filter cleared")
+	} else {
+		e.SetStatusMessage("Filtered WHERE " + e.relation.Filter)
+	}
+}
+
+// addQuickFilter shows only the rows with the selected cell's value in its column,
+// or with exclude, hides them. Quick filters stack on top of the WHERE filter.
+func (e *Editor) addQuickFilter(row, col int, exclude bool) {
+	if !e.canFilter() {
+		return
+	}
+	headers := e.table.GetHeaders()
+	if row < 0 || row >= len(e.buffer) || col < 0 || col >= len(headers) {
+		return
+	}
+	data := e.buffer[(row+e.pointer)%len(e.buffer)].data
+	idx := e.table.dataIndex(col)
+	if idx >= len(data) {
+		return
+	}
+
+	qf := dblib.QuickFilter{Column: headers[col].Name, Value: data[idx], Exclude: exclude}
+	previous := e.relation.QuickFilters
+	e.relation.QuickFilters = append(previous[:len(previous):len(previous)], qf)
+	if !e.reloadFiltered(e.currentLocation(), func() { e.relation.QuickFilters = previous }) {
+		return
+	}
+	e.SetStatusMessage(fmt.Sprintf("Filtered %s · alt+backspace to undo", quickFilterLabel(qf)))
+}
+
+// removeQuickFilter drops the quick filter at index i, or the most recent one when i is -1
+func (e *Editor) removeQuickFilter(i int) {
+	if !e.canFilter() {
+		return
+	}
+	previous := e.relation.QuickFilters
+	if len(previous) == 0 {
+		e.SetStatusMessage("No quick filters")
+		return
+	}
+	if i < 0 {
+		i = len(previous) - 1
+	}
+	if i >= len(previous) {
+		e.SetStatusError(fmt.Sprintf("No quick filter %d", i+1))
+		return
+	}
+
+	removed := previous[i]
+	e.relation.QuickFilters = append(previous[:i:i], previous[i+1:]...)
+	if !e.reloadFiltered(e.currentLocation(), func() { e.relation.QuickFilters = previous }) {
+		return
+	}
+	e.SetStatusMessage("Removed filter " + quickFilterLabel(removed))
+}
+
+// clearFilters drops the WHERE filter and every quick filter
+func (e *Editor) clearFilters() {
+	if !e.canFilter() {
+		return
+	}
+	previousFilter, previousQuick := e.relation.Filter, e.relation.QuickFilters
+	e.relation.Filter, e.relation.QuickFilters = "", nil
+	if !e.reloadFiltered(e.currentLocation(), func() {
+		e.relation.Filter, e.relation.QuickFilters = previousFilter, previousQuick
+	}) {
+		return
+	}
+	e.SetStatusMessage("Filters cleared")
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestApplyFilter(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.dryRun = false
+	e.statusBar = nil
+
+	e.applyFilter("name = 'Bob'")
+	if e.relation.Filter != "name = 'Bob'" || e.table.filter != e.relation.Filter {
+		t.Fatalf("Expected filter to be applied, got relation %q, header %q", e.relation.Filter, e.table.filter)
+	}
+	if len(e.buffer) < 2 || e.buffer[0].data[1] != "Bob" || e.buffer[1].data != nil {
+		t.Errorf("Expected only Bob's row, got %+v", e.buffer)
+	}
+
+	// An invalid predicate keeps the filtered rows on screen
+	e.applyFilter("no_such_column = 1")
+	if e.relation.Filter != "name = 'Bob'" {
+		t.Errorf("Expected filter to be kept, got %q", e.relation.Filter)
+	}
+	if e.buffer[0].data[1] != "Bob" {
+		t.Errorf("Expected rows to be kept, got %+v", e.buffer)
+	}
+
+	e.applyFilter("")
+	if e.relation.Filter != "" || e.table.filter != "" {
+		t.Errorf("Expected filter to be cleared, got %q", e.relation.Filter)
+	}
+	if e.buffer[0].data[1] != "Alice" || e.buffer[1].data[1] != "Bob" {
+		t.Errorf("Expected all rows after clearing, got %+v", e.buffer)
+	}
+}
+
+func TestQuickFilters(t *testing.T) {
+	e, _ := newDryRunEditor(t)
+	e.dryRun = false
+	e.statusBar = nil
+
+	e.addQuickFilter(1, 1, false)
+	if len(e.buffer) < 2 || e.buffer[0].data[1] != "Bob" || e.buffer[1].data != nil {
+		t.Fatalf("Expected only Bob's row, got %+v", e.buffer)
+	}
+	if len(e.table.chips) != 1 || e.table.chips[0] != "name = Bob" {
+		t.Errorf("Expected chip for the filter, got %v", e.table.chips)
+	}
+
+	e.removeQuickFilter(-1)
+	e.addQuickFilter(0, 1, true)
+	if e.buffer[0].data[1] != "Bob" || e.buffer[1].data != nil {
+		t.Errorf("Expected Alice to be excluded, got %+v", e.buffer)
+	}
+	if e.table.chips[0] != "name ≠ Alice" {
+		t.Errorf("Expected exclusion chip, got %v", e.table.chips)
+	}
+
+	e.removeQuickFilter(0)
+	if len(e.relation.QuickFilters) != 0 || len(e.table.chips) != 0 {
+		t.Errorf("Expected quick filters to be removed, got %v", e.relation.QuickFilters)
+	}
+	if e.buffer[0].data[1] != "Alice" || e.buffer[1].data[1] != "Bob" {
+		t.Errorf("Expected all rows after removing the filter, got %+v", e.buffer)
+	}
+}
+
+func TestQuickFilterLabel(t *testing.T) {
+	tests := []struct {
+		qf   dblib.QuickFilter
+		want string
+	}{
+		{dblib.QuickFilter{Column: "age", Value: int64(30)}, "age = 30"},
+		{dblib.QuickFilter{Column: "age", Value: int64(30), Exclude: true}, "age ≠ 30"},
+		{dblib.QuickFilter{Column: "age"}, "age IS NULL"},
+		{dblib.QuickFilter{Column: "age", Exclude: true}, "age IS NOT NULL"},
+		{dblib.QuickFilter{Column: "bio", Value: strings.Repeat("x", 30)}, "bio = " + strings.Repeat("x", 23) + "…"},
+	}
+	for _, tt := range tests {
+		if got := quickFilterLabel(tt.qf); got != tt.want {
+			t.Errorf("quickFilterLabel(%+v) = %q, want %q", tt.qf, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+
+	"ted/internal/dblib"
 )
 
 func (e *Editor) setupKeyBindings() {
@@ -100,6 +102,9 @@ func (e *Editor) setupKeyBindings() {
 		}
 		// Ctrl+N: New row
 		if (rune == 'n' || rune == 14) && mod&tcell.ModCtrl != 0 {
+			if !e.canWriteRows() {
+				return nil
+			}
 			// Ctrl+I: Jump to end and enable insert mode
 			e.loadFromRowId(nil, false, 0)
 			go func() {
@@ -121,7 +126,7 @@ func (e *Editor) setupKeyBindings() {
 				if colIdx, ok := e.relation.ColumnIndex[colName]; ok && colIdx < len(e.relation.Columns) {
 					attr := e.relation.Columns[colIdx]
 					if attr.Nullable {
-						e.insertRow[col] = nil
+						e.insertRow[colIdx] = nil
 						e.renderData()
 					}
 				}
@@ -129,6 +134,100 @@ func (e *Editor) setupKeyBindings() {
 			}
 		}
 
+		// Alt+D: reset cell to the column default in insert mode
+		if rune == 'd' && mod&tcell.ModAlt != 0 {
+			if idx := e.table.dataIndex(col); len(e.insertRow) > 0 && !e.editing && idx >= 0 && idx < len(e.insertRow) {
+				e.insertRow[idx] = dblib.EmptyCellValue
+				e.renderData()
+				return nil
+			}
+		}
+
+		// Alt+E: set cell to an empty string for text columns in insert mode
+		if rune == 'e' && mod&tcell.ModAlt != 0 {
+			if len(e.insertRow) > 0 && !e.editing {
+				if e.setInsertEmpty(col) {
+					e.renderData()
+				}
+				return nil
+			}
+		}
+
+		// Ctrl+]: follow the foreign key in the selected cell
+		if key == tcell.KeyCtrlRightSq {
+			e.followReference(row, col)
+			return nil
+		}
+		// Alt+R: list rows in other tables that reference the selected row
+		if key == tcell.KeyRune && rune == 'r' && mod&tcell.ModAlt != 0 {
+			e.showIncomingReferences(row, col, e.openReferencingRows)
+			return nil
+		}
+		// Alt+X: hex dump of a binary cell
+		if key == tcell.KeyRune && rune == 'x' && mod&tcell.ModAlt != 0 {
+			e.showHexDump(row, col)
+			return nil
+		}
+		// Alt+S: cycle the sort on the selected column, Alt+Shift+S adds it to the sort
+		if key == tcell.KeyRune && mod&tcell.ModAlt != 0 && (rune == 's' || rune == 'S') {
+			e.toggleSort(col, rune == 'S')
+			return nil
+		}
+		// Alt+V: show the selected row transposed, a line per column
+		if key == tcell.KeyRune && rune == 'v' && mod&tcell.ModAlt != 0 {
+			e.showDetail()
+			return nil
+		}
+		// Alt+C: show, hide and reorder columns
+		if key == tcell.KeyRune && rune == 'c' && mod&tcell.ModAlt != 0 {
+			e.showColumnChooser()
+			return nil
+		}
+		// Alt+W: wrap text in the selected column, Alt+Shift+W in every column
+		if key == tcell.KeyRune && mod&tcell.ModAlt != 0 && (rune == 'w' || rune == 'W') {
+			e.toggleWrap(col, rune == 'W')
+			return nil
+		}
+		// Alt+J: split off the rows referencing the selected row below, or close the split
+		if key == tcell.KeyRune && rune == 'j' && mod&tcell.ModAlt != 0 {
+			e.toggleSplit()
+			return nil
+		}
+		// Alt+1..9: show the nth tab
+		if key == tcell.KeyRune && mod&tcell.ModAlt != 0 && rune >= '1' && rune <= '9' {
+			e.switchTab(int(rune - '1'))
+			return nil
+		}
+		// F6: switch between the panes of a split
+		if key == tcell.KeyF6 {
+			e.switchPane()
+			return nil
+		}
+		// Alt+P: pin or unpin the selected column
+		if key == tcell.KeyRune && rune == 'p' && mod&tcell.ModAlt != 0 {
+			e.togglePin(col)
+			return nil
+		}
+		// Alt+= / Alt+-: show only / hide rows with the selected cell's value
+		if key == tcell.KeyRune && mod&tcell.ModAlt != 0 && (rune == '=' || rune == '-') {
+			e.addQuickFilter(row, col, rune == '-')
+			return nil
+		}
+		// Alt+Backspace: remove the most recent quick filter
+		if (key == tcell.KeyBackspace || key == tcell.KeyBackspace2) && mod&tcell.ModAlt != 0 && len(e.insertRow) == 0 {
+			e.removeQuickFilter(-1)
+			return nil
+		}
+		// Alt+, / Alt+.: navigate back/forward through followed references
+		if key == tcell.KeyRune && mod&tcell.ModAlt != 0 && (rune == ',' || rune == '.') {
+			if rune == ',' {
+				e.navigateBack()
+			} else {
+				e.navigateForward()
+			}
+			return nil
+		}
+
 		switch {
 		case key == tcell.KeyEnter:
 			if len(e.insertRow) > 0 && !e.editing && mod&tcell.ModAlt != 0 {
@@ -224,6 +323,14 @@ func (e *Editor) setupKeyBindings() {
 			}
 			e.table.Select(row, len(e.table.GetHeaders())-1)
 			return nil
+		case (key == tcell.KeyPgUp || key == tcell.KeyPgDn) && mod&tcell.ModCtrl != 0:
+			// Ctrl+PgUp/PgDn: show the previous/next tab
+			if key == tcell.KeyPgUp {
+				e.nextTab(-1)
+			} else {
+				e.nextTab(1)
+			}
+			return nil
 		case key == tcell.KeyPgUp:
 			if len(e.insertRow) > 0 || e.paletteMode == PaletteModeDelete {
 				return nil // Disable vertical navigation in insert mode or delete mode
@@ -266,6 +373,14 @@ func (e *Editor) setupKeyBindings() {
 		case (rune == 'p' || rune == 16) && mod&tcell.ModCtrl != 0:
 			e.setPaletteMode(PaletteModeCommand, true)
 			return nil
+		// Ctrl+W sends ETB (23) or 'w' depending on terminal
+		case (rune == 'w' || rune == 23) && mod&tcell.ModCtrl != 0:
+			e.enterFilterMode()
+			return nil
+		// Ctrl+G sends BEL (7) or 'g' depending on terminal
+		case (rune == 'g' || rune == 7) && mod&tcell.ModCtrl != 0:
+			e.enterGotoMode()
+			return nil
 		// Ctrl+` sends BEL (0) or '`' depending on terminal
 		case (rune == '`' || rune == 0) && mod&tcell.ModCtrl != 0:
 			e.setPaletteMode(PaletteModeSQL, true)
@@ -539,6 +654,10 @@ func (e *Editor) consumeKittyCSI(key tcell.Key, r rune, mod tcell.ModMask) bool
 				modifier, err2 := strconv.Atoi(parts[1])
 				if err1 == nil && err2 == nil {
 					mask := modifier - 1
+					// Shift+Enter (bit 0, value 1) finds the previous match
+					if mask == 1 && codepoint == 13 && e.paletteMode == PaletteModeFind {
+						e.executeFind(e.commandPalette.GetText(), true)
+					}
 					// Check if Ctrl is pressed (bit 2, value 4)
 					if mask&4 != 0 {
 						_, col := e.table.GetSelection()
@@ -546,6 +665,9 @@ func (e *Editor) consumeKittyCSI(key tcell.Key, r rune, mod tcell.ModMask) bool
 						case 96: // Ctrl+` (backtick)
 							e.setPaletteMode(PaletteModeSQL, true)
 						case 105: // Ctrl+I: Jump to end and enable insert mode
+							if !e.canWriteRows() {
+								break
+							}
 							e.SetupInsertRow()
 							e.loadFromRowId(nil, false, 0)
 							e.updateStatusForInsertMode()
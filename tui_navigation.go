@@ -0,0 +1,337 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"ted/internal/dblib"
+)
+
+// navLocation records where the user was so back/forward can restore it
+type navLocation struct {
+	relation    *dblib.Relation
+	displayName string
+	topKey      []any // key of the first visible row, nil when the relation was empty
+	sortCols    []dblib.SortColumn
+	row         int    // selected row relative to the first visible row
+	column      string // name of the selected column
+	headers     []dblib.DisplayColumn
+	hiddenCols  []dblib.DisplayColumn
+	pinned      int
+	wrapAll     bool
+}
+
+// buildDisplayHeaders builds table headers in database schema order
+func buildDisplayHeaders(relation *dblib.Relation) []dblib.DisplayColumn {
+	headers := make([]dblib.DisplayColumn, 0, len(relation.Columns))
+	for i, col := range relation.Columns {
+		isKey := false
+		for _, keyIdx := range relation.Key {
+			if keyIdx == i {
+				isKey = true
+				break
+			}
+		}
+		editable := relation.IsColumnEditable(i)
+		headers = append(headers, dblib.DisplayColumn{Name: col.Name, Width: DefaultColumnWidth, IsKey: isKey, Editable: editable, Default: insertPlaceholder(col), Index: i})
+	}
+	return headers
+}
+
+// insertPlaceholder describes what the database fills in when an insert leaves the column unset
+func insertPlaceholder(col dblib.Column) string {
+	switch {
+	case col.Generated:
+		return "generated"
+	case col.AutoIncrement:
+		return "auto"
+	case col.HasDefault:
+		return col.Default
+	default:
+		return ""
+	}
+}
+
+// setRelation swaps the relation shown in the table without loading any rows
+func (e *Editor) setRelation(relation *dblib.Relation, displayName string) {
+	if relation != e.relation {
+		e.saveLayout()
+	}
+	e.relation = relation
+	relation.OnWrite = e.journalWrite // record every write in the audit journal
+	headers := buildDisplayHeaders(relation)
+	pinned := 0
+	if e.config != nil && e.config.PinKeys {
+		pinned = pinKeyColumns(headers)
+	}
+	e.table.SetHeaders(headers).SetPinned(pinned).SetWrapAll(false).SetTableName(displayName).SetVimMode(e.vimMode)
+	e.hiddenCols = nil
+	e.fitPending = true
+	e.showFilter()
+	e.pointer = 0
+	e.setSort(nil)
+	e.updateTabs()
+	e.updateTitle()
+}
+
+// headerIndex returns the display position of the named column, or -1
+func (e *Editor) headerIndex(name string) int {
+	for i, header := range e.table.GetHeaders() {
+		if header.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// currentLocation captures the relation, column layout, scroll position and selection
+func (e *Editor) currentLocation() navLocation {
+	row, col := e.table.GetSelection()
+	headers := e.table.GetHeaders()
+	loc := navLocation{
+		relation:    e.relation,
+		displayName: e.table.tableName,
+		sortCols:    e.sortCols,
+		row:         row,
+		headers:     slices.Clone(headers),
+		hiddenCols:  slices.Clone(e.hiddenCols),
+		pinned:      e.table.GetPinned(),
+		wrapAll:     e.table.GetWrapAll(),
+	}
+	if col >= 0 && col < len(headers) {
+		loc.column = headers[col].Name
+	}
+	if len(e.buffer) > 0 && e.buffer[e.pointer].data != nil {
+		loc.topKey = e.extractKeys(e.buffer[e.pointer].data)
+	}
+	return loc
+}
+
+// pushHistory records the current location before navigating elsewhere
+func (e *Editor) pushHistory() {
+	if e.relation == nil {
+		return
+	}
+	e.backStack = append(e.backStack, e.currentLocation())
+	e.forwardStack = nil
+}
+
+// restoreLocation reopens a previously visited relation with the same columns and
+// at the same position
+func (e *Editor) restoreLocation(loc navLocation) error {
+	e.setRelation(loc.relation, loc.displayName)
+	e.table.SetHeaders(slices.Clone(loc.headers)).SetPinned(loc.pinned).SetWrapAll(loc.wrapAll)
+	e.hiddenCols = slices.Clone(loc.hiddenCols)
+	e.fitPending = false
+	e.setSort(loc.sortCols)
+	col := max(0, e.headerIndex(loc.column))
+	if err := e.loadFromRowId(loc.topKey, true, col); err != nil {
+		return err
+	}
+	e.table.Select(loc.row, col)
+	return nil
+}
+
+// canNavigate reports whether the editor is in a state that allows leaving the current relation
+func (e *Editor) canNavigate() bool {
+	if e.editing || len(e.insertRow) > 0 || e.paletteMode == PaletteModeDelete {
+		e.SetStatusError("Finish editing before navigating")
+		return false
+	}
+	return true
+}
+
+// navigateBack returns to the previous location in the history
+func (e *Editor) navigateBack() {
+	if !e.canNavigate() {
+		return
+	}
+	if len(e.backStack) == 0 {
+		e.SetStatusMessage("No previous location")
+		return
+	}
+	loc := e.backStack[len(e.backStack)-1]
+	e.backStack = e.backStack[:len(e.backStack)-1]
+	e.forwardStack = append(e.forwardStack, e.currentLocation())
+	if err := e.restoreLocation(loc); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.SetStatusMessage("← " + loc.displayName)
+}
+
+// navigateForward re-applies a location undone by navigateBack
+func (e *Editor) navigateForward() {
+	if !e.canNavigate() {
+		return
+	}
+	if len(e.forwardStack) == 0 {
+		e.SetStatusMessage("No next location")
+		return
+	}
+	loc := e.forwardStack[len(e.forwardStack)-1]
+	e.forwardStack = e.forwardStack[:len(e.forwardStack)-1]
+	e.backStack = append(e.backStack, e.currentLocation())
+	if err := e.restoreLocation(loc); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.SetStatusMessage("→ " + loc.displayName)
+}
+
+// followReference opens the row referenced by the foreign key in the selected cell
+func (e *Editor) followReference(row, col int) {
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+	if row < 0 || row >= len(e.buffer) {
+		return
+	}
+	data := e.buffer[(row+e.pointer)%len(e.buffer)].data
+	if data == nil {
+		return
+	}
+	headers := e.table.GetHeaders()
+	if col < 0 || col >= len(headers) {
+		return
+	}
+
+	colName := headers[col].Name
+	colIdx, ok := e.relation.ColumnIndex[colName]
+	if !ok || colIdx >= len(e.relation.Columns) {
+		return
+	}
+	attr := e.relation.Columns[colIdx]
+	if attr.Reference < 0 || attr.Reference >= len(e.relation.References) {
+		e.SetStatusMessage(fmt.Sprintf("%s is not a foreign key", colName))
+		return
+	}
+	ref := e.relation.References[attr.Reference]
+
+	// Collect the referenced column values from the current row
+	foreignKey := make(map[string]any, len(ref.Columns))
+	for localCol, foreignCol := range ref.Columns {
+		localIdx := e.loadedIndex(localCol)
+		if localIdx < 0 || localIdx >= len(data) {
+			e.SetStatusError(fmt.Sprintf("Column %s is not loaded", localCol))
+			return
+		}
+		value := data[localIdx]
+		if value == nil {
+			e.SetStatusMessage(fmt.Sprintf("%s is null", localCol))
+			return
+		}
+		foreignKey[foreignCol] = value
+	}
+
+	foreignRel, err := dblib.NewRelation(e.db, e.dbType, ref.Table)
+	if err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	if len(foreignRel.Key) == 0 {
+		e.SetStatusError(fmt.Sprintf("Relation %s has no keyable columns and cannot be viewed", ref.Table))
+		return
+	}
+
+	// The foreign key may target a unique column rather than the key, so look the key up
+	keyCols := make([]string, len(foreignRel.Key))
+	for i, keyIdx := range foreignRel.Key {
+		keyCols[i] = foreignRel.Columns[keyIdx].Name
+	}
+	found, err := dblib.GetForeignRow(e.db, foreignRel, foreignKey, keyCols)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			e.SetStatusError(fmt.Sprintf("Referenced row not found in %s", ref.Table))
+			return
+		}
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	targetKey := make([]any, len(keyCols))
+	for i, name := range keyCols {
+		targetKey[i] = found[name]
+	}
+
+	e.pushHistory()
+	e.setRelation(foreignRel, ref.Table)
+	if layout, ok := e.savedLayout(); ok {
+		e.applyLayoutColumns(layout)
+	}
+	targetCol := max(0, e.headerIndex(ref.Columns[colName]))
+	if err := e.loadFromRowId(targetKey, true, targetCol); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.table.Select(0, targetCol)
+	e.SetStatusMessage(fmt.Sprintf("→ %s · Alt+, to go back", ref.Table))
+}
+
+// keyLabel describes a key for the status bar, e.g. "id = 48213"
+func keyLabel(names []string, keys []any) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		text, _ := formatCellValue(keys[i], tcell.StyleDefault)
+		parts[i] = name + " = " + text
+	}
+	return strings.Join(parts, ", ")
+}
+
+// enterGotoMode opens the palette to jump to a row by its key
+func (e *Editor) enterGotoMode() {
+	if e.relation == nil {
+		e.SetStatusError("No relation to go to a row in")
+		return
+	}
+	if !e.canNavigate() {
+		return
+	}
+	e.setPaletteMode(PaletteModeGoto, true)
+	names := e.relation.KeyNames()
+	if len(names) == 1 {
+		e.commandPalette.SetPlaceholder(fmt.Sprintf("Go to %s… (Esc to exit)", names[0]))
+	} else {
+		e.commandPalette.SetPlaceholder(fmt.Sprintf("Go to %s… comma-separated (Esc to exit)", strings.Join(names, ", ")))
+	}
+}
+
+// gotoKey selects the row whose key is typed in text, or the nearest row after it
+// when there is no such row, keeping the current sort and filters
+func (e *Editor) gotoKey(text string) error {
+	keys, err := e.relation.ParseKey(text)
+	if err != nil {
+		return err
+	}
+	found, exact, err := e.relation.NearestKey(keys)
+	if err != nil {
+		return err
+	}
+	_, col := e.table.GetSelection()
+	col = max(col, 0)
+	names := e.relation.KeyNames()
+
+	if found == nil {
+		if err := e.loadFromRowId(nil, false, col); err != nil {
+			return err
+		}
+		e.table.Select(e.lastRowIdx()-1, col)
+		e.SetStatusMessage(fmt.Sprintf("No row at or after %s · showing the last row", keyLabel(names, keys)))
+		return nil
+	}
+
+	if err := e.loadFromRowId(found, true, col); err != nil {
+		return err
+	}
+	e.table.Select(0, col)
+	if exact {
+		e.SetStatusMessage(keyLabel(names, found))
+	} else {
+		e.SetStatusMessage(fmt.Sprintf("No row %s · showing the next one, %s", keyLabel(names, keys), keyLabel(names, found)))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestGotoKey(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (5, 'Eve'), (9, 'Ivan')`); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+
+	if err := e.gotoKey("5"); err != nil {
+		t.Fatalf("gotoKey failed: %v", err)
+	}
+	if row, _ := e.table.GetSelection(); row != 0 || e.buffer[e.pointer].data[1] != "Eve" {
+		t.Errorf("Expected Eve's row at the top and selected, got row %d of %+v", row, e.buffer)
+	}
+
+	// A missing key goes to the next row
+	if err := e.gotoKey("6"); err != nil {
+		t.Fatalf("gotoKey failed: %v", err)
+	}
+	if e.buffer[e.pointer].data[1] != "Ivan" {
+		t.Errorf("Expected Ivan's row at the top, got %+v", e.buffer)
+	}
+
+	if err := e.gotoKey("six"); err == nil {
+		t.Error("Expected a non-numeric key to be rejected")
+	}
+}
+
+func TestKeyLabel(t *testing.T) {
+	got := keyLabel([]string{"tenant", "id"}, []any{"acme", int64(48213)})
+	if want := "tenant = acme, id = 48213"; got != want {
+		t.Errorf("keyLabel = %q, want %q", got, want)
+	}
+}
+
+func TestFollowReferenceAfterScrolling(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	if _, err := db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id))`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for id := 1; id <= 10; id++ {
+		if _, err := db.Exec(`INSERT OR IGNORE INTO users (id, name) VALUES (?, 'user')`, id); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+		if _, err := db.Exec(`INSERT INTO posts (id, user_id) VALUES (?, ?)`, id, id); err != nil {
+			t.Fatalf("Failed to insert post: %v", err)
+		}
+	}
+	posts, err := dblib.NewRelation(db, dblib.SQLite, "posts")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.setRelation(posts, "posts")
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+
+	// Scrolled down a row, the first row on screen is post 2
+	if _, err := e.nextRows(1); err != nil {
+		t.Fatalf("nextRows failed: %v", err)
+	}
+	if e.pointer == 0 {
+		t.Fatalf("Expected scrolling to move the buffer pointer")
+	}
+	e.followReference(0, e.headerIndex("user_id"))
+	if e.relation.Name != "users" || e.buffer[e.pointer].data[0] != int64(2) {
+		t.Errorf("Expected user 2 opened, got %v in %s", e.buffer[e.pointer].data, e.relation.Name)
+	}
+}
+
+func TestOpenReferencingRows(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	if _, err := db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id));
+		INSERT INTO posts (id, user_id) VALUES (1, 1), (2, 2), (3, 2);`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	ref := dblib.IncomingReference{Table: "posts", Columns: map[string]string{"user_id": "id"}}
+	e.openReferencingRows(incomingEntry{ref: ref, values: map[string]any{"id": int64(2)}, count: 2})
+	if e.relation.Name != "posts" || e.relation.IsCustomSQL {
+		t.Fatalf("Expected the posts table opened, got %q", e.relation.Name)
+	}
+	var ids []any
+	for _, row := range e.table.data {
+		if row.data != nil {
+			ids = append(ids, row.data[0])
+		}
+	}
+	if len(ids) != 2 || ids[0] != int64(2) || ids[1] != int64(3) {
+		t.Errorf("Expected posts 2 and 3 of Bob, got %v", ids)
+	}
+}
+
+func TestNavigationKeepsColumns(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	if _, err := db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT, user_id INTEGER REFERENCES users(id));
+		INSERT INTO posts (id, title, user_id) VALUES (1, 'Hello', 1);`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	// Hiding the user's name is saved in its layout when leaving it
+	items := e.chooserItems()
+	items[1].shown = false
+	if err := e.applyColumns(items); err != nil {
+		t.Fatalf("applyColumns failed: %v", err)
+	}
+	posts, err := dblib.NewRelation(db, dblib.SQLite, "posts")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.setRelation(posts, "posts")
+	headers := e.table.GetHeaders()
+	e.table.SetHeaders([]dblib.DisplayColumn{headers[2], headers[0]}).SetPinned(1).SetWrapAll(true)
+	e.hiddenCols = []dblib.DisplayColumn{headers[1]}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+
+	e.followReference(0, e.headerIndex("user_id"))
+	if e.relation.Name != "users" {
+		t.Fatalf("Expected users opened, got %s", e.relation.Name)
+	}
+	if headers := e.table.GetHeaders(); len(headers) != 1 || headers[0].Name != "id" {
+		t.Errorf("Expected the users layout to hide name, got %+v", headers)
+	}
+
+	// Back on posts, the columns are as they were left and user_id is still selected
+	e.navigateBack()
+	headers = e.table.GetHeaders()
+	if e.relation.Name != "posts" || len(headers) != 2 || headers[0].Name != "user_id" || headers[1].Name != "id" {
+		t.Fatalf("Expected posts with user_id and id shown, got %+v", headers)
+	}
+	if e.table.GetPinned() != 1 || !e.table.GetWrapAll() || len(e.hiddenCols) != 1 || e.hiddenCols[0].Name != "title" {
+		t.Errorf("Expected user_id pinned, wrapping and title hidden, got pinned %d, wrap %v, hidden %+v",
+			e.table.GetPinned(), e.table.GetWrapAll(), e.hiddenCols)
+	}
+	if _, col := e.table.GetSelection(); col != 0 {
+		t.Errorf("Expected user_id selected, got column %d", col)
+	}
+}
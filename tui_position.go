@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"ted/internal/dblib"
+)
+
+// resetRowCount forgets the row count and key sample so the next render counts the
+// rows again, after a reload that may have changed them
+func (e *Editor) resetRowCount() {
+	if e.keepRowCount {
+		return
+	}
+	e.rowCounted = false
+	e.keySample = nil
+	e.positionKey = ""
+	e.countGen++
+}
+
+// ensureKeySample samples the relation's keys in the current order if not done yet
+func (e *Editor) ensureKeySample() error {
+	if e.keySample != nil {
+		return nil
+	}
+	sample, err := e.relation.SampleKeys(e.sortCols, e.rowCount)
+	if err != nil {
+		return err
+	}
+	e.keySample = sample
+	return nil
+}
+
+// runBackground runs work off the UI goroutine and the function it returns back on it
+func (e *Editor) runBackground(work func() func()) {
+	if e.background != nil {
+		e.background(work)
+		return
+	}
+	go func() {
+		apply := work()
+		e.app.QueueUpdateDraw(apply)
+	}()
+}
+
+// updatePosition works out where the first visible row lies for the position indicator
+// and scrollbar, in the background. Small tables count the rows before it exactly;
+// large ones place it by the key sample. One count runs at a time per pane, and once
+// done it catches up with where the table has scrolled. Errors leave the position
+// unknown.
+func (e *Editor) updatePosition() {
+	if e.relation == nil || e.relation.DB == nil || e.table == nil || e.positionBusy {
+		return
+	}
+	var cursor []any
+	if len(e.buffer) > 0 && e.buffer[e.pointer].data != nil {
+		cursor = e.rowCursor(e.buffer[e.pointer].data)
+	}
+	key := ""
+	if cursor != nil {
+		key = fmt.Sprintf("%#v", cursor)
+	}
+	if e.rowCounted && key == e.positionKey {
+		return
+	}
+
+	// The count works on copies of what it needs, as the table may change meanwhile
+	p, gen := e.pane, e.countGen
+	relation := *e.relation
+	relation.QuickFilters = slices.Clone(relation.QuickFilters)
+	relation.Link = slices.Clone(relation.Link)
+	sortCols := slices.Clone(e.sortCols)
+	counted, count, exact, sample := e.rowCounted, e.rowCount, e.rowCountExact, e.keySample
+	e.positionBusy = true
+	e.runBackground(func() func() {
+		var err error
+		if !counted {
+			count, exact, err = relation.CountRows()
+		}
+		first := int64(-1)
+		if err == nil && cursor != nil {
+			if exact && count <= dblib.ExactCountLimit {
+				if position, err := relation.RowPosition(sortCols, cursor); err == nil {
+					first = position
+				}
+			} else {
+				if sample == nil {
+					sample, _ = relation.SampleKeys(sortCols, count)
+				}
+				if sample != nil {
+					first = int64(sample.Fraction(cursor) * float64(count))
+				}
+			}
+		}
+		return func() {
+			p.positionBusy = false
+			if p.countGen != gen {
+				// Reloaded meanwhile, so count again
+				e.withPane(p, e.updatePosition)
+				return
+			}
+			if err != nil {
+				p.positionKey = key
+				p.table.SetRowPosition(-1, -1, false)
+				return
+			}
+			p.rowCount, p.rowCountExact, p.rowCounted = count, exact, true
+			if p.keySample == nil {
+				p.keySample = sample
+			}
+			p.positionKey = key
+			p.table.SetRowPosition(first, count, exact)
+			e.withPane(p, e.updatePosition)
+		}
+	})
+}
+
+// scrollToFraction jumps to the row about fraction of the way through the relation,
+// from 0 for the first row to 1 for the last, by the key sample rather than OFFSET
+func (e *Editor) scrollToFraction(fraction float64) {
+	if e.relation == nil || !e.rowCounted || e.editing || len(e.insertRow) > 0 {
+		return
+	}
+	_, col := e.table.GetSelection()
+	var target []any
+	if fraction > 0 && fraction < 1 {
+		if err := e.ensureKeySample(); err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		target = e.keySample.At(fraction)
+	}
+
+	e.keepRowCount = true
+	defer func() { e.keepRowCount = false }()
+	if err := e.loadFromRowId(target, fraction < 1, col); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	if fraction >= 1 {
+		e.table.Select(e.lastRowIdx()-1, col)
+	} else {
+		e.table.Select(0, col)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatRowPosition(t *testing.T) {
+	tests := []struct {
+		first, visible, count int64
+		exact                 bool
+		want                  string
+	}{
+		{1200, 40, 48213, true, "1,201–1,240 of 48,213"},
+		{1200, 40, 1234567, false, "~1,201–1,240 of ~1,234,567"},
+		{0, 5, 2, true, "1–2 of 2"},
+		{-1, 0, 0, true, "0 rows"},
+		{-1, 0, 1, true, "1 row"},
+		{0, 0, -1, true, ""},
+	}
+	for _, tt := range tests {
+		if got := formatRowPosition(tt.first, tt.visible, tt.count, tt.exact); got != tt.want {
+			t.Errorf("formatRowPosition(%d, %d, %d, %v) = %q, want %q", tt.first, tt.visible, tt.count, tt.exact, got, tt.want)
+		}
+	}
+}
+
+func TestScrollThumb(t *testing.T) {
+	tests := []struct {
+		first, visible, count int64
+		length                int
+		start, size           int
+	}{
+		{0, 10, 100, 10, 0, 1},
+		{50, 10, 100, 10, 5, 1},
+		{90, 10, 100, 10, 9, 1},
+		{0, 5, 10, 10, 0, 5},
+		{0, 10, 1000000, 20, 0, 1},
+	}
+	for _, tt := range tests {
+		start, size := scrollThumb(tt.first, tt.visible, tt.count, tt.length)
+		if start != tt.start || size != tt.size {
+			t.Errorf("scrollThumb(%d, %d, %d, %d) = %d, %d, want %d, %d", tt.first, tt.visible, tt.count, tt.length, start, size, tt.start, tt.size)
+		}
+	}
+}
+
+func TestRowPositionAndScrollbar(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	var values []string
+	for id := 3; id <= 100; id++ {
+		values = append(values, fmt.Sprintf("(%d, 'user %d')", id, id))
+	}
+	if _, err := db.Exec(`INSERT INTO users (id, name) VALUES ` + strings.Join(values, ", ")); err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+
+	if err := e.loadFromRowId([]any{int64(41)}, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	if e.rowCount != 100 || !e.rowCountExact || e.table.firstRow != 40 {
+		t.Errorf("Expected row 41 of exactly 100, got first %d of %d (exact %v)", e.table.firstRow, e.rowCount, e.rowCountExact)
+	}
+
+	e.scrollToFraction(1)
+	if row, _ := e.table.GetSelection(); e.buffer[(row+e.pointer)%len(e.buffer)].data[0] != int64(100) {
+		t.Errorf("Expected the last row selected, got row %d of %+v", row, e.buffer)
+	}
+	e.scrollToFraction(0)
+	if e.buffer[e.pointer].data[0] != int64(1) || e.table.firstRow != 0 {
+		t.Errorf("Expected the first row at the top, got %+v", e.buffer[e.pointer])
+	}
+
+	// The middle comes from the key sample, which holds every row of a small table
+	e.scrollToFraction(0.5)
+	if id := e.buffer[e.pointer].data[0]; id != int64(51) {
+		t.Errorf("Expected row 51 at the top, got %v", id)
+	}
+	if e.keySample == nil || e.keySample.Len() != 100 {
+		t.Errorf("Expected the key sample to be kept across the jump")
+	}
+}
+
+func TestUpdatePositionInBackground(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	var queued []func() func()
+	e.background = func(work func() func()) { queued = append(queued, work) }
+
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	e.renderData()
+	if len(queued) != 1 || e.rowCounted {
+		t.Fatalf("Expected one count queued and none applied, got %d", len(queued))
+	}
+
+	// A reload while counting drops the count begun before it and counts again
+	if _, err := db.Exec(`INSERT INTO users (id, name) VALUES (3, 'Carol')`); err != nil {
+		t.Fatalf("Failed to insert row: %v", err)
+	}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	queued[0]()()
+	if e.rowCounted || len(queued) != 2 {
+		t.Fatalf("Expected the stale count dropped and another queued, got %d", len(queued))
+	}
+	queued[1]()()
+	if !e.rowCounted || e.rowCount != 3 || e.table.firstRow != 0 {
+		t.Errorf("Expected row 1 of 3, got first %d of %d", e.table.firstRow, e.rowCount)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+const pageReferences = "references"
+
+// incomingEntry is a referencing table/column pair along with the values it must match
+type incomingEntry struct {
+	ref    dblib.IncomingReference
+	values map[string]any // referenced column name -> value in the selected row
+	count  int64
+}
+
+// showIncomingReferences opens a panel listing the foreign keys that point at the
+// selected row, calling open with the one chosen
+func (e *Editor) showIncomingReferences(row, col int, open func(incomingEntry)) {
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+	if row < 0 || row >= len(e.buffer) {
+		return
+	}
+	data := e.buffer[(row+e.pointer)%len(e.buffer)].data
+	if data == nil {
+		return
+	}
+
+	table, refs, err := e.relation.IncomingReferences()
+	if err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	if len(refs) == 0 {
+		e.SetStatusMessage(fmt.Sprintf("No foreign keys reference %s", table))
+		return
+	}
+
+	entries := make([]incomingEntry, 0, len(refs))
+	for _, ref := range refs {
+		entry := incomingEntry{ref: ref, values: make(map[string]any, len(ref.Columns))}
+		complete := true
+		for _, targetCol := range ref.Columns {
+			colIdx := e.relation.BaseColumnIndex(table, targetCol)
+			if colIdx < 0 {
+				complete = false
+				break
+			}
+			loadedIdx := e.loadedIndex(e.relation.Columns[colIdx].Name)
+			if loadedIdx < 0 || loadedIdx >= len(data) {
+				complete = false
+				break
+			}
+			entry.values[targetCol] = data[loadedIdx]
+		}
+		if !complete {
+			continue // referenced columns are not part of this relation
+		}
+		entry.count, err = e.relation.CountReferencingRows(ref, entry.values)
+		if err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		e.SetStatusMessage("Referenced columns are not part of this relation")
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false).SetHighlightFullLine(true)
+	list.SetBorder(true).SetTitle(" Referenced by ")
+	width := 0
+	for _, entry := range entries {
+		cols := make([]string, 0, len(entry.ref.Columns))
+		for col := range entry.ref.Columns {
+			cols = append(cols, col)
+		}
+		sort.Strings(cols)
+		label := fmt.Sprintf("%s.%s · %d rows", entry.ref.Table, strings.Join(cols, ","), entry.count)
+		if entry.count == 1 {
+			label = fmt.Sprintf("%s.%s · 1 row", entry.ref.Table, strings.Join(cols, ","))
+		}
+		width = max(width, len(label))
+		list.AddItem(label, "", 0, func() {
+			e.closeIncomingReferences()
+			open(entry)
+		})
+	}
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			e.closeIncomingReferences()
+			return nil
+		}
+		return event
+	})
+
+	// Center the panel over the table
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, len(entries)+2, 0, true).
+			AddItem(nil, 0, 1, false), width+6, 0, true).
+		AddItem(nil, 0, 1, false)
+	e.pages.AddPage(pageReferences, modal, true, true)
+	e.app.SetFocus(list)
+	e.SetStatusMessage("Enter to open referencing rows · Esc to close")
+}
+
+// closeIncomingReferences removes the references panel and returns focus to the table
+func (e *Editor) closeIncomingReferences() {
+	e.pages.RemovePage(pageReferences)
+	e.app.SetFocus(e.table)
+}
+
+// openReferencingRows opens the referencing table restricted to rows pointing at the selected row
+func (e *Editor) openReferencingRows(entry incomingEntry) {
+	if entry.count == 0 {
+		e.SetStatusMessage(fmt.Sprintf("No rows in %s reference this row", entry.ref.Table))
+		return
+	}
+	relation, err := e.relation.NewReferencingRelation(entry.ref, entry.values)
+	if err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+
+	e.pushHistory()
+	e.setRelation(relation, entry.ref.Table)
+	if err := e.restoreLayout(); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.table.Select(0, 0)
+	e.SetStatusMessage(fmt.Sprintf("%s referencing rows · Alt+, to go back", entry.ref.Table))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+// replaceSamples is how many before/after values the replace preview lists
+const replaceSamples = 3
+
+// enterReplaceMode replaces the text typed into the find palette throughout the
+// selected column, asking for the replacement next
+func (e *Editor) enterReplaceMode() {
+	find := e.commandPalette.GetText()
+	if find == "" {
+		e.SetStatusError("Type the text to find before replacing it")
+		return
+	}
+	if !e.canFilter() {
+		return
+	}
+	if e.findOpts.AllColumns {
+		e.SetStatusError("Replace works on one column · alt+a to search the selected column")
+		return
+	}
+	_, col := e.table.GetSelection()
+	headers := e.table.GetHeaders()
+	if col < 0 || col >= len(headers) {
+		return
+	}
+	if colIdx, ok := e.relation.ColumnIndex[headers[col].Name]; !ok || !e.relation.IsColumnEditable(colIdx) {
+		e.SetStatusError(fmt.Sprintf("Column %s is not editable", headers[col].Name))
+		return
+	}
+
+	e.setPaletteMode(PaletteModeReplace, true)
+	e.replaceFind, e.replaceCol = find, col
+	e.commandPalette.SetLabel(PaletteModeReplace.Glyph() + find + " → ")
+	e.SetStatusMessage(fmt.Sprintf("Replacing %s matches in %s · Enter to preview", e.findOpts.Mode, headers[col].Name))
+}
+
+// formatReplacePlan summarizes a replace for the status bar with a few before and
+// after values
+func formatReplacePlan(plan *dblib.ReplacePlan) string {
+	rows := "1 row"
+	if len(plan.Changes) != 1 {
+		rows = fmt.Sprintf("%d rows", len(plan.Changes))
+	}
+	summary := "Replaces " + rows
+	if plan.Matched != len(plan.Changes) {
+		summary = fmt.Sprintf("Replaces %d of %d matching rows", len(plan.Changes), plan.Matched)
+	}
+
+	sample := func(text string) string {
+		if runes := []rune(text); len(runes) > quickFilterLabelWidth {
+			text = string(runes[:quickFilterLabelWidth-1]) + "…"
+		}
+		return tview.Escape(text)
+	}
+	var samples []string
+	for _, change := range plan.Changes[:min(len(plan.Changes), replaceSamples)] {
+		before, _ := formatCellValue(change.Before, tcell.StyleDefault)
+		samples = append(samples, sample(before)+" → "+sample(change.After))
+	}
+	if len(plan.Changes) > replaceSamples {
+		samples = append(samples, "…")
+	}
+	return summary + ": " + strings.Join(samples, ", ")
+}
+
+// previewReplace works out which rows the replacement changes and shows the UPDATE,
+// waiting for Enter to run it
+func (e *Editor) previewReplace(replacement string) {
+	e.replacePlan = nil
+	headers := e.table.GetHeaders()
+	if e.replaceCol < 0 || e.replaceCol >= len(headers) {
+		return
+	}
+	colIdx, ok := e.relation.ColumnIndex[headers[e.replaceCol].Name]
+	if !ok {
+		e.SetStatusError("Column not found in relation")
+		return
+	}
+
+	plan, err := e.relation.PlanReplace(colIdx, e.replaceFind, replacement, e.findOpts)
+	if err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	switch {
+	case plan.Matched == 0:
+		e.SetStatusMessage("No match found")
+		return
+	case len(plan.Changes) == 0:
+		e.SetStatusMessage("The replacement leaves every match as it is")
+		return
+	}
+
+	e.replacePlan = plan
+	e.commandPalette.SetText("")
+	e.commandPalette.SetPlaceholder(plan.Preview())
+	e.SetStatusMessage(formatReplacePlan(plan) + " · Enter to replace in one transaction · type to change the replacement · Esc to cancel")
+}
+
+// executeReplace runs the previewed replace and reloads the rows in view
+func (e *Editor) executeReplace() {
+	plan := e.replacePlan
+	if plan == nil {
+		return
+	}
+	col := e.replaceCol
+	e.setPaletteMode(PaletteModeDefault, false)
+	e.app.SetFocus(e.table)
+
+	if e.dryRun {
+		e.dryRunReplace(plan, col)
+		return
+	}
+	if err := e.relation.ExecuteReplace(plan); err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+
+	loc := e.currentLocation()
+	if err := e.loadFromRowId(loc.topKey, true, col); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.table.Select(loc.row, col)
+	if len(plan.Changes) == 1 {
+		e.SetStatusMessage("Replaced 1 value")
+	} else {
+		e.SetStatusMessage(fmt.Sprintf("Replaced %d values", len(plan.Changes)))
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+func TestReplaceFlow(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.commandPalette = tview.NewInputField()
+	e.statusBar = nil
+	e.findOpts = dblib.FindOptions{Mode: dblib.FindSubstring}
+
+	// Dry runs collect the UPDATEs and show the new values as pending
+	e.table.Select(0, 1)
+	e.setPaletteMode(PaletteModeFind, false)
+	e.commandPalette.SetText("li")
+	e.enterReplaceMode()
+	if e.paletteMode != PaletteModeReplace || e.replaceFind != "li" {
+		t.Fatalf("Expected replace mode for %q, got mode %v find %q", "li", e.paletteMode, e.replaceFind)
+	}
+	e.previewReplace("LI")
+	if e.replacePlan == nil || len(e.replacePlan.Changes) != 1 {
+		t.Fatalf("Expected a plan changing Alice, got %+v", e.replacePlan)
+	}
+	e.executeReplace()
+	if len(e.dryRunScript) != 1 || e.dryRunScript[0] != "UPDATE users SET name = 'ALIce' WHERE id = 1 AND name = 'Alice'" {
+		t.Errorf("Unexpected script: %v", e.dryRunScript)
+	}
+	if got := e.applyPending(e.buffer[0]).data[1]; got != "ALIce" {
+		t.Errorf("Expected pending value, got %v", got)
+	}
+
+	// Otherwise the rows are rewritten and reloaded
+	e.dryRun = false
+	e.pendingRows = map[string]Row{}
+	e.setPaletteMode(PaletteModeFind, false)
+	e.commandPalette.SetText("b")
+	e.findOpts.IgnoreCase = true
+	e.enterReplaceMode()
+	e.previewReplace("p")
+	e.executeReplace()
+	var name string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = 2").Scan(&name); err != nil || name != "pop" {
+		t.Errorf("Expected Bob to become pop, got %q (%v)", name, err)
+	}
+	if e.buffer[1].data[1] != "pop" {
+		t.Errorf("Expected reloaded rows, got %+v", e.buffer)
+	}
+}
+
+func TestFormatReplacePlan(t *testing.T) {
+	plan := &dblib.ReplacePlan{Matched: 5, Changes: []dblib.ReplaceChange{
+		{Before: "colour", After: "color"},
+		{Before: []byte("[red]"), After: "[blue]"},
+		{Before: "a", After: "b"},
+		{Before: "c", After: "d"},
+	}}
+	want := "Replaces 4 of 5 matching rows: colour → color, [red[] → [blue[], a → b, …"
+	if got := formatReplacePlan(plan); got != want {
+		t.Errorf("formatReplacePlan = %q, want %q", got, want)
+	}
+	plan.Matched = 1
+	plan.Changes = plan.Changes[:1]
+	if got, want := formatReplacePlan(plan), "Replaces 1 row: colour → color"; got != want {
+		t.Errorf("formatReplacePlan = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"ted/internal/dblib"
+)
+
+// nextSort cycles name through ascending, descending and unsorted. With add the
+// other sort columns are kept, so name becomes a secondary sort; otherwise name
+// replaces them.
+func nextSort(sortCols []dblib.SortColumn, name string, add bool) []dblib.SortColumn {
+	idx := slices.IndexFunc(sortCols, func(sc dblib.SortColumn) bool { return sc.Name == name })
+
+	// absent -> ASC -> DESC -> absent
+	next := dblib.SortColumn{Name: name, Asc: true}
+	remove := false
+	if idx >= 0 {
+		if sortCols[idx].Asc {
+			next.Asc = false
+		} else {
+			remove = true
+		}
+	}
+
+	if !add {
+		if remove {
+			return nil
+		}
+		return []dblib.SortColumn{next}
+	}
+
+	result := slices.Clone(sortCols)
+	switch {
+	case idx < 0:
+		result = append(result, next)
+	case remove:
+		result = slices.Delete(result, idx, idx+1)
+	default:
+		result[idx] = next
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// describeSort formats the sort for the status bar
+func describeSort(sortCols []dblib.SortColumn) string {
+	if len(sortCols) == 0 {
+		return "Sort cleared"
+	}
+	parts := make([]string, len(sortCols))
+	for i, sc := range sortCols {
+		if sc.Asc {
+			parts[i] = sc.Name + " ▲"
+		} else {
+			parts[i] = sc.Name + " ▼"
+		}
+	}
+	return "Sorted by " + strings.Join(parts, ", ")
+}
+
+// setSort changes the sort without reloading rows
+func (e *Editor) setSort(sortCols []dblib.SortColumn) {
+	e.sortCols = sortCols
+	e.table.SetSort(sortCols)
+}
+
+// toggleSort cycles the sort on a column and reloads, keeping the selected row in view
+func (e *Editor) toggleSort(col int, add bool) {
+	if e.relation == nil {
+		return
+	}
+	if e.editing || len(e.insertRow) > 0 || e.paletteMode == PaletteModeDelete {
+		e.SetStatusError("Finish editing before sorting")
+		return
+	}
+	headers := e.table.GetHeaders()
+	if col < 0 || col >= len(headers) {
+		return
+	}
+
+	selectedKeys := e.selectedKeys()
+	e.setSort(nextSort(e.sortCols, headers[col].Name, add))
+	if err := e.loadFromRowId(selectedKeys, true, col); err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	e.table.Select(0, col)
+	e.layoutChanged()
+	e.SetStatusMessage(describeSort(e.sortCols))
+}
+
+// rowCursor returns the values that position a query at row: its key, preceded
+// by its sort column values when sorted
+func (e *Editor) rowCursor(row []any) []any {
+	keys := e.extractKeys(row)
+	if len(e.sortCols) == 0 || keys == nil {
+		return keys
+	}
+	cursor := make([]any, 0, len(e.sortCols)+len(keys))
+	for _, sc := range e.sortCols {
+		idx := e.headerIndex(sc.Name)
+		if idx < 0 || e.table.dataIndex(idx) >= len(row) {
+			return keys // the query looks the sort values up by key
+		}
+		cursor = append(cursor, row[e.table.dataIndex(idx)])
+	}
+	return append(cursor, keys...)
+}
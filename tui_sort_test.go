@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"ted/internal/dblib"
+)
+
+func TestNextSort(t *testing.T) {
+	asc := func(name string) dblib.SortColumn { return dblib.SortColumn{Name: name, Asc: true} }
+	desc := func(name string) dblib.SortColumn { return dblib.SortColumn{Name: name} }
+
+	tests := []struct {
+		name    string
+		current []dblib.SortColumn
+		column  string
+		add     bool
+		want    []dblib.SortColumn
+	}{
+		{"unsorted to ascending", nil, "age", false, []dblib.SortColumn{asc("age")}},
+		{"ascending to descending", []dblib.SortColumn{asc("age")}, "age", false, []dblib.SortColumn{desc("age")}},
+		{"descending to off", []dblib.SortColumn{desc("age")}, "age", false, nil},
+		{"other column replaces sort", []dblib.SortColumn{asc("age"), desc("name")}, "id", false, []dblib.SortColumn{asc("id")}},
+		{"sorted column replaces sort", []dblib.SortColumn{asc("age"), asc("name")}, "name", false, []dblib.SortColumn{desc("name")}},
+		{"add secondary sort", []dblib.SortColumn{asc("age")}, "name", true, []dblib.SortColumn{asc("age"), asc("name")}},
+		{"add keeps priority", []dblib.SortColumn{asc("age"), asc("name")}, "age", true, []dblib.SortColumn{desc("age"), asc("name")}},
+		{"add removes descending", []dblib.SortColumn{desc("age"), asc("name")}, "age", true, []dblib.SortColumn{asc("name")}},
+		{"add removes last", []dblib.SortColumn{desc("age")}, "age", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := slices.Clone(tt.current)
+			got := nextSort(current, tt.column, tt.add)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("nextSort(%v, %q, %v) = %v, want %v", tt.current, tt.column, tt.add, got, tt.want)
+			}
+			if !slices.Equal(current, tt.current) {
+				t.Errorf("nextSort modified its input: %v", current)
+			}
+		})
+	}
+}
+
+func TestDescribeSort(t *testing.T) {
+	if got := describeSort(nil); got != "Sort cleared" {
+		t.Errorf("describeSort(nil) = %q", got)
+	}
+	got := describeSort([]dblib.SortColumn{{Name: "age", Asc: true}, {Name: "name"}})
+	if want := "Sorted by age ▲, name ▼"; got != want {
+		t.Errorf("describeSort = %q, want %q", got, want)
+	}
+}
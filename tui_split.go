@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"ted/internal/dblib"
+)
+
+// splitView shows a second table below the first: the detail pane, with the rows
+// of another table whose foreign key points at the master pane's selected row
+type splitView struct {
+	master, detail *pane
+	table          string                  // master's base table, which ref points at
+	ref            dblib.IncomingReference // foreign key from the detail table
+	relation       *dblib.Relation         // detail relation the master's row links, until the pane shows another
+}
+
+// toggleSplit opens a split below the table with the rows referencing the selected
+// row, choosing the foreign key if several do, or closes the split
+func (e *Editor) toggleSplit() {
+	if e.split != nil {
+		e.closeSplit()
+		return
+	}
+	if e.relation == nil || !e.canNavigate() {
+		return
+	}
+	row, col := e.table.GetSelection()
+	e.showIncomingReferences(row, col, e.openSplit)
+}
+
+// openSplit shows the table of entry's foreign key in a detail pane below the table,
+// keeping focus on the table
+func (e *Editor) openSplit(entry incomingEntry) {
+	table, _, err := e.relation.IncomingReferences()
+	if err != nil {
+		e.SetStatusError(err.Error())
+		return
+	}
+	relation, err := dblib.NewRelation(e.db, e.dbType, entry.ref.Table)
+	if err != nil {
+		e.SetStatusErrorWithSentry(err)
+		return
+	}
+	if len(relation.Key) == 0 {
+		e.SetStatusError(fmt.Sprintf("%s has no keyable columns and cannot be viewed", entry.ref.Table))
+		return
+	}
+
+	detail := &pane{}
+	detail.table = e.newTable(e.table.rowsHeight/2, buildDisplayHeaders(relation))
+	detail.table.SetDimmed(true)
+	e.split = &splitView{master: e.pane, detail: detail, table: table, ref: entry.ref, relation: relation}
+	e.withPane(detail, func() {
+		e.setRelation(relation, relation.Name)
+		e.setupKeyBindings()
+	})
+	e.loadDetail()
+	e.layoutTables()
+	e.SetStatusMessage(fmt.Sprintf("%s rows of the selected row below · F6 to switch panes · Alt+J to close", entry.ref.Table))
+}
+
+// closeSplit removes the detail pane, focusing the master's table
+func (e *Editor) closeSplit() {
+	s := e.split
+	if s == nil {
+		return
+	}
+	e.focusPane(s.master)
+	if e.pane != s.master {
+		return
+	}
+	e.withPane(s.detail, e.saveLayout)
+	e.split = nil
+	e.table.SetDimmed(false)
+	e.layoutTables()
+	e.app.SetFocus(e.table)
+}
+
+// layoutTables stacks the table, or both tables of a split, above the status bar
+// and command palette
+func (e *Editor) layoutTables() {
+	e.layout.Clear()
+	if s := e.split; s != nil {
+		e.layout.AddItem(s.master.table, 0, 1, s.master == e.pane).
+			AddItem(s.detail.table, 0, 1, s.detail == e.pane)
+	} else {
+		e.layout.AddItem(e.table, 0, 1, true)
+	}
+	e.layout.AddItem(e.statusBar, 1, 0, false).
+		AddItem(e.commandPalette, 1, 0, false)
+}
+
+// paneOf returns the pane showing table, the focused one when not split
+func (e *Editor) paneOf(table *TableView) *pane {
+	if s := e.split; s != nil && s.detail.table == table {
+		return s.detail
+	} else if s != nil && s.master.table == table {
+		return s.master
+	}
+	return e.pane
+}
+
+// withPane runs f on pane p as if it had focus. A pane without focus is left with
+// no open query or timers, which its next load starts again.
+func (e *Editor) withPane(p *pane, f func()) {
+	if p == e.pane {
+		f()
+		return
+	}
+	focused := e.pane
+	e.pane = p
+	f()
+	e.stopRowsTimer()
+	e.stopRefreshTimer()
+	e.pane = focused
+}
+
+// focusPane moves the focus to pane p of the split. A cell being edited is closed
+// first; an insert row or delete keeps the focus where it is.
+func (e *Editor) focusPane(p *pane) {
+	if e.split == nil || p == e.pane {
+		return
+	}
+	if e.editing {
+		e.exitEditMode()
+	}
+	if len(e.insertRow) > 0 || e.paletteMode == PaletteModeDelete {
+		e.SetStatusError("Finish inserting or deleting before switching panes")
+		e.app.SetFocus(e.table)
+		return
+	}
+	e.stopRowsTimer()
+	e.stopRefreshTimer()
+	e.table.SetDimmed(true)
+	e.pane = p
+	e.table.SetDimmed(false)
+	e.app.SetFocus(e.table)
+	e.startRefreshTimer()
+	e.updateStatusWithCellContent()
+}
+
+// switchPane focuses the other pane of the split
+func (e *Editor) switchPane() {
+	if s := e.split; s != nil && e.pane == s.master {
+		e.focusPane(s.detail)
+	} else if s != nil {
+		e.focusPane(s.master)
+	}
+}
+
+// linkFilters returns the conditions on the referencing columns of ref that match
+// the selected row, false when no row is selected or the row can't be referenced
+func (e *Editor) linkFilters(table string, ref dblib.IncomingReference) ([]dblib.QuickFilter, bool) {
+	row, _ := e.table.GetSelection()
+	if e.relation == nil || row < 0 || row >= len(e.table.data) || e.table.data[row].state == RowStateInsert {
+		return nil, false
+	}
+	data := e.table.data[row].data
+	var link []dblib.QuickFilter
+	for col, target := range ref.Columns {
+		i := e.relation.BaseColumnIndex(table, target)
+		if i < 0 {
+			return nil, false
+		}
+		loaded := e.loadedIndex(e.relation.Columns[i].Name)
+		if loaded < 0 || loaded >= len(data) || data[loaded] == nil {
+			return nil, false
+		}
+		link = append(link, dblib.QuickFilter{Column: col, Value: data[loaded]})
+	}
+	sort.Slice(link, func(i, j int) bool { return link[i].Column < link[j].Column })
+	return link, true
+}
+
+// loadDetail loads the detail pane with the rows referencing the master's selected
+// row, or none if no row is selected. Once the detail pane shows another relation
+// it no longer follows.
+func (e *Editor) loadDetail() {
+	s := e.split
+	if s == nil || s.detail.relation != s.relation {
+		return
+	}
+	var link []dblib.QuickFilter
+	linked := false
+	e.withPane(s.master, func() {
+		link, linked = e.linkFilters(s.table, s.ref)
+	})
+
+	e.withPane(s.detail, func() {
+		if linked && len(e.buffer) > 0 && linkEqual(link, e.relation.Link) {
+			return // still the same row
+		}
+		e.relation.Link = link
+		_, col := e.table.GetSelection()
+		if !linked {
+			e.buffer, e.pointer, e.previousRows = []Row{BottomBorderRow}, 0, nil
+			e.renderData()
+			return
+		}
+		if err := e.loadFromRowId(nil, true, col); err != nil {
+			e.SetStatusErrorWithSentry(err)
+			return
+		}
+		e.table.Select(0, col)
+	})
+}
+
+// linkEqual reports whether two links match the same row
+func linkEqual(a, b []dblib.QuickFilter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Column != b[i].Column || !keysEqual([]any{a[i].Value}, []any{b[i].Value}) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+func TestSplit(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	e.layout = tview.NewFlex()
+	if _, err := db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users(id), title TEXT);
+		INSERT INTO posts (id, user_id, title) VALUES (1, 1, 'a'), (2, 2, 'b'), (3, 1, 'c');`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	master := e.pane
+
+	ref := dblib.IncomingReference{Table: "posts", Columns: map[string]string{"user_id": "id"}}
+	e.openSplit(incomingEntry{ref: ref})
+	if e.split == nil {
+		t.Fatalf("Expected a split open")
+	}
+	if e.pane != master || e.layout.GetItemCount() != 4 {
+		t.Fatalf("Expected focus kept on the master with both tables laid out, got %d items", e.layout.GetItemCount())
+	}
+	detail := e.split.detail
+	detailIds := func() []any {
+		t.Helper()
+		var ids []any
+		for _, row := range detail.table.data {
+			if row.data != nil {
+				ids = append(ids, row.data[0])
+			}
+		}
+		return ids
+	}
+
+	// The detail pane shows the posts of the selected user, following the selection
+	if ids := detailIds(); len(ids) != 2 || ids[0] != int64(1) || ids[1] != int64(3) {
+		t.Errorf("Expected posts 1 and 3 of Alice, got %v", ids)
+	}
+	e.table.Select(1, 0)
+	e.loadDetail() // as the master's selection change does
+	if ids := detailIds(); len(ids) != 1 || ids[0] != int64(2) {
+		t.Errorf("Expected post 2 of Bob, got %v", ids)
+	}
+	if detail.query != nil || detail.refreshTimer != nil {
+		t.Errorf("Expected the unfocused detail pane left without a query or timer")
+	}
+
+	// Switching panes, inserts in the detail pane reference the master's row
+	e.switchPane()
+	if e.pane != detail || e.relation.Name != "posts" {
+		t.Fatalf("Expected the detail pane focused")
+	}
+	e.SetupInsertRow()
+	if i := e.relation.ColumnIndex["user_id"]; e.insertRow[i] != int64(2) {
+		t.Errorf("Expected user_id prefilled with 2, got %v", e.insertRow[i])
+	}
+
+	// With an insert pending the focus stays
+	e.switchPane()
+	if e.pane != detail {
+		t.Errorf("Expected the focus kept while inserting")
+	}
+	e.insertRow = nil
+
+	// Closing the split returns to the master alone
+	e.closeSplit()
+	if e.split != nil || e.pane != master || e.layout.GetItemCount() != 3 {
+		t.Errorf("Expected the master alone once closed")
+	}
+}
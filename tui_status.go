@@ -49,6 +49,13 @@ func (e *Editor) updateStatusForEditMode(col int) {
 		}
 	}
 
+	// What the database fills in when an insert leaves the column unset
+	if len(e.insertRow) > 0 {
+		if placeholder := insertPlaceholder(attr); placeholder != "" {
+			parts = append(parts, "DEFAULT "+placeholder+" (Alt+D)")
+		}
+	}
+
 	// Nullability constraint
 	if !attr.Nullable {
 		parts = append(parts, "NOT NULL")
@@ -57,6 +64,11 @@ func (e *Editor) updateStatusForEditMode(col int) {
 		parts = append(parts, "Alt+0 for null")
 	}
 
+	// An empty edit leaves the column unset, so empty text has its own key
+	if len(e.insertRow) > 0 && e.isMultilineColumnType(col) {
+		parts = append(parts, "Alt+E for empty text")
+	}
+
 	// Foreign key reference
 	if attr.Reference >= 0 && attr.Reference < len(e.relation.References) {
 		refTable := e.relation.References[attr.Reference].Table
@@ -156,11 +168,11 @@ func (e *Editor) updateForeignKeyPreview(row, col int, newText string) {
 		}
 		previewStr = strings.Join(previewStrs, ", ")
 		if preview == nil {
-			parts = append(parts, fmt.Sprintf("[blueviolet]→ %s: not found[black]",
-				ref.Table))
+			parts = append(parts, fmt.Sprintf("%s→ %s: not found[-]",
+				colorTag(theme.Missing), ref.Table))
 		} else {
-			parts = append(parts, fmt.Sprintf("[darkgreen]→ %s: %s[black]",
-				ref.Table, previewStr))
+			parts = append(parts, fmt.Sprintf("%s→ %s: %s[-]",
+				colorTag(theme.Value), ref.Table, previewStr))
 		}
 	}
 
@@ -240,7 +252,7 @@ func formatEnumValuesWithHighlight(values []string, currentValue string) string
 		// Highlight if it matches current value
 		var formatted string
 		if val == currentValue {
-			formatted = "[green]'" + displayVal + "'[white]"
+			formatted = colorTag(theme.Value) + "'" + displayVal + "'[-]"
 			foundMatch = true
 		} else {
 			formatted = "'" + displayVal + "'"
@@ -268,9 +280,9 @@ func formatEnumValuesWithHighlight(values []string, currentValue string) string
 			}
 		}
 		if hasPartial {
-			return strings.Join(parts, ", ") + " [yellow](typing...)[white]"
+			return strings.Join(parts, ", ") + " " + colorTag(theme.Pending) + "(typing...)[-]"
 		}
-		return strings.Join(parts, ", ") + " [yellow](invalid)[white]"
+		return strings.Join(parts, ", ") + " " + colorTag(theme.Pending) + "(invalid)[-]"
 	}
 
 	return strings.Join(parts, ", ")
@@ -342,6 +354,12 @@ func (e *Editor) setPaletteMode(mode PaletteMode, focus bool) {
 			modeStr = "Insert"
 		case PaletteModeDelete:
 			modeStr = "Delete"
+		case PaletteModeFilter:
+			modeStr = "Filter"
+		case PaletteModeReplace:
+			modeStr = "Replace"
+		case PaletteModeGoto:
+			modeStr = "Goto"
 		}
 		breadcrumbs.RecordNavigation(modeStr, "Palette mode changed")
 	}
@@ -351,7 +369,14 @@ func (e *Editor) setPaletteMode(mode PaletteMode, focus bool) {
 	isDeleteMode := mode == PaletteModeDelete
 
 	e.paletteMode = mode
-	e.commandPalette.SetLabel(mode.Glyph())
+	if mode != PaletteModeReplace {
+		e.replacePlan = nil
+	}
+	if mode == PaletteModeFind {
+		e.commandPalette.SetLabel(findLabel(e.findOpts))
+	} else {
+		e.commandPalette.SetLabel(mode.Glyph())
+	}
 	// Clear input when switching modes
 	e.commandPalette.SetText("")
 	style := e.commandPalette.GetPlaceholderStyle().Italic(true)
@@ -360,17 +385,17 @@ func (e *Editor) setPaletteMode(mode PaletteMode, focus bool) {
 	// Update table view delete mode state
 	if e.table != nil {
 		e.table.SetDeleteMode(isDeleteMode)
-		e.table.SetFindMode(mode == PaletteModeFind)
+		e.table.SetFindMode(mode == PaletteModeFind || mode == PaletteModeReplace)
 	}
 
 	// Update status bar background color
 	if e.statusBar != nil {
 		if isDeleteMode && !wasDeleteMode {
-			// Entering delete mode: set status bar to red
-			e.statusBar.SetBackgroundColor(tcell.ColorRed)
+			// Entering delete mode: set status bar to the delete colors
+			e.setStatusStyle(theme.Delete)
 		} else if !isDeleteMode && wasDeleteMode {
-			// Exiting delete mode: restore status bar to light gray
-			e.statusBar.SetBackgroundColor(tcell.ColorLightGray)
+			// Exiting delete mode: restore the status bar colors
+			e.setStatusStyle(theme.StatusBar)
 		}
 	}
 	if e.insertRow != nil {
@@ -391,13 +416,17 @@ func (e *Editor) setPaletteMode(mode PaletteMode, focus bool) {
 	} else {
 		switch mode {
 		case PaletteModeDefault:
-			e.commandPalette.SetPlaceholder("Ctrl+… N: New row · `: SQL · F: Find in column · ⌫: Delete row · Q: Exit")
+			e.commandPalette.SetPlaceholder("Ctrl+… N: New row · `: SQL · F: Find in column · W: Filter rows · G: Go to key · ⌫: Delete row · Q: Exit")
 		case PaletteModeCommand:
 			e.commandPalette.SetPlaceholder("Command… (Esc to exit)")
 		case PaletteModeSQL:
 			e.commandPalette.SetPlaceholder("Execute SQL… (Esc to exit)")
 		case PaletteModeFind:
-			e.commandPalette.SetPlaceholder("Find next matching value… (Esc to exit)")
+			e.commandPalette.SetPlaceholder("Find next match… ⇧⏎: Previous · Alt+… M: Mode · C: Case · A: All columns · R: Replace (Esc to exit)")
+		case PaletteModeReplace:
+			e.commandPalette.SetPlaceholder("Replace with… ($1 inserts a regex group) · Enter to preview (Esc to exit)")
+		case PaletteModeFilter:
+			e.commandPalette.SetPlaceholder("Filter rows WHERE… (empty to clear, Esc to exit)")
 		case PaletteModeUpdate:
 			// No placeholder in update mode
 			e.commandPalette.SetPlaceholder("")
@@ -423,7 +452,7 @@ func (e *Editor) SetStatusMessage(message string) {
 
 func (e *Editor) SetStatusError(message string) {
 	if e.statusBar != nil {
-		e.statusBar.SetText("[red]ERROR: " + message + "[white]")
+		e.statusBar.SetText(colorTag(theme.Error) + "ERROR: " + message + "[-]")
 		e.app.Draw()
 	}
 }
@@ -431,7 +460,7 @@ func (e *Editor) SetStatusError(message string) {
 // SetStatusErrorWithSentry sets an error status and sends it to Sentry
 func (e *Editor) SetStatusErrorWithSentry(err error) {
 	if e.statusBar != nil {
-		e.statusBar.SetText("[red]ERROR: " + err.Error() + "[white]")
+		e.statusBar.SetText(colorTag(theme.Error) + "ERROR: " + err.Error() + "[-]")
 		e.app.Draw()
 	}
 	CaptureError(err)
@@ -439,7 +468,7 @@ func (e *Editor) SetStatusErrorWithSentry(err error) {
 
 func (e *Editor) SetStatusLog(message string) {
 	if e.statusBar != nil {
-		e.statusBar.SetText("[blue]LOG: " + message + "[white]")
+		e.statusBar.SetText(colorTag(theme.Info) + "LOG: " + message + "[-]")
 		e.app.Draw()
 	}
 }
@@ -461,8 +490,8 @@ func (e *Editor) updateStatusWithCellContent() {
 
 	// Get the cell value
 	var cellValue any
-	if col < len(e.buffer[row].data) {
-		cellValue = e.buffer[row].data[col]
+	if idx := e.table.dataIndex(col); idx < len(e.buffer[row].data) {
+		cellValue = e.buffer[row].data[idx]
 	}
 
 	// Format the cell value
@@ -470,20 +499,34 @@ func (e *Editor) updateStatusWithCellContent() {
 
 	// Get column name and type info
 	colName := e.table.GetHeaders()[col].Name
-	var colType string
+	var colType, refTable string
 	if e.relation != nil {
 		if colIdx, ok := e.relation.ColumnIndex[colName]; ok && colIdx < len(e.relation.Columns) {
 			colType = e.relation.Columns[colIdx].Type
+			if ref := e.relation.Columns[colIdx].Reference; ref >= 0 && ref < len(e.relation.References) {
+				refTable = e.relation.References[ref].Table
+			}
 		}
 	}
 
 	// Build the status message with explicit colors
 	var statusMsg string
 	if colType != "" {
-		statusMsg = fmt.Sprintf("[black]%s[darkgreen] %s", colType, cellText)
+		statusMsg = fmt.Sprintf("%s%s %s", colType, colorTag(theme.Value), cellText)
 	} else {
-		statusMsg = fmt.Sprintf("[darkgreen]%s", cellText)
+		statusMsg = fmt.Sprintf("%s%s", colorTag(theme.Value), cellText)
+	}
+	if refTable != "" && cellValue != nil {
+		statusMsg += fmt.Sprintf(" [-]· Ctrl+] → %s", refTable)
 	}
 
 	e.SetStatusMessage(statusMsg)
 }
+
+// setStatusStyle colors the status bar with style
+func (e *Editor) setStatusStyle(style tcell.Style) {
+	fg, bg, _ := style.Decompose()
+	e.statusBar.SetBackgroundColor(bg)
+	e.statusBar.SetTextColor(fg)
+	e.statusBar.SetTextStyle(style)
+}
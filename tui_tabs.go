@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+
+	"ted/internal/dblib"
+)
+
+// tab is an open relation with its own rows, selection, filters and sort, or a
+// split of two. Tabs not shown hold no query or timers and aren't refreshed until
+// shown again.
+type tab struct {
+	pane  *pane      // pane focused when the tab was last shown
+	split *splitView // split open in the tab, if any
+}
+
+// openTab shows relation in a new tab, or switches to the tab already showing it.
+// An editor with no relation open shows it in place.
+func (e *Editor) openTab(relation *dblib.Relation, displayName string) {
+	if len(e.tabs) == 0 {
+		e.tabs = []*tab{{pane: e.pane, split: e.split}}
+	}
+	if e.relation != nil {
+		for i := range e.tabs {
+			if e.tabTable(i).tableName == displayName {
+				e.switchTab(i)
+				return
+			}
+		}
+		if !e.canNavigate() {
+			return
+		}
+		// The new table takes the room of the tables shown, split or not
+		rows, height := e.table.rowsHeight, e.table.drawnHeight
+		if s := e.split; s != nil {
+			height = s.master.table.drawnHeight + s.detail.table.drawnHeight
+		}
+		if height > 0 {
+			rows = e.table.fittingRows(height)
+		}
+		e.leaveTab()
+		e.pane, e.split = &pane{}, nil
+		e.table = e.newTable(rows, nil)
+		e.tabs = append(e.tabs, &tab{pane: e.pane})
+		e.tabIndex = len(e.tabs) - 1
+		e.setupKeyBindings()
+		e.layoutTables()
+	}
+
+	e.setRelation(relation, displayName)
+
+	// Load from the beginning with the layout it was last shown with
+	if err := e.restoreLayout(); err != nil {
+		e.SetStatusErrorWithSentry(err)
+	}
+	e.renderData()
+	e.table.Select(0, 0)
+	e.app.SetFocus(e.table)
+}
+
+// switchTab shows tab i, refreshing its rows from where they were left
+func (e *Editor) switchTab(i int) {
+	if i == e.tabIndex || i < 0 || i >= len(e.tabs) || !e.canNavigate() {
+		return
+	}
+	e.leaveTab()
+	e.showTab(i)
+}
+
+// nextTab shows the tab delta places after the current one, wrapping around
+func (e *Editor) nextTab(delta int) {
+	if len(e.tabs) < 2 {
+		return
+	}
+	e.switchTab(((e.tabIndex+delta)%len(e.tabs) + len(e.tabs)) % len(e.tabs))
+}
+
+// closeTab closes the current tab, showing the one before it
+func (e *Editor) closeTab() {
+	if len(e.tabs) < 2 {
+		e.SetStatusMessage("Only one tab is open")
+		return
+	}
+	if !e.canNavigate() {
+		return
+	}
+	e.leaveTab()
+	e.tabs = append(e.tabs[:e.tabIndex], e.tabs[e.tabIndex+1:]...)
+	e.showTab(max(e.tabIndex-1, 0))
+}
+
+// leaveTab keeps the current tab's panes to show again later, closing its query and
+// saving its layout
+func (e *Editor) leaveTab() {
+	e.stopRowsTimer()
+	e.stopRefreshTimer()
+	e.saveLayout()
+	if s := e.split; s != nil {
+		for _, p := range []*pane{s.master, s.detail} {
+			e.withPane(p, e.saveLayout)
+		}
+	}
+	e.tabs[e.tabIndex].pane, e.tabs[e.tabIndex].split = e.pane, e.split
+}
+
+// showTab makes tab i current, refreshing the rows of its panes
+func (e *Editor) showTab(i int) {
+	e.tabIndex = i
+	e.pane, e.split = e.tabs[i].pane, e.tabs[i].split
+	e.layoutTables()
+	e.updateTabs()
+	e.updateTitle()
+	if s := e.split; s != nil {
+		for _, p := range []*pane{s.master, s.detail} {
+			if p != e.pane {
+				e.withPane(p, e.refreshPane)
+			}
+		}
+	}
+	e.refreshPane()
+	e.app.SetFocus(e.table)
+	e.updateStatusWithCellContent()
+}
+
+// refreshPane reloads the pane's rows from the first one shown
+func (e *Editor) refreshPane() {
+	if e.relation == nil {
+		return
+	}
+	if err := e.refresh(); err != nil {
+		e.SetStatusErrorWithSentry(err)
+	}
+}
+
+// tabTable returns the top table of tab i
+func (e *Editor) tabTable(i int) *TableView {
+	p, s := e.tabs[i].pane, e.tabs[i].split
+	if i == e.tabIndex {
+		p, s = e.pane, e.split
+	}
+	if s != nil {
+		return s.master.table
+	}
+	return p.table
+}
+
+// updateTabs shows the open tabs in the header of the current tab's top table
+func (e *Editor) updateTabs() {
+	if len(e.tabs) == 0 {
+		return
+	}
+	names := make([]string, len(e.tabs))
+	for i := range e.tabs {
+		names[i] = e.tabTable(i).tableName
+	}
+	e.tabTable(e.tabIndex).SetTabs(names, e.tabIndex)
+}
+
+// updateTitle names the focused relation in the terminal title
+func (e *Editor) updateTitle() {
+	if e.relation == nil || e.app == nil {
+		return
+	}
+	if e.relation.IsCustomSQL {
+		e.app.SetTitle(fmt.Sprintf("ted %s/[SQL Query] %s", e.config.Database, databaseIcons[e.relation.DBType]))
+	} else {
+		e.app.SetTitle(fmt.Sprintf("ted %s/%s %s", e.config.Database, e.table.tableName, databaseIcons[e.relation.DBType]))
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"ted/internal/dblib"
+)
+
+func TestTabs(t *testing.T) {
+	e, db := newDryRunEditor(t)
+	e.statusBar = nil
+	e.layout = tview.NewFlex()
+	if _, err := db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT);
+		INSERT INTO posts (id, title) VALUES (1, 'a'), (2, 'b');`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := e.loadFromRowId(nil, true, 0); err != nil {
+		t.Fatalf("loadFromRowId failed: %v", err)
+	}
+	e.table.Select(1, 1)
+	e.addQuickFilter(1, 1, false)
+	users := e.pane
+
+	posts, err := dblib.NewRelation(db, dblib.SQLite, "posts")
+	if err != nil {
+		t.Fatalf("NewRelation failed: %v", err)
+	}
+	e.table.drawnHeight = 12 // as last drawn, with the table name and borders
+	e.openTab(posts, "posts")
+	if len(e.tabs) != 2 || e.tabIndex != 1 || e.relation != posts {
+		t.Fatalf("Expected posts open in a second tab, got %d tabs", len(e.tabs))
+	}
+	if e.table.rowsHeight != 8 {
+		t.Errorf("Expected the new table sized to the one shown, got %d rows", e.table.rowsHeight)
+	}
+	if e.table.tabs[0] != "users" || e.table.tabs[1] != "posts" || e.table.activeTab != 1 {
+		t.Errorf("Expected the tab strip to list users and posts, got %v", e.table.tabs)
+	}
+	if users.query != nil || users.refreshTimer != nil {
+		t.Errorf("Expected the background tab left without a query or timer")
+	}
+	if len(e.relation.QuickFilters) != 0 {
+		t.Errorf("Expected the new tab unfiltered")
+	}
+
+	// Back on the first tab, its filter and selection are as they were
+	e.nextTab(1)
+	if e.pane != users || e.tabIndex != 0 {
+		t.Fatalf("Expected the users tab shown")
+	}
+	if len(e.relation.QuickFilters) != 1 {
+		t.Errorf("Expected the quick filter kept, got %v", e.relation.QuickFilters)
+	}
+	if row, col := e.table.GetSelection(); col != 1 || e.buffer[(row+e.pointer)%len(e.buffer)].data[1] != "Bob" {
+		t.Errorf("Expected Bob still selected in the name column, got %d, %d", row, col)
+	}
+
+	// Picking a table already open switches to its tab
+	e.openTab(posts, "posts")
+	if len(e.tabs) != 2 || e.tabIndex != 1 {
+		t.Errorf("Expected the posts tab reused, got %d tabs", len(e.tabs))
+	}
+
+	e.closeTab()
+	if len(e.tabs) != 1 || e.pane != users {
+		t.Errorf("Expected the users tab alone once posts is closed")
+	}
+}